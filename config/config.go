@@ -5,10 +5,20 @@ package config // import "go.opentelemetry.io/contrib/config"
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
+	"fmt"
+	"os"
+	"time"
 
+	"go.opentelemetry.io/otel/log"
+	lognoop "go.opentelemetry.io/otel/log/noop"
 	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
 )
 
 const (
@@ -22,6 +32,10 @@ const (
 type configOptions struct {
 	ctx                 context.Context
 	opentelemetryConfig OpenTelemetryConfiguration
+	reloadInterval      *time.Duration
+	reloadGracePeriod   *time.Duration
+	inFlightSpans       *inFlightSpans
+	prometheusServers   *prometheusServerRegistry
 }
 
 type shutdownFunc func(context.Context) error
@@ -30,11 +44,63 @@ func noopShutdown(context.Context) error {
 	return nil
 }
 
+// limit returns the configured limit v, or the general attribute limit
+// fallback if v is not set. An error is returned if either value is
+// negative.
+func limit(name string, v, fallback *int) (*int, error) {
+	if fallback != nil && *fallback < 0 {
+		return nil, fmt.Errorf("attribute_limits: invalid %s %d", name, *fallback)
+	}
+	if v == nil {
+		return fallback, nil
+	}
+	if *v < 0 {
+		return nil, fmt.Errorf("invalid %s %d", name, *v)
+	}
+	return v, nil
+}
+
+// tlsConfig returns the TLS configuration of an exporter from the paths to
+// PEM encoded files of the certificate authority used to verify the server
+// certificate, and of the client certificate and key used for mTLS. A nil
+// config is returned if none of the files is set.
+func tlsConfig(certificate, clientCertificate, clientKey *string) (*tls.Config, error) {
+	if certificate == nil && clientCertificate == nil && clientKey == nil {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{}
+	if certificate != nil {
+		b, err := os.ReadFile(*certificate)
+		if err != nil {
+			return nil, fmt.Errorf("could not read certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if ok := pool.AppendCertsFromPEM(b); !ok {
+			return nil, fmt.Errorf("could not create certificate authority chain from certificate %q", *certificate)
+		}
+		cfg.RootCAs = pool
+	}
+	if clientCertificate != nil || clientKey != nil {
+		if clientCertificate == nil || clientKey == nil {
+			return nil, errors.New("client_certificate and client_key must both be specified")
+		}
+		cert, err := tls.LoadX509KeyPair(*clientCertificate, *clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not use client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
 // SDK is a struct that contains all the providers
 // configured via the configuration model.
 type SDK struct {
 	meterProvider  metric.MeterProvider
 	tracerProvider trace.TracerProvider
+	loggerProvider log.LoggerProvider
+	propagator     propagation.TextMapPropagator
 	shutdown       shutdownFunc
 }
 
@@ -48,6 +114,16 @@ func (s *SDK) MeterProvider() metric.MeterProvider {
 	return s.meterProvider
 }
 
+// LoggerProvider returns a configured log.LoggerProvider.
+func (s *SDK) LoggerProvider() log.LoggerProvider {
+	return s.loggerProvider
+}
+
+// TextMapPropagator returns a configured propagation.TextMapPropagator.
+func (s *SDK) TextMapPropagator() propagation.TextMapPropagator {
+	return s.propagator
+}
+
 // Shutdown calls shutdown on all configured providers.
 func (s *SDK) Shutdown(ctx context.Context) error {
 	return s.shutdown(ctx)
@@ -55,30 +131,62 @@ func (s *SDK) Shutdown(ctx context.Context) error {
 
 // NewSDK creates SDK providers based on the configuration model.
 //
-// Caution: The implementation only returns noop providers.
+// Unless the SDK is disabled, the configuration model is checked with
+// Validate before any provider is created.
 func NewSDK(opts ...ConfigurationOption) (SDK, error) {
-	o := configOptions{}
+	o := configOptions{
+		ctx: context.Background(),
+	}
 	for _, opt := range opts {
 		o = opt.apply(o)
 	}
 
-	r, err := newResource(o.opentelemetryConfig.Resource)
+	if o.opentelemetryConfig.Disabled != nil && *o.opentelemetryConfig.Disabled {
+		return SDK{
+			meterProvider:  metricnoop.NewMeterProvider(),
+			tracerProvider: tracenoop.NewTracerProvider(),
+			loggerProvider: lognoop.NewLoggerProvider(),
+			propagator:     propagation.NewCompositeTextMapPropagator(),
+			shutdown:       noopShutdown,
+		}, nil
+	}
+
+	if err := Validate(o.opentelemetryConfig); err != nil {
+		return SDK{}, err
+	}
+
+	r, err := newResource(o.ctx, o.opentelemetryConfig.Resource)
 	if err != nil {
 		return SDK{}, err
 	}
 
-	mp, mpShutdown := initMeterProvider(o)
-	tp, tpShutdown, err := tracerProvider(o, r)
+	p, err := propagator(o)
+	if err != nil {
+		return SDK{}, err
+	}
+
+	mp, mpShutdown, err := meterProvider(o, r)
 	if err != nil {
 		return SDK{}, err
 	}
 
+	tp, tpShutdown, err := tracerProvider(o, r)
+	if err != nil {
+		return SDK{}, errors.Join(err, mpShutdown(o.ctx))
+	}
+
+	lp, lpShutdown, err := loggerProvider(o, r)
+	if err != nil {
+		return SDK{}, errors.Join(err, mpShutdown(o.ctx), tpShutdown(o.ctx))
+	}
+
 	return SDK{
 		meterProvider:  mp,
 		tracerProvider: tp,
+		loggerProvider: lp,
+		propagator:     p,
 		shutdown: func(ctx context.Context) error {
-			err := mpShutdown(ctx)
-			return errors.Join(err, tpShutdown(ctx))
+			return errors.Join(mpShutdown(ctx), tpShutdown(ctx), lpShutdown(ctx))
 		},
 	}, nil
 }
@@ -111,9 +219,5 @@ func WithOpenTelemetryConfiguration(cfg OpenTelemetryConfiguration) Configuratio
 	})
 }
 
-// TODO: implement parsing functionality:
-// - https://github.com/open-telemetry/opentelemetry-go-contrib/issues/4373
-// - https://github.com/open-telemetry/opentelemetry-go-contrib/issues/4412
-
 // TODO: create SDK from the model:
 // - https://github.com/open-telemetry/opentelemetry-go-contrib/issues/4371
@@ -5,12 +5,25 @@ package config
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	lognoop "go.opentelemetry.io/otel/log/noop"
 	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	tracenoop "go.opentelemetry.io/otel/trace/noop"
@@ -22,6 +35,8 @@ func TestNewSDK(t *testing.T) {
 		cfg                []ConfigurationOption
 		wantTracerProvider any
 		wantMeterProvider  any
+		wantLoggerProvider any
+		wantPropagator     any
 		wantErr            error
 		wantShutdownErr    error
 	}{
@@ -29,6 +44,8 @@ func TestNewSDK(t *testing.T) {
 			name:               "no-configuration",
 			wantTracerProvider: tracenoop.NewTracerProvider(),
 			wantMeterProvider:  metricnoop.NewMeterProvider(),
+			wantLoggerProvider: lognoop.NewLoggerProvider(),
+			wantPropagator:     propagation.NewCompositeTextMapPropagator(),
 		},
 		{
 			name: "with-configuration",
@@ -37,21 +54,179 @@ func TestNewSDK(t *testing.T) {
 				WithOpenTelemetryConfiguration(OpenTelemetryConfiguration{
 					TracerProvider: &TracerProvider{},
 					MeterProvider:  &MeterProvider{},
+					LoggerProvider: &LoggerProvider{},
+					Propagator: &Propagator{
+						Composite: []string{"tracecontext", "baggage"},
+					},
 				}),
 			},
 			wantTracerProvider: &sdktrace.TracerProvider{},
 			wantMeterProvider:  &sdkmetric.MeterProvider{},
+			wantLoggerProvider: &sdklog.LoggerProvider{},
+			wantPropagator:     propagation.NewCompositeTextMapPropagator(),
+		},
+		{
+			name: "disabled",
+			cfg: []ConfigurationOption{
+				WithOpenTelemetryConfiguration(OpenTelemetryConfiguration{
+					Disabled:       ptr(true),
+					TracerProvider: &TracerProvider{},
+					MeterProvider:  &MeterProvider{},
+					LoggerProvider: &LoggerProvider{},
+					Propagator: &Propagator{
+						Composite: []string{"tracecontext"},
+					},
+				}),
+			},
+			wantTracerProvider: tracenoop.NewTracerProvider(),
+			wantMeterProvider:  metricnoop.NewMeterProvider(),
+			wantLoggerProvider: lognoop.NewLoggerProvider(),
+			wantPropagator:     propagation.NewCompositeTextMapPropagator(),
+		},
+		{
+			name: "invalid-propagator",
+			cfg: []ConfigurationOption{
+				WithOpenTelemetryConfiguration(OpenTelemetryConfiguration{
+					Propagator: &Propagator{
+						Composite: []string{"invalid"},
+					},
+				}),
+			},
+			wantErr: &ValidationError{Path: "propagator.composite[0]", Value: "invalid", Reason: "unknown propagator: invalid"},
 		},
 	}
 	for _, tt := range tests {
 		sdk, err := NewSDK(tt.cfg...)
-		require.Equal(t, tt.wantErr, err)
+		if tt.wantErr != nil {
+			require.EqualError(t, err, tt.wantErr.Error())
+			continue
+		}
+		require.NoError(t, err)
 		assert.IsType(t, tt.wantTracerProvider, sdk.TracerProvider())
 		assert.IsType(t, tt.wantMeterProvider, sdk.MeterProvider())
+		assert.IsType(t, tt.wantLoggerProvider, sdk.LoggerProvider())
+		assert.IsType(t, tt.wantPropagator, sdk.TextMapPropagator())
 		require.Equal(t, tt.wantShutdownErr, sdk.Shutdown(context.Background()))
 	}
 }
 
+func TestTLSConfig(t *testing.T) {
+	ca, cert, key := writeTLSFiles(t)
+	invalid := filepath.Join(t.TempDir(), "invalid.pem")
+	require.NoError(t, os.WriteFile(invalid, []byte("invalid"), 0o600))
+
+	for _, tt := range []struct {
+		name              string
+		certificate       *string
+		clientCertificate *string
+		clientKey         *string
+		wantNil           bool
+		wantRootCAs       bool
+		wantCertificates  int
+		wantErr           string
+	}{
+		{
+			name:    "no-tls",
+			wantNil: true,
+		},
+		{
+			name:        "certificate",
+			certificate: ptr(ca),
+			wantRootCAs: true,
+		},
+		{
+			name:              "mtls",
+			certificate:       ptr(ca),
+			clientCertificate: ptr(cert),
+			clientKey:         ptr(key),
+			wantRootCAs:       true,
+			wantCertificates:  1,
+		},
+		{
+			name:        "missing-certificate",
+			certificate: ptr(filepath.Join(t.TempDir(), "missing.pem")),
+			wantErr:     "could not read certificate",
+		},
+		{
+			name:        "invalid-certificate",
+			certificate: ptr(invalid),
+			wantErr:     "could not create certificate authority chain from certificate",
+		},
+		{
+			name:              "client-certificate-without-key",
+			clientCertificate: ptr(cert),
+			wantErr:           "client_certificate and client_key must both be specified",
+		},
+		{
+			name:              "invalid-client-key",
+			clientCertificate: ptr(cert),
+			clientKey:         ptr(invalid),
+			wantErr:           "could not use client certificate",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tlsConfig(tt.certificate, tt.clientCertificate, tt.clientKey)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantNil {
+				require.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			assert.Equal(t, tt.wantRootCAs, got.RootCAs != nil)
+			assert.Len(t, got.Certificates, tt.wantCertificates)
+		})
+	}
+}
+
+// writeTLSFiles writes a self-signed certificate authority, and a client
+// certificate and key issued by it, as PEM files to a temporary directory.
+func writeTLSFiles(t *testing.T) (ca, cert, key string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	clientTmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, clientTmpl, caTmpl, &clientKey.PublicKey, caKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(clientKey)
+	require.NoError(t, err)
+
+	write := func(name, typ string, der []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600))
+		return path
+	}
+	return write("ca.pem", "CERTIFICATE", caDER),
+		write("client.pem", "CERTIFICATE", clientDER),
+		write("client-key.pem", "EC PRIVATE KEY", keyDER)
+}
+
 func ptr[T any](v T) *T {
 	return &v
 }
@@ -0,0 +1,510 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package config // import "go.opentelemetry.io/contrib/config"
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	envExporterNone       = "none"
+	envExporterOTLP       = "otlp"
+	envExporterConsole    = "console"
+	envExporterZipkin     = "zipkin"
+	envExporterPrometheus = "prometheus"
+
+	envProtocolGRPC = "grpc"
+
+	defaultOTLPHTTPEndpoint = "http://localhost:4318"
+	defaultOTLPGRPCEndpoint = "http://localhost:4317"
+	defaultZipkinEndpoint   = "http://localhost:9411/api/v2/spans"
+	defaultPrometheusHost   = "localhost"
+	defaultPrometheusPort   = 9464
+)
+
+// FromEnvironment returns the OpenTelemetryConfiguration equivalent to the
+// SDK configuration environment variables (e.g. OTEL_TRACES_EXPORTER,
+// OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_TRACES_SAMPLER) of the current
+// process.
+//
+// Unset variables default to the values defined by the OpenTelemetry
+// specification, so the returned configuration can be passed to NewSDK to
+// configure the same SDK an environment variable based setup would. An error
+// is returned for any variable with an invalid value.
+func FromEnvironment() (*OpenTelemetryConfiguration, error) {
+	var r envReader
+	cfg := &OpenTelemetryConfiguration{
+		FileFormat: supportedFileFormats[len(supportedFileFormats)-1],
+		Disabled:   r.bool("OTEL_SDK_DISABLED"),
+		Resource:   r.resource(),
+		Propagator: &Propagator{
+			Composite: r.list("OTEL_PROPAGATORS", "tracecontext,baggage"),
+		},
+		TracerProvider: r.tracerProvider(),
+		MeterProvider:  r.meterProvider(),
+		LoggerProvider: r.loggerProvider(),
+	}
+	if count, length := r.int("OTEL_ATTRIBUTE_COUNT_LIMIT"), r.int("OTEL_ATTRIBUTE_VALUE_LENGTH_LIMIT"); count != nil || length != nil {
+		cfg.AttributeLimits = &AttributeLimits{
+			AttributeCountLimit:       count,
+			AttributeValueLengthLimit: length,
+		}
+	}
+	if len(r.errs) > 0 {
+		return nil, errors.Join(r.errs...)
+	}
+	return cfg, nil
+}
+
+// envReader reads environment variables and collects the errors of invalid
+// values.
+type envReader struct {
+	errs []error
+}
+
+func (r *envReader) errorf(key, value string, err error) {
+	r.errs = append(r.errs, fmt.Errorf("invalid %s value %q: %w", key, value, err))
+}
+
+// str returns the value of the environment variable key, or nil if it is
+// unset or empty.
+func (r *envReader) str(key string) *string {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		return &v
+	}
+	return nil
+}
+
+func (r *envReader) int(key string) *int {
+	v := r.str(key)
+	if v == nil {
+		return nil
+	}
+	i, err := strconv.Atoi(*v)
+	if err != nil {
+		r.errorf(key, *v, err)
+		return nil
+	}
+	return &i
+}
+
+func (r *envReader) float(key string) *float64 {
+	v := r.str(key)
+	if v == nil {
+		return nil
+	}
+	f, err := strconv.ParseFloat(*v, 64)
+	if err != nil {
+		r.errorf(key, *v, err)
+		return nil
+	}
+	return &f
+}
+
+func (r *envReader) bool(key string) *bool {
+	v := r.str(key)
+	if v == nil {
+		return nil
+	}
+	b, err := strconv.ParseBool(*v)
+	if err != nil {
+		r.errorf(key, *v, err)
+		return nil
+	}
+	return &b
+}
+
+// list returns the comma-separated values of the environment variable key,
+// or of def if it is unset.
+func (r *envReader) list(key, def string) []string {
+	v := def
+	if s := r.str(key); s != nil {
+		v = *s
+	}
+	var values []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// keyValues returns the comma-separated, URL encoded key=value pairs of the
+// environment variable key.
+func (r *envReader) keyValues(key string) map[string]string {
+	v := r.str(key)
+	if v == nil {
+		return nil
+	}
+	kvs := make(map[string]string)
+	for _, pair := range strings.Split(*v, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		k, val, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			r.errorf(key, *v, fmt.Errorf("missing key in %q", pair))
+			return nil
+		}
+		decoded, err := url.PathUnescape(strings.TrimSpace(val))
+		if err != nil {
+			r.errorf(key, *v, err)
+			return nil
+		}
+		kvs[strings.TrimSpace(k)] = decoded
+	}
+	return kvs
+}
+
+func (r *envReader) resource() *Resource {
+	attrs := &Attributes{
+		ServiceName: r.str("OTEL_SERVICE_NAME"),
+	}
+	for k, v := range r.keyValues("OTEL_RESOURCE_ATTRIBUTES") {
+		if k == "service.name" {
+			// OTEL_SERVICE_NAME takes precedence over the service.name
+			// resource attribute.
+			if attrs.ServiceName == nil {
+				attrs.ServiceName = &v
+			}
+			continue
+		}
+		if attrs.AdditionalProperties == nil {
+			attrs.AdditionalProperties = make(map[string]interface{})
+		}
+		attrs.AdditionalProperties[k] = v
+	}
+	if attrs.ServiceName == nil && attrs.AdditionalProperties == nil {
+		return nil
+	}
+	return &Resource{Attributes: attrs}
+}
+
+// exporters returns the exporter names of the environment variable key.
+// An empty list is returned if the "none" exporter is used.
+func (r *envReader) exporters(key string, supported ...string) []string {
+	names := r.list(key, envExporterOTLP)
+	var valid []string
+	for _, name := range names {
+		if name == envExporterNone {
+			return nil
+		}
+		if !contains(supported, name) {
+			r.errorf(key, name, fmt.Errorf("supported values: %q", supported))
+			continue
+		}
+		if !contains(valid, name) {
+			valid = append(valid, name)
+		}
+	}
+	return valid
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// otlp returns the OTLP exporter configuration of signal. The signal
+// specific OTEL_EXPORTER_OTLP_<SIGNAL>_* variables take precedence over the
+// generic OTEL_EXPORTER_OTLP_* ones.
+func (r *envReader) otlp(signal, path string) *OTLP {
+	get := func(name string) (string, *string) {
+		key := "OTEL_EXPORTER_OTLP_" + signal + "_" + name
+		if v := r.str(key); v != nil {
+			return key, v
+		}
+		key = "OTEL_EXPORTER_OTLP_" + name
+		return key, r.str(key)
+	}
+
+	cfg := &OTLP{Protocol: protocolProtobufHTTP}
+	if key, v := get("PROTOCOL"); v != nil {
+		switch *v {
+		case protocolProtobufHTTP:
+		case envProtocolGRPC, protocolProtobufGRPC:
+			cfg.Protocol = protocolProtobufGRPC
+		default:
+			r.errorf(key, *v, fmt.Errorf("supported values: %q", []string{protocolProtobufHTTP, envProtocolGRPC}))
+		}
+	}
+
+	signalEndpoint := r.str("OTEL_EXPORTER_OTLP_" + signal + "_ENDPOINT")
+	endpoint := r.str("OTEL_EXPORTER_OTLP_ENDPOINT")
+	switch {
+	case signalEndpoint != nil:
+		// Signal specific endpoints are used as-is.
+		cfg.Endpoint = *signalEndpoint
+	case cfg.Protocol == protocolProtobufGRPC:
+		cfg.Endpoint = defaultOTLPGRPCEndpoint
+		if endpoint != nil {
+			cfg.Endpoint = *endpoint
+		}
+	default:
+		base := defaultOTLPHTTPEndpoint
+		if endpoint != nil {
+			base = *endpoint
+		}
+		cfg.Endpoint = strings.TrimSuffix(base, "/") + path
+	}
+
+	if key, v := get("HEADERS"); v != nil {
+		cfg.Headers = r.keyValues(key)
+	}
+	if key, v := get("COMPRESSION"); v != nil {
+		switch *v {
+		case compressionGzip, compressionNone:
+			cfg.Compression = v
+		default:
+			r.errorf(key, *v, fmt.Errorf("supported values: %q", []string{compressionGzip, compressionNone}))
+		}
+	}
+	if key, v := get("TIMEOUT"); v != nil {
+		cfg.Timeout = r.int(key)
+	}
+	_, cfg.Certificate = get("CERTIFICATE")
+	_, cfg.ClientCertificate = get("CLIENT_CERTIFICATE")
+	_, cfg.ClientKey = get("CLIENT_KEY")
+	return cfg
+}
+
+func (r *envReader) tracerProvider() *TracerProvider {
+	tp := &TracerProvider{
+		Sampler: r.sampler(),
+	}
+
+	limits := &SpanLimits{
+		AttributeCountLimit:       r.int("OTEL_SPAN_ATTRIBUTE_COUNT_LIMIT"),
+		AttributeValueLengthLimit: r.int("OTEL_SPAN_ATTRIBUTE_VALUE_LENGTH_LIMIT"),
+		EventCountLimit:           r.int("OTEL_SPAN_EVENT_COUNT_LIMIT"),
+		EventAttributeCountLimit:  r.int("OTEL_EVENT_ATTRIBUTE_COUNT_LIMIT"),
+		LinkCountLimit:            r.int("OTEL_SPAN_LINK_COUNT_LIMIT"),
+		LinkAttributeCountLimit:   r.int("OTEL_LINK_ATTRIBUTE_COUNT_LIMIT"),
+	}
+	if *limits != (SpanLimits{}) {
+		tp.Limits = limits
+	}
+
+	for _, name := range r.exporters("OTEL_TRACES_EXPORTER", envExporterOTLP, envExporterZipkin, envExporterConsole) {
+		var exporter SpanExporter
+		switch name {
+		case envExporterOTLP:
+			exporter.OTLP = r.otlp("TRACES", "/v1/traces")
+		case envExporterZipkin:
+			exporter.Zipkin = &Zipkin{
+				Endpoint: defaultZipkinEndpoint,
+				Timeout:  r.int("OTEL_EXPORTER_ZIPKIN_TIMEOUT"),
+			}
+			if v := r.str("OTEL_EXPORTER_ZIPKIN_ENDPOINT"); v != nil {
+				exporter.Zipkin.Endpoint = *v
+			}
+		case envExporterConsole:
+			// The console exporter is meant for debugging, export spans as
+			// soon as they end.
+			exporter.Console = Console{}
+			tp.Processors = append(tp.Processors, SpanProcessor{
+				Simple: &SimpleSpanProcessor{Exporter: exporter},
+			})
+			continue
+		}
+		tp.Processors = append(tp.Processors, SpanProcessor{
+			Batch: &BatchSpanProcessor{
+				Exporter:           exporter,
+				ScheduleDelay:      r.int("OTEL_BSP_SCHEDULE_DELAY"),
+				ExportTimeout:      r.int("OTEL_BSP_EXPORT_TIMEOUT"),
+				MaxQueueSize:       r.int("OTEL_BSP_MAX_QUEUE_SIZE"),
+				MaxExportBatchSize: r.int("OTEL_BSP_MAX_EXPORT_BATCH_SIZE"),
+			},
+		})
+	}
+	return tp
+}
+
+func (r *envReader) sampler() *Sampler {
+	const key = "OTEL_TRACES_SAMPLER"
+	name := "parentbased_always_on"
+	if v := r.str(key); v != nil {
+		name = *v
+	}
+
+	root := strings.TrimPrefix(name, "parentbased_")
+	var s *Sampler
+	switch root {
+	case "always_on":
+		s = &Sampler{AlwaysOn: SamplerAlwaysOn{}}
+	case "always_off":
+		s = &Sampler{AlwaysOff: SamplerAlwaysOff{}}
+	case "traceidratio":
+		ratio := r.float("OTEL_TRACES_SAMPLER_ARG")
+		if ratio == nil {
+			ratio = ptrTo(1.0)
+		}
+		s = &Sampler{TraceIDRatioBased: &SamplerTraceIDRatioBased{Ratio: ratio}}
+	case "jaeger_remote":
+		s = &Sampler{JaegerRemote: r.jaegerRemoteSampler()}
+	default:
+		r.errorf(key, name, errors.New("unsupported sampler"))
+		return nil
+	}
+	if root == name {
+		return s
+	}
+	return &Sampler{ParentBased: &SamplerParentBased{Root: s}}
+}
+
+// jaegerRemoteSampler returns the jaeger_remote sampler configured by the
+// endpoint, pollingIntervalMs and initialSamplingRate arguments of
+// OTEL_TRACES_SAMPLER_ARG.
+func (r *envReader) jaegerRemoteSampler() *SamplerJaegerRemote {
+	const key = "OTEL_TRACES_SAMPLER_ARG"
+	s := &SamplerJaegerRemote{}
+	for k, v := range r.keyValues(key) {
+		switch k {
+		case "endpoint":
+			s.Endpoint = ptrTo(v)
+		case "pollingIntervalMs":
+			i, err := strconv.Atoi(v)
+			if err != nil {
+				r.errorf(key, v, err)
+				continue
+			}
+			s.Interval = &i
+		case "initialSamplingRate":
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				r.errorf(key, v, err)
+				continue
+			}
+			s.InitialSampler = &Sampler{
+				TraceIDRatioBased: &SamplerTraceIDRatioBased{Ratio: &f},
+			}
+		default:
+			r.errorf(key, k, errors.New("unsupported jaeger_remote argument"))
+		}
+	}
+	return s
+}
+
+func (r *envReader) meterProvider() *MeterProvider {
+	mp := &MeterProvider{}
+	for _, name := range r.exporters("OTEL_METRICS_EXPORTER", envExporterOTLP, envExporterPrometheus, envExporterConsole) {
+		if name == envExporterPrometheus {
+			prom := &Prometheus{
+				Host: r.str("OTEL_EXPORTER_PROMETHEUS_HOST"),
+				Port: r.int("OTEL_EXPORTER_PROMETHEUS_PORT"),
+			}
+			if prom.Host == nil {
+				prom.Host = ptrTo(defaultPrometheusHost)
+			}
+			if prom.Port == nil {
+				prom.Port = ptrTo(defaultPrometheusPort)
+			}
+			mp.Readers = append(mp.Readers, MetricReader{
+				Pull: &PullMetricReader{
+					Exporter: MetricExporter{Prometheus: prom},
+				},
+			})
+			continue
+		}
+
+		var exporter MetricExporter
+		switch name {
+		case envExporterOTLP:
+			exporter.OTLP = r.otlpMetric()
+		case envExporterConsole:
+			exporter.Console = Console{}
+		}
+		mp.Readers = append(mp.Readers, MetricReader{
+			Periodic: &PeriodicMetricReader{
+				Exporter: exporter,
+				Interval: r.int("OTEL_METRIC_EXPORT_INTERVAL"),
+				Timeout:  r.int("OTEL_METRIC_EXPORT_TIMEOUT"),
+			},
+		})
+	}
+	return mp
+}
+
+func (r *envReader) otlpMetric() *OTLPMetric {
+	otlp := r.otlp("METRICS", "/v1/metrics")
+	cfg := &OTLPMetric{
+		Certificate:       otlp.Certificate,
+		ClientCertificate: otlp.ClientCertificate,
+		ClientKey:         otlp.ClientKey,
+		Compression:       otlp.Compression,
+		Endpoint:          otlp.Endpoint,
+		Headers:           otlp.Headers,
+		Protocol:          otlp.Protocol,
+		Timeout:           otlp.Timeout,
+	}
+
+	const temporalityKey = "OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE"
+	if v := r.str(temporalityKey); v != nil {
+		t := strings.ToLower(*v)
+		if _, err := temporalitySelector(t); err != nil {
+			r.errorf(temporalityKey, *v, err)
+		} else {
+			cfg.TemporalityPreference = &t
+		}
+	}
+
+	const aggregationKey = "OTEL_EXPORTER_OTLP_METRICS_DEFAULT_HISTOGRAM_AGGREGATION"
+	if v := r.str(aggregationKey); v != nil {
+		a := OTLPMetricDefaultHistogramAggregation(strings.ToLower(*v))
+		if _, err := aggregationSelector(a); err != nil {
+			r.errorf(aggregationKey, *v, err)
+		} else {
+			cfg.DefaultHistogramAggregation = &a
+		}
+	}
+	return cfg
+}
+
+func (r *envReader) loggerProvider() *LoggerProvider {
+	lp := &LoggerProvider{}
+
+	limits := &LogRecordLimits{
+		AttributeCountLimit:       r.int("OTEL_LOGRECORD_ATTRIBUTE_COUNT_LIMIT"),
+		AttributeValueLengthLimit: r.int("OTEL_LOGRECORD_ATTRIBUTE_VALUE_LENGTH_LIMIT"),
+	}
+	if *limits != (LogRecordLimits{}) {
+		lp.Limits = limits
+	}
+
+	for _, name := range r.exporters("OTEL_LOGS_EXPORTER", envExporterOTLP, envExporterConsole) {
+		switch name {
+		case envExporterOTLP:
+			lp.Processors = append(lp.Processors, LogRecordProcessor{
+				Batch: &BatchLogRecordProcessor{
+					Exporter:           LogRecordExporter{OTLP: r.otlp("LOGS", "/v1/logs")},
+					ScheduleDelay:      r.int("OTEL_BLRP_SCHEDULE_DELAY"),
+					ExportTimeout:      r.int("OTEL_BLRP_EXPORT_TIMEOUT"),
+					MaxQueueSize:       r.int("OTEL_BLRP_MAX_QUEUE_SIZE"),
+					MaxExportBatchSize: r.int("OTEL_BLRP_MAX_EXPORT_BATCH_SIZE"),
+				},
+			})
+		case envExporterConsole:
+			lp.Processors = append(lp.Processors, LogRecordProcessor{
+				Simple: &SimpleLogRecordProcessor{
+					Exporter: LogRecordExporter{Console: Console{}},
+				},
+			})
+		}
+	}
+	return lp
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
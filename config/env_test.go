@@ -0,0 +1,231 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromEnvironment(t *testing.T) {
+	defaultBatch := func(exporter SpanExporter) []SpanProcessor {
+		return []SpanProcessor{{Batch: &BatchSpanProcessor{Exporter: exporter}}}
+	}
+	defaultMeterProvider := &MeterProvider{
+		Readers: []MetricReader{{
+			Periodic: &PeriodicMetricReader{
+				Exporter: MetricExporter{OTLP: &OTLPMetric{
+					Endpoint: "http://localhost:4318/v1/metrics",
+					Protocol: protocolProtobufHTTP,
+				}},
+			},
+		}},
+	}
+	defaultLoggerProvider := &LoggerProvider{
+		Processors: []LogRecordProcessor{{
+			Batch: &BatchLogRecordProcessor{
+				Exporter: LogRecordExporter{OTLP: &OTLP{
+					Endpoint: "http://localhost:4318/v1/logs",
+					Protocol: protocolProtobufHTTP,
+				}},
+			},
+		}},
+	}
+	defaultSampler := &Sampler{ParentBased: &SamplerParentBased{Root: &Sampler{AlwaysOn: SamplerAlwaysOn{}}}}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    *OpenTelemetryConfiguration
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			want: &OpenTelemetryConfiguration{
+				FileFormat: "0.1",
+				Propagator: &Propagator{Composite: []string{"tracecontext", "baggage"}},
+				TracerProvider: &TracerProvider{
+					Sampler: defaultSampler,
+					Processors: defaultBatch(SpanExporter{OTLP: &OTLP{
+						Endpoint: "http://localhost:4318/v1/traces",
+						Protocol: protocolProtobufHTTP,
+					}}),
+				},
+				MeterProvider:  defaultMeterProvider,
+				LoggerProvider: defaultLoggerProvider,
+			},
+		},
+		{
+			name: "general",
+			env: map[string]string{
+				"OTEL_SDK_DISABLED":                    "true",
+				"OTEL_SERVICE_NAME":                    "svc",
+				"OTEL_RESOURCE_ATTRIBUTES":             "service.name=ignored,deployment.environment=prod,team=a%20b",
+				"OTEL_PROPAGATORS":                     "b3, jaeger",
+				"OTEL_ATTRIBUTE_COUNT_LIMIT":           "10",
+				"OTEL_ATTRIBUTE_VALUE_LENGTH_LIMIT":    "20",
+				"OTEL_SPAN_EVENT_COUNT_LIMIT":          "30",
+				"OTEL_LINK_ATTRIBUTE_COUNT_LIMIT":      "40",
+				"OTEL_LOGRECORD_ATTRIBUTE_COUNT_LIMIT": "50",
+				"OTEL_TRACES_SAMPLER":                  "traceidratio",
+				"OTEL_TRACES_SAMPLER_ARG":              "0.25",
+				"OTEL_TRACES_EXPORTER":                 "none",
+				"OTEL_METRICS_EXPORTER":                "none",
+				"OTEL_LOGS_EXPORTER":                   "none",
+			},
+			want: &OpenTelemetryConfiguration{
+				FileFormat: "0.1",
+				Disabled:   ptr(true),
+				Resource: &Resource{Attributes: &Attributes{
+					ServiceName: ptr("svc"),
+					AdditionalProperties: map[string]interface{}{
+						"deployment.environment": "prod",
+						"team":                   "a b",
+					},
+				}},
+				AttributeLimits: &AttributeLimits{
+					AttributeCountLimit:       ptr(10),
+					AttributeValueLengthLimit: ptr(20),
+				},
+				Propagator: &Propagator{Composite: []string{"b3", "jaeger"}},
+				TracerProvider: &TracerProvider{
+					Sampler: &Sampler{TraceIDRatioBased: &SamplerTraceIDRatioBased{Ratio: ptr(0.25)}},
+					Limits: &SpanLimits{
+						EventCountLimit:         ptr(30),
+						LinkAttributeCountLimit: ptr(40),
+					},
+				},
+				MeterProvider: &MeterProvider{},
+				LoggerProvider: &LoggerProvider{
+					Limits: &LogRecordLimits{AttributeCountLimit: ptr(50)},
+				},
+			},
+		},
+		{
+			name: "exporters",
+			env: map[string]string{
+				"OTEL_TRACES_SAMPLER":                               "parentbased_jaeger_remote",
+				"OTEL_TRACES_SAMPLER_ARG":                           "endpoint=http://localhost:14250,pollingIntervalMs=5000,initialSamplingRate=0.5",
+				"OTEL_TRACES_EXPORTER":                              "zipkin,console",
+				"OTEL_EXPORTER_ZIPKIN_TIMEOUT":                      "1000",
+				"OTEL_BSP_MAX_QUEUE_SIZE":                           "100",
+				"OTEL_METRICS_EXPORTER":                             "prometheus,otlp",
+				"OTEL_EXPORTER_PROMETHEUS_PORT":                     "9000",
+				"OTEL_METRIC_EXPORT_INTERVAL":                       "1000",
+				"OTEL_EXPORTER_OTLP_PROTOCOL":                       "grpc",
+				"OTEL_EXPORTER_OTLP_ENDPOINT":                       "https://collector:4317",
+				"OTEL_EXPORTER_OTLP_HEADERS":                        "api-key=secret",
+				"OTEL_EXPORTER_OTLP_METRICS_COMPRESSION":            "gzip",
+				"OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE": "Delta",
+				"OTEL_LOGS_EXPORTER":                                "otlp,console",
+				"OTEL_EXPORTER_OTLP_LOGS_PROTOCOL":                  "http/protobuf",
+				"OTEL_EXPORTER_OTLP_LOGS_ENDPOINT":                  "https://logs:4318/custom",
+				"OTEL_BLRP_SCHEDULE_DELAY":                          "200",
+			},
+			want: &OpenTelemetryConfiguration{
+				FileFormat: "0.1",
+				Propagator: &Propagator{Composite: []string{"tracecontext", "baggage"}},
+				TracerProvider: &TracerProvider{
+					Sampler: &Sampler{ParentBased: &SamplerParentBased{Root: &Sampler{
+						JaegerRemote: &SamplerJaegerRemote{
+							Endpoint: ptr("http://localhost:14250"),
+							Interval: ptr(5000),
+							InitialSampler: &Sampler{
+								TraceIDRatioBased: &SamplerTraceIDRatioBased{Ratio: ptr(0.5)},
+							},
+						},
+					}}},
+					Processors: []SpanProcessor{
+						{Batch: &BatchSpanProcessor{
+							MaxQueueSize: ptr(100),
+							Exporter: SpanExporter{Zipkin: &Zipkin{
+								Endpoint: "http://localhost:9411/api/v2/spans",
+								Timeout:  ptr(1000),
+							}},
+						}},
+						{Simple: &SimpleSpanProcessor{Exporter: SpanExporter{Console: Console{}}}},
+					},
+				},
+				MeterProvider: &MeterProvider{
+					Readers: []MetricReader{
+						{Pull: &PullMetricReader{Exporter: MetricExporter{Prometheus: &Prometheus{
+							Host: ptr("localhost"),
+							Port: ptr(9000),
+						}}}},
+						{Periodic: &PeriodicMetricReader{
+							Interval: ptr(1000),
+							Exporter: MetricExporter{OTLP: &OTLPMetric{
+								Endpoint:              "https://collector:4317",
+								Protocol:              protocolProtobufGRPC,
+								Headers:               Headers{"api-key": "secret"},
+								Compression:           ptr("gzip"),
+								TemporalityPreference: ptr("delta"),
+							}},
+						}},
+					},
+				},
+				LoggerProvider: &LoggerProvider{
+					Processors: []LogRecordProcessor{
+						{Batch: &BatchLogRecordProcessor{
+							ScheduleDelay: ptr(200),
+							Exporter: LogRecordExporter{OTLP: &OTLP{
+								Endpoint: "https://logs:4318/custom",
+								Protocol: protocolProtobufHTTP,
+								Headers:  Headers{"api-key": "secret"},
+							}},
+						}},
+						{Simple: &SimpleLogRecordProcessor{Exporter: LogRecordExporter{Console: Console{}}}},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid",
+			env: map[string]string{
+				"OTEL_SDK_DISABLED":           "maybe",
+				"OTEL_BSP_MAX_QUEUE_SIZE":     "many",
+				"OTEL_TRACES_SAMPLER":         "unknown",
+				"OTEL_METRICS_EXPORTER":       "jaeger",
+				"OTEL_EXPORTER_OTLP_PROTOCOL": "http/json",
+			},
+			wantErr: `invalid OTEL_SDK_DISABLED value "maybe"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			got, err := FromEnvironment()
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFromEnvironmentNewSDK(t *testing.T) {
+	t.Setenv("OTEL_TRACES_EXPORTER", "console")
+	t.Setenv("OTEL_METRICS_EXPORTER", "console")
+	t.Setenv("OTEL_LOGS_EXPORTER", "console")
+	cfg, err := FromEnvironment()
+	require.NoError(t, err)
+
+	b, err := MarshalYAML(cfg)
+	require.NoError(t, err)
+	parsed, err := ParseYAML(b)
+	require.NoError(t, err)
+	assert.Equal(t, cfg, parsed)
+
+	sdk, err := NewSDK(WithOpenTelemetryConfiguration(*parsed))
+	require.NoError(t, err)
+	require.NoError(t, sdk.Shutdown(context.Background()))
+}
@@ -0,0 +1,445 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/noop"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestLoggerProvider(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          configOptions
+		wantProvider log.LoggerProvider
+		wantErr      error
+	}{
+		{
+			name:         "no-logger-provider-configured",
+			wantProvider: noop.NewLoggerProvider(),
+		},
+		{
+			name: "logger-provider-configured",
+			cfg: configOptions{
+				opentelemetryConfig: OpenTelemetryConfiguration{
+					LoggerProvider: &LoggerProvider{
+						Limits: &LogRecordLimits{
+							AttributeCountLimit:       ptr(10),
+							AttributeValueLengthLimit: ptr(100),
+						},
+						Processors: []LogRecordProcessor{
+							{
+								Simple: &SimpleLogRecordProcessor{
+									Exporter: LogRecordExporter{
+										Console: Console{},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantProvider: &sdklog.LoggerProvider{},
+		},
+		{
+			name: "error-in-config",
+			cfg: configOptions{
+				opentelemetryConfig: OpenTelemetryConfiguration{
+					LoggerProvider: &LoggerProvider{
+						Processors: []LogRecordProcessor{
+							{
+								Batch:  &BatchLogRecordProcessor{},
+								Simple: &SimpleLogRecordProcessor{},
+							},
+						},
+					},
+				},
+			},
+			wantProvider: noop.NewLoggerProvider(),
+			wantErr:      errors.Join(errors.New("must not specify multiple log processor type")),
+		},
+		{
+			name: "multiple-errors-in-config",
+			cfg: configOptions{
+				opentelemetryConfig: OpenTelemetryConfiguration{
+					LoggerProvider: &LoggerProvider{
+						Limits: &LogRecordLimits{
+							AttributeCountLimit: ptr(-1),
+						},
+						Processors: []LogRecordProcessor{
+							{
+								Simple: &SimpleLogRecordProcessor{
+									Exporter: LogRecordExporter{
+										Console: Console{},
+										OTLP:    &OTLP{},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantProvider: noop.NewLoggerProvider(),
+			wantErr:      errors.Join(errors.New("invalid attribute count limit -1"), errors.New("must not specify multiple exporters")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lp, shutdown, err := loggerProvider(tt.cfg, resource.Default())
+			assert.IsType(t, tt.wantProvider, lp)
+			assert.Equal(t, tt.wantErr, err)
+			require.NoError(t, shutdown(context.Background()))
+		})
+	}
+}
+
+func TestLogRecordLimits(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		limits     *LogRecordLimits
+		attrLimits *AttributeLimits
+		wantOpts   int
+		wantErr    error
+	}{
+		{
+			name: "no-limits",
+		},
+		{
+			name: "all-limits",
+			limits: &LogRecordLimits{
+				AttributeCountLimit:       ptr(0),
+				AttributeValueLengthLimit: ptr(1024),
+			},
+			wantOpts: 2,
+		},
+		{
+			name: "attribute-limits-fallback",
+			limits: &LogRecordLimits{
+				AttributeCountLimit: ptr(10),
+			},
+			attrLimits: &AttributeLimits{
+				AttributeValueLengthLimit: ptr(1024),
+			},
+			wantOpts: 2,
+		},
+		{
+			name: "invalid-attribute-count-limit",
+			limits: &LogRecordLimits{
+				AttributeCountLimit: ptr(-1),
+			},
+			wantErr: errors.New("invalid attribute count limit -1"),
+		},
+		{
+			name: "invalid-attribute-value-length-limit",
+			limits: &LogRecordLimits{
+				AttributeValueLengthLimit: ptr(-2),
+			},
+			wantErr: errors.New("invalid attribute value length limit -2"),
+		},
+		{
+			name: "invalid-attribute-limits",
+			limits: &LogRecordLimits{
+				AttributeCountLimit: ptr(10),
+			},
+			attrLimits: &AttributeLimits{
+				AttributeCountLimit: ptr(-3),
+			},
+			wantErr: errors.New("attribute_limits: invalid attribute count limit -3"),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := logRecordLimits(tt.limits, tt.attrLimits)
+			require.Equal(t, tt.wantErr, err)
+			assert.Len(t, got, tt.wantOpts)
+		})
+	}
+}
+
+func TestLogProcessor(t *testing.T) {
+	testCases := []struct {
+		name          string
+		processor     LogRecordProcessor
+		wantErr       error
+		wantProcessor sdklog.Processor
+	}{
+		{
+			name:    "no processor",
+			wantErr: errors.New("unsupported log processor type {<nil> <nil>}"),
+		},
+		{
+			name: "multiple processor types",
+			processor: LogRecordProcessor{
+				Batch:  &BatchLogRecordProcessor{},
+				Simple: &SimpleLogRecordProcessor{},
+			},
+			wantErr: errors.New("must not specify multiple log processor type"),
+		},
+		{
+			name: "batch processor invalid exporter",
+			processor: LogRecordProcessor{
+				Batch: &BatchLogRecordProcessor{},
+			},
+			wantErr: errors.New("no valid log exporter"),
+		},
+		{
+			name: "batch processor invalid batch size console exporter",
+			processor: LogRecordProcessor{
+				Batch: &BatchLogRecordProcessor{
+					MaxExportBatchSize: ptr(-1),
+					Exporter: LogRecordExporter{
+						Console: Console{},
+					},
+				},
+			},
+			wantErr: errors.New("invalid batch size -1"),
+		},
+		{
+			name: "batch processor invalid export timeout console exporter",
+			processor: LogRecordProcessor{
+				Batch: &BatchLogRecordProcessor{
+					ExportTimeout: ptr(-2),
+					Exporter: LogRecordExporter{
+						Console: Console{},
+					},
+				},
+			},
+			wantErr: errors.New("invalid export timeout -2"),
+		},
+		{
+			name: "batch processor invalid queue size console exporter",
+			processor: LogRecordProcessor{
+				Batch: &BatchLogRecordProcessor{
+					MaxQueueSize: ptr(-3),
+					Exporter: LogRecordExporter{
+						Console: Console{},
+					},
+				},
+			},
+			wantErr: errors.New("invalid queue size -3"),
+		},
+		{
+			name: "batch processor invalid schedule delay console exporter",
+			processor: LogRecordProcessor{
+				Batch: &BatchLogRecordProcessor{
+					ScheduleDelay: ptr(-4),
+					Exporter: LogRecordExporter{
+						Console: Console{},
+					},
+				},
+			},
+			wantErr: errors.New("invalid schedule delay -4"),
+		},
+		{
+			name: "batch processor console exporter",
+			processor: LogRecordProcessor{
+				Batch: &BatchLogRecordProcessor{
+					MaxExportBatchSize: ptr(0),
+					ExportTimeout:      ptr(0),
+					MaxQueueSize:       ptr(0),
+					ScheduleDelay:      ptr(0),
+					Exporter: LogRecordExporter{
+						Console: Console{},
+					},
+				},
+			},
+			wantProcessor: &sdklog.BatchProcessor{},
+		},
+		{
+			name: "batch/otlp-http-exporter",
+			processor: LogRecordProcessor{
+				Batch: &BatchLogRecordProcessor{
+					Exporter: LogRecordExporter{
+						OTLP: &OTLP{
+							Protocol:    "http/protobuf",
+							Endpoint:    "http://localhost:4318/v1/logs",
+							Compression: ptr("gzip"),
+							Timeout:     ptr(1000),
+							Headers: map[string]string{
+								"test": "test1",
+							},
+						},
+					},
+				},
+			},
+			wantProcessor: &sdklog.BatchProcessor{},
+		},
+		{
+			name: "simple/no-exporter",
+			processor: LogRecordProcessor{
+				Simple: &SimpleLogRecordProcessor{},
+			},
+			wantErr: errors.New("no valid log exporter"),
+		},
+		{
+			name: "simple/console-exporter",
+			processor: LogRecordProcessor{
+				Simple: &SimpleLogRecordProcessor{
+					Exporter: LogRecordExporter{
+						Console: Console{},
+					},
+				},
+			},
+			wantProcessor: &sdklog.SimpleProcessor{},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := logProcessor(context.Background(), tt.processor)
+			require.Equal(t, tt.wantErr, err)
+			if tt.wantProcessor == nil {
+				require.Nil(t, got)
+			} else {
+				require.IsType(t, tt.wantProcessor, got)
+				require.NoError(t, got.Shutdown(context.Background()))
+			}
+		})
+	}
+}
+
+func TestLogExporter(t *testing.T) {
+	testCases := []struct {
+		name         string
+		exporter     LogRecordExporter
+		wantErr      error
+		wantExporter sdklog.Exporter
+	}{
+		{
+			name:    "no-exporter",
+			wantErr: errors.New("no valid log exporter"),
+		},
+		{
+			name: "multiple-exporters",
+			exporter: LogRecordExporter{
+				Console: Console{},
+				OTLP:    &OTLP{},
+			},
+			wantErr: errors.New("must not specify multiple exporters"),
+		},
+		{
+			name: "console",
+			exporter: LogRecordExporter{
+				Console: Console{},
+			},
+			wantExporter: &stdoutlog.Exporter{},
+		},
+		{
+			name: "otlp-invalid-protocol",
+			exporter: LogRecordExporter{
+				OTLP: &OTLP{
+					Protocol: "http/invalid",
+				},
+			},
+			wantErr: errors.New("unsupported protocol \"http/invalid\""),
+		},
+		{
+			name: "otlp-grpc",
+			exporter: LogRecordExporter{
+				OTLP: &OTLP{
+					Protocol:    "grpc/protobuf",
+					Endpoint:    "http://localhost:4317",
+					Compression: ptr("gzip"),
+					Timeout:     ptr(1000),
+					Headers: map[string]string{
+						"test": "test1",
+					},
+				},
+			},
+			wantExporter: &otlploggrpc.Exporter{},
+		},
+		{
+			name: "otlp-grpc-no-scheme",
+			exporter: LogRecordExporter{
+				OTLP: &OTLP{
+					Protocol:    "grpc/protobuf",
+					Endpoint:    "localhost:4317",
+					Compression: ptr("none"),
+				},
+			},
+			wantExporter: &otlploggrpc.Exporter{},
+		},
+		{
+			name: "otlp-grpc-invalid-endpoint",
+			exporter: LogRecordExporter{
+				OTLP: &OTLP{
+					Protocol: "grpc/protobuf",
+					Endpoint: " ",
+				},
+			},
+			wantErr: &url.Error{Op: "parse", URL: " ", Err: errors.New("invalid URI for request")},
+		},
+		{
+			name: "otlp-grpc-invalid-compression",
+			exporter: LogRecordExporter{
+				OTLP: &OTLP{
+					Protocol:    "grpc/protobuf",
+					Endpoint:    "localhost:4317",
+					Compression: ptr("invalid"),
+				},
+			},
+			wantErr: errors.New("unsupported compression \"invalid\""),
+		},
+		{
+			name: "otlp-http",
+			exporter: LogRecordExporter{
+				OTLP: &OTLP{
+					Protocol:    "http/protobuf",
+					Endpoint:    "http://localhost:4318/v1/logs",
+					Compression: ptr("none"),
+					Timeout:     ptr(1000),
+					Headers: map[string]string{
+						"test": "test1",
+					},
+				},
+			},
+			wantExporter: &otlploghttp.Exporter{},
+		},
+		{
+			name: "otlp-http-invalid-endpoint",
+			exporter: LogRecordExporter{
+				OTLP: &OTLP{
+					Protocol: "http/protobuf",
+					Endpoint: " ",
+				},
+			},
+			wantErr: &url.Error{Op: "parse", URL: " ", Err: errors.New("invalid URI for request")},
+		},
+		{
+			name: "otlp-http-invalid-compression",
+			exporter: LogRecordExporter{
+				OTLP: &OTLP{
+					Protocol:    "http/protobuf",
+					Endpoint:    "http://localhost:4318",
+					Compression: ptr("invalid"),
+				},
+			},
+			wantErr: errors.New("unsupported compression \"invalid\""),
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := logExporter(context.Background(), tt.exporter)
+			require.Equal(t, tt.wantErr, err)
+			if tt.wantExporter == nil {
+				require.Nil(t, got)
+			} else {
+				require.Equal(t, reflect.TypeOf(tt.wantExporter), reflect.TypeOf(got))
+				require.NoError(t, got.Shutdown(context.Background()))
+			}
+		})
+	}
+}
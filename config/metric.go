@@ -4,15 +4,690 @@
 package config // import "go.opentelemetry.io/contrib/config"
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/credentials"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+const (
+	temporalityCumulative = "cumulative"
+	temporalityDelta      = "delta"
+	temporalityLowMemory  = "lowmemory"
 )
 
-func initMeterProvider(cfg configOptions) (metric.MeterProvider, shutdownFunc) {
+var zeroScope instrumentation.Scope
+
+func meterProvider(cfg configOptions, res *resource.Resource) (metric.MeterProvider, shutdownFunc, error) {
 	if cfg.opentelemetryConfig.MeterProvider == nil {
-		return noop.NewMeterProvider(), noopShutdown
+		return noop.NewMeterProvider(), noopShutdown, nil
+	}
+	opts := []sdkmetric.Option{
+		sdkmetric.WithResource(res),
+	}
+
+	var errs []error
+	for _, reader := range cfg.opentelemetryConfig.MeterProvider.Readers {
+		r, err := metricReader(cfg.ctx, reader, cfg.prometheusServers)
+		if err == nil {
+			opts = append(opts, sdkmetric.WithReader(r))
+		} else {
+			errs = append(errs, err)
+		}
+	}
+	for _, vw := range cfg.opentelemetryConfig.MeterProvider.Views {
+		v, err := view(vw)
+		if err == nil {
+			opts = append(opts, sdkmetric.WithView(v))
+		} else {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return noop.NewMeterProvider(), noopShutdown, errors.Join(errs...)
+	}
+
+	mp := sdkmetric.NewMeterProvider(opts...)
+	return mp, mp.Shutdown, nil
+}
+
+func metricReader(ctx context.Context, r MetricReader, servers *prometheusServerRegistry) (sdkmetric.Reader, error) {
+	if r.Periodic != nil && r.Pull != nil {
+		return nil, errors.New("must not specify multiple metric reader type")
+	}
+
+	if r.Periodic != nil {
+		var opts []sdkmetric.PeriodicReaderOption
+		if r.Periodic.Interval != nil {
+			if *r.Periodic.Interval < 0 {
+				return nil, fmt.Errorf("invalid interval %d", *r.Periodic.Interval)
+			}
+			opts = append(opts, sdkmetric.WithInterval(time.Duration(*r.Periodic.Interval)*time.Millisecond))
+		}
+		if r.Periodic.Timeout != nil {
+			if *r.Periodic.Timeout < 0 {
+				return nil, fmt.Errorf("invalid timeout %d", *r.Periodic.Timeout)
+			}
+			opts = append(opts, sdkmetric.WithTimeout(time.Duration(*r.Periodic.Timeout)*time.Millisecond))
+		}
+		return periodicExporter(ctx, r.Periodic.Exporter, opts...)
+	}
+
+	if r.Pull != nil {
+		return pullReader(ctx, r.Pull.Exporter, servers)
+	}
+	return nil, errors.New("no valid metric reader")
+}
+
+func pullReader(ctx context.Context, exporter MetricExporter, servers *prometheusServerRegistry) (sdkmetric.Reader, error) {
+	if exporter.Console != nil || exporter.OTLP != nil || len(exporter.AdditionalProperties) > 0 {
+		return nil, errors.New("pull metric reader only supports the prometheus exporter")
+	}
+	if exporter.Prometheus != nil {
+		return prometheusReader(ctx, exporter.Prometheus, servers)
+	}
+	return nil, errors.New("no valid metric exporter")
+}
+
+func periodicExporter(ctx context.Context, exporter MetricExporter, opts ...sdkmetric.PeriodicReaderOption) (sdkmetric.Reader, error) {
+	var set int
+	for _, ok := range []bool{
+		exporter.Console != nil,
+		exporter.OTLP != nil,
+	} {
+		if ok {
+			set++
+		}
+	}
+	set += len(exporter.AdditionalProperties)
+	if set > 1 {
+		return nil, errors.New("must not specify multiple exporters")
+	}
+	if exporter.Prometheus != nil {
+		return nil, errors.New("prometheus exporter must be used with a pull metric reader")
+	}
+	if exporter.Console != nil {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "    ")
+
+		exp, err := stdoutmetric.New(
+			stdoutmetric.WithEncoder(enc),
+		)
+		if err != nil {
+			return nil, err
+		}
+		return sdkmetric.NewPeriodicReader(exp, opts...), nil
+	}
+	if exporter.OTLP != nil {
+		var err error
+		var exp sdkmetric.Exporter
+		switch exporter.OTLP.Protocol {
+		case protocolProtobufHTTP:
+			exp, err = otlpHTTPMetricExporter(ctx, exporter.OTLP)
+		case protocolProtobufGRPC:
+			exp, err = otlpGRPCMetricExporter(ctx, exporter.OTLP)
+		default:
+			return nil, fmt.Errorf("unsupported protocol %q", exporter.OTLP.Protocol)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return sdkmetric.NewPeriodicReader(exp, opts...), nil
+	}
+	for name, cfg := range exporter.AdditionalProperties {
+		exp, err := metricExporters.load(ctx, name, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return sdkmetric.NewPeriodicReader(exp, opts...), nil
+	}
+	return nil, errors.New("no valid metric exporter")
+}
+
+func otlpHTTPMetricExporter(ctx context.Context, otlpConfig *OTLPMetric) (sdkmetric.Exporter, error) {
+	var opts []otlpmetrichttp.Option
+
+	if len(otlpConfig.Endpoint) > 0 {
+		u, err := url.ParseRequestURI(otlpConfig.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlpmetrichttp.WithEndpoint(u.Host))
+
+		if u.Scheme == "http" {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+		if len(u.Path) > 0 {
+			opts = append(opts, otlpmetrichttp.WithURLPath(u.Path))
+		}
+	}
+	if otlpConfig.Compression != nil {
+		switch *otlpConfig.Compression {
+		case compressionGzip:
+			opts = append(opts, otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression))
+		case compressionNone:
+			opts = append(opts, otlpmetrichttp.WithCompression(otlpmetrichttp.NoCompression))
+		default:
+			return nil, fmt.Errorf("unsupported compression %q", *otlpConfig.Compression)
+		}
+	}
+	if otlpConfig.Timeout != nil && *otlpConfig.Timeout > 0 {
+		opts = append(opts, otlpmetrichttp.WithTimeout(time.Millisecond*time.Duration(*otlpConfig.Timeout)))
+	}
+	if len(otlpConfig.Headers) > 0 {
+		opts = append(opts, otlpmetrichttp.WithHeaders(otlpConfig.Headers))
+	}
+	tlsCfg, err := tlsConfig(otlpConfig.Certificate, otlpConfig.ClientCertificate, otlpConfig.ClientKey)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		opts = append(opts, otlpmetrichttp.WithTLSClientConfig(tlsCfg))
+	}
+	if otlpConfig.TemporalityPreference != nil {
+		ts, err := temporalitySelector(*otlpConfig.TemporalityPreference)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlpmetrichttp.WithTemporalitySelector(ts))
+	}
+	if otlpConfig.DefaultHistogramAggregation != nil {
+		as, err := aggregationSelector(*otlpConfig.DefaultHistogramAggregation)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlpmetrichttp.WithAggregationSelector(as))
+	}
+
+	return otlpmetrichttp.New(ctx, opts...)
+}
+
+func otlpGRPCMetricExporter(ctx context.Context, otlpConfig *OTLPMetric) (sdkmetric.Exporter, error) {
+	var opts []otlpmetricgrpc.Option
+
+	if len(otlpConfig.Endpoint) > 0 {
+		u, err := url.ParseRequestURI(otlpConfig.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		// ParseRequestURI leaves the Host field empty when no
+		// scheme is specified (i.e. localhost:4317). This check is
+		// here to support the case where a user may not specify a
+		// scheme. The code does its best effort here by using
+		// otlpConfig.Endpoint as-is in that case.
+		if u.Host != "" {
+			opts = append(opts, otlpmetricgrpc.WithEndpoint(u.Host))
+		} else {
+			opts = append(opts, otlpmetricgrpc.WithEndpoint(otlpConfig.Endpoint))
+		}
+		if u.Scheme == "http" {
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		}
+	}
+
+	if otlpConfig.Compression != nil {
+		switch *otlpConfig.Compression {
+		case compressionGzip:
+			opts = append(opts, otlpmetricgrpc.WithCompressor(*otlpConfig.Compression))
+		case compressionNone:
+			// none requires no options
+		default:
+			return nil, fmt.Errorf("unsupported compression %q", *otlpConfig.Compression)
+		}
+	}
+	if otlpConfig.Timeout != nil && *otlpConfig.Timeout > 0 {
+		opts = append(opts, otlpmetricgrpc.WithTimeout(time.Millisecond*time.Duration(*otlpConfig.Timeout)))
+	}
+	if len(otlpConfig.Headers) > 0 {
+		opts = append(opts, otlpmetricgrpc.WithHeaders(otlpConfig.Headers))
+	}
+	tlsCfg, err := tlsConfig(otlpConfig.Certificate, otlpConfig.ClientCertificate, otlpConfig.ClientKey)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(tlsCfg)))
+	}
+	if otlpConfig.TemporalityPreference != nil {
+		ts, err := temporalitySelector(*otlpConfig.TemporalityPreference)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlpmetricgrpc.WithTemporalitySelector(ts))
+	}
+	if otlpConfig.DefaultHistogramAggregation != nil {
+		as, err := aggregationSelector(*otlpConfig.DefaultHistogramAggregation)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlpmetricgrpc.WithAggregationSelector(as))
+	}
+
+	return otlpmetricgrpc.New(ctx, opts...)
+}
+
+func temporalitySelector(preference string) (sdkmetric.TemporalitySelector, error) {
+	switch preference {
+	case temporalityCumulative:
+		return cumulativeTemporality, nil
+	case temporalityDelta:
+		return deltaTemporality, nil
+	case temporalityLowMemory:
+		return lowMemoryTemporality, nil
+	default:
+		return nil, fmt.Errorf("unsupported temporality preference %q", preference)
+	}
+}
+
+func cumulativeTemporality(sdkmetric.InstrumentKind) metricdata.Temporality {
+	return metricdata.CumulativeTemporality
+}
+
+func deltaTemporality(ik sdkmetric.InstrumentKind) metricdata.Temporality {
+	switch ik {
+	case sdkmetric.InstrumentKindCounter, sdkmetric.InstrumentKindHistogram, sdkmetric.InstrumentKindObservableCounter:
+		return metricdata.DeltaTemporality
+	default:
+		return metricdata.CumulativeTemporality
+	}
+}
+
+func lowMemoryTemporality(ik sdkmetric.InstrumentKind) metricdata.Temporality {
+	switch ik {
+	case sdkmetric.InstrumentKindCounter, sdkmetric.InstrumentKindHistogram:
+		return metricdata.DeltaTemporality
+	default:
+		return metricdata.CumulativeTemporality
+	}
+}
+
+func aggregationSelector(aggr OTLPMetricDefaultHistogramAggregation) (sdkmetric.AggregationSelector, error) {
+	switch aggr {
+	case OTLPMetricDefaultHistogramAggregationExplicitBucketHistogram:
+		return sdkmetric.DefaultAggregationSelector, nil
+	case OTLPMetricDefaultHistogramAggregationBase2ExponentialBucketHistogram:
+		return func(ik sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+			if ik == sdkmetric.InstrumentKindHistogram {
+				// Defaults as defined by the specification.
+				return sdkmetric.AggregationBase2ExponentialHistogram{
+					MaxSize:  160,
+					MaxScale: 20,
+				}
+			}
+			return sdkmetric.DefaultAggregationSelector(ik)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported default histogram aggregation %q", aggr)
+	}
+}
+
+// prometheusReader returns a Prometheus pull reader serving its metrics with
+// an HTTP server. The server is shared with the readers of servers listening
+// on the same address, if any: only the readers of a ReloadableSDK share
+// their server.
+func prometheusReader(ctx context.Context, prometheusConfig *Prometheus, servers *prometheusServerRegistry) (sdkmetric.Reader, error) {
+	var opts []otelprom.Option
+	if prometheusConfig.Host == nil {
+		return nil, errors.New("host must be specified")
+	}
+	if prometheusConfig.Port == nil {
+		return nil, errors.New("port must be specified")
+	}
+	if prometheusConfig.WithoutScopeInfo != nil && *prometheusConfig.WithoutScopeInfo {
+		opts = append(opts, otelprom.WithoutScopeInfo())
+	}
+	if prometheusConfig.WithoutTypeSuffix != nil && *prometheusConfig.WithoutTypeSuffix {
+		opts = append(opts, otelprom.WithoutCounterSuffixes())
+	}
+	if prometheusConfig.WithoutUnits != nil && *prometheusConfig.WithoutUnits {
+		opts = append(opts, otelprom.WithoutUnits())
+	}
+
+	// create an isolated registry instead of using the global registry --
+	// the user might not want to mix OTel with non-OTel metrics
+	reg := prometheus.NewRegistry()
+	opts = append(opts, otelprom.WithRegisterer(reg))
+
+	reader, err := otelprom.New(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating otel prometheus exporter: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg}))
+
+	if servers == nil {
+		servers = newPrometheusServerRegistry()
+	}
+	addr := net.JoinHostPort(*prometheusConfig.Host, fmt.Sprint(*prometheusConfig.Port))
+	server, handler, err := servers.acquire(addr, *prometheusConfig.Port != 0, mux)
+	if err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("binding address %s for Prometheus exporter: %w", addr, err),
+			reader.Shutdown(ctx),
+		)
+	}
+	return readerWithServer{server.addr, reader, servers, server, handler}, nil
+}
+
+// prometheusServerRegistry shares the HTTP server listening on an address
+// between the Prometheus pull readers configured with that address. This lets
+// the readers of a new configuration of a ReloadableSDK be created while the
+// ones of the previous configuration still listen.
+type prometheusServerRegistry struct {
+	mu      sync.Mutex
+	servers map[string]*prometheusServer
+}
+
+func newPrometheusServerRegistry() *prometheusServerRegistry {
+	return &prometheusServerRegistry{servers: make(map[string]*prometheusServer)}
+}
+
+type prometheusServer struct {
+	addr   net.Addr
+	key    string
+	server *http.Server
+
+	// handlers are the handlers of the readers using the server. The last
+	// one, of the most recently created reader, serves the requests.
+	handlers []*http.Handler
+	current  atomic.Pointer[http.Handler]
+}
+
+// acquire returns the server listening on addr, started if no reader uses it
+// yet, and the registration of handler with that server. Servers listening on
+// an address with an ephemeral port are not shared.
+func (r *prometheusServerRegistry) acquire(addr string, shared bool, handler http.Handler) (*prometheusServer, *http.Handler, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h := &handler
+	if s, ok := r.servers[addr]; ok && shared {
+		s.handlers = append(s.handlers, h)
+		s.current.Store(h)
+		return s, h, nil
+	}
+
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	s := &prometheusServer{addr: lis.Addr(), handlers: []*http.Handler{h}}
+	s.current.Store(h)
+	s.server = &http.Server{
+		// Timeouts are necessary to make a server resilient to attacks, but ListenAndServe doesn't set any.
+		// We use values from this example: https://blog.cloudflare.com/exposing-go-on-the-internet/#:~:text=There%20are%20three%20main%20timeouts
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  120 * time.Second,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			(*s.current.Load()).ServeHTTP(w, req)
+		}),
+	}
+	if shared {
+		s.key = addr
+		r.servers[addr] = s
+	}
+
+	go func() {
+		if err := s.server.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			otel.Handle(fmt.Errorf("the Prometheus HTTP server exited unexpectedly: %w", err))
+		}
+	}()
+	return s, h, nil
+}
+
+// release unregisters the handler h of s. The requests are served by the
+// handler of the most recently created reader left, and the server is shut
+// down once no reader uses it.
+func (r *prometheusServerRegistry) release(ctx context.Context, s *prometheusServer, h *http.Handler) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.Index(s.handlers, h)
+	if i < 0 {
+		// Already released.
+		return nil
+	}
+	s.handlers = slices.Delete(s.handlers, i, i+1)
+	if len(s.handlers) > 0 {
+		s.current.Store(s.handlers[len(s.handlers)-1])
+		return nil
+	}
+	if s.key != "" && r.servers[s.key] == s {
+		delete(r.servers, s.key)
+	}
+	// The server is shut down with r locked so that its address is released
+	// before another server can listen on it.
+	return s.server.Shutdown(ctx)
+}
+
+type readerWithServer struct {
+	addr net.Addr
+	sdkmetric.Reader
+	servers *prometheusServerRegistry
+	server  *prometheusServer
+	handler *http.Handler
+}
+
+func (rws readerWithServer) Shutdown(ctx context.Context) error {
+	// The handler is released first so that the server does not serve the
+	// metrics of a shut down reader.
+	return errors.Join(
+		rws.servers.release(ctx, rws.server, rws.handler),
+		rws.Reader.Shutdown(ctx),
+	)
+}
+
+func view(v View) (sdkmetric.View, error) {
+	if v.Selector == nil {
+		return nil, errors.New("view: no selector provided")
+	}
+
+	inst, err := instrument(*v.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := stream(v.Stream)
+	if err != nil {
+		return nil, err
+	}
+	return sdkmetric.NewView(inst, s), nil
+}
+
+func instrument(vs ViewSelector) (sdkmetric.Instrument, error) {
+	kind, err := instrumentKind(vs.InstrumentType)
+	if err != nil {
+		return sdkmetric.Instrument{}, fmt.Errorf("view_selector: %w", err)
+	}
+	inst := sdkmetric.Instrument{
+		Name: strOrEmpty(vs.InstrumentName),
+		Unit: strOrEmpty(vs.Unit),
+		Kind: kind,
+		Scope: instrumentation.Scope{
+			Name:      strOrEmpty(vs.MeterName),
+			Version:   strOrEmpty(vs.MeterVersion),
+			SchemaURL: strOrEmpty(vs.MeterSchemaUrl),
+		},
+	}
+
+	if instrumentIsEmpty(inst) {
+		return sdkmetric.Instrument{}, errors.New("view_selector: empty selector not supported")
+	}
+	return inst, nil
+}
+
+func stream(vs *ViewStream) (sdkmetric.Stream, error) {
+	if vs == nil {
+		return sdkmetric.Stream{}, nil
+	}
+
+	aggr, err := aggregation(vs.Aggregation)
+	if err != nil {
+		return sdkmetric.Stream{}, err
+	}
+
+	s := sdkmetric.Stream{
+		Name:        strOrEmpty(vs.Name),
+		Description: strOrEmpty(vs.Description),
+		Aggregation: aggr,
+	}
+	if len(vs.AttributeKeys) > 0 {
+		s.AttributeFilter = attributeFilter(vs.AttributeKeys)
+	}
+	return s, nil
+}
+
+func attributeFilter(attributeKeys []string) attribute.Filter {
+	attrKeys := make([]attribute.Key, 0, len(attributeKeys))
+	for _, attrStr := range attributeKeys {
+		attrKeys = append(attrKeys, attribute.Key(attrStr))
+	}
+	return attribute.NewAllowKeysFilter(attrKeys...)
+}
+
+func aggregation(aggr *ViewStreamAggregation) (sdkmetric.Aggregation, error) {
+	if aggr == nil {
+		return nil, nil
+	}
+
+	var set int
+	for _, ok := range []bool{
+		aggr.Base2ExponentialBucketHistogram != nil,
+		aggr.Default != nil,
+		aggr.Drop != nil,
+		aggr.ExplicitBucketHistogram != nil,
+		aggr.LastValue != nil,
+		aggr.Sum != nil,
+	} {
+		if ok {
+			set++
+		}
+	}
+	if set > 1 {
+		return nil, errors.New("view_stream: must not specify multiple aggregations")
+	}
+
+	switch {
+	case aggr.Base2ExponentialBucketHistogram != nil:
+		return base2ExponentialHistogram(aggr.Base2ExponentialBucketHistogram)
+	case aggr.Drop != nil:
+		return sdkmetric.AggregationDrop{}, nil
+	case aggr.ExplicitBucketHistogram != nil:
+		return explicitBucketHistogram(aggr.ExplicitBucketHistogram)
+	case aggr.LastValue != nil:
+		return sdkmetric.AggregationLastValue{}, nil
+	case aggr.Sum != nil:
+		return sdkmetric.AggregationSum{}, nil
+	}
+	// Default aggregation or nothing set: let the SDK decide.
+	return nil, nil
+}
+
+func base2ExponentialHistogram(h *ViewStreamAggregationBase2ExponentialBucketHistogram) (sdkmetric.Aggregation, error) {
+	// Defaults as defined by the specification.
+	maxSize, maxScale := 160, 20
+	if h.MaxSize != nil {
+		if *h.MaxSize <= 0 {
+			return nil, fmt.Errorf("view_stream: invalid max_size %d", *h.MaxSize)
+		}
+		maxSize = *h.MaxSize
+	}
+	if h.MaxScale != nil {
+		if *h.MaxScale < -10 || *h.MaxScale > 20 {
+			return nil, fmt.Errorf("view_stream: invalid max_scale %d", *h.MaxScale)
+		}
+		maxScale = *h.MaxScale
+	}
+	return sdkmetric.AggregationBase2ExponentialHistogram{
+		MaxSize:  int32(maxSize),
+		MaxScale: int32(maxScale),
+		// Need to negate because config has the positive action RecordMinMax.
+		NoMinMax: !boolOrTrue(h.RecordMinMax),
+	}, nil
+}
+
+func explicitBucketHistogram(h *ViewStreamAggregationExplicitBucketHistogram) (sdkmetric.Aggregation, error) {
+	for i := 1; i < len(h.Boundaries); i++ {
+		if h.Boundaries[i] <= h.Boundaries[i-1] {
+			return nil, fmt.Errorf("view_stream: non-monotonic boundaries %v", h.Boundaries)
+		}
+	}
+	return sdkmetric.AggregationExplicitBucketHistogram{
+		Boundaries: h.Boundaries,
+		// Need to negate because config has the positive action RecordMinMax.
+		NoMinMax: !boolOrTrue(h.RecordMinMax),
+	}, nil
+}
+
+func instrumentKind(vsit *ViewSelectorInstrumentType) (sdkmetric.InstrumentKind, error) {
+	if vsit == nil {
+		// Equivalent to instrumentKindUndefined.
+		return sdkmetric.InstrumentKind(0), nil
+	}
+
+	switch *vsit {
+	case ViewSelectorInstrumentTypeCounter:
+		return sdkmetric.InstrumentKindCounter, nil
+	case ViewSelectorInstrumentTypeUpDownCounter:
+		return sdkmetric.InstrumentKindUpDownCounter, nil
+	case ViewSelectorInstrumentTypeHistogram:
+		return sdkmetric.InstrumentKindHistogram, nil
+	case ViewSelectorInstrumentTypeObservableCounter:
+		return sdkmetric.InstrumentKindObservableCounter, nil
+	case ViewSelectorInstrumentTypeObservableUpDownCounter:
+		return sdkmetric.InstrumentKindObservableUpDownCounter, nil
+	case ViewSelectorInstrumentTypeObservableGauge:
+		return sdkmetric.InstrumentKindObservableGauge, nil
+	}
+
+	return sdkmetric.InstrumentKind(0), fmt.Errorf("instrument_type: invalid value %q", *vsit)
+}
+
+func instrumentIsEmpty(i sdkmetric.Instrument) bool {
+	return i.Name == "" &&
+		i.Description == "" &&
+		i.Kind == sdkmetric.InstrumentKind(0) &&
+		i.Unit == "" &&
+		i.Scope == zeroScope
+}
+
+func boolOrTrue(pBool *bool) bool {
+	if pBool == nil {
+		return true
+	}
+	return *pBool
+}
+
+func strOrEmpty(pStr *string) string {
+	if pStr == nil {
+		return ""
 	}
-	mp := sdkmetric.NewMeterProvider()
-	return mp, mp.Shutdown
+	return *pStr
 }
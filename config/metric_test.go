@@ -4,12 +4,30 @@
 package config
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
 )
 
 func TestInitMeterProvider(t *testing.T) {
@@ -23,10 +41,773 @@ func TestInitMeterProvider(t *testing.T) {
 			name:         "no-meter-provider-configured",
 			wantProvider: noop.NewMeterProvider(),
 		},
+		{
+			name: "error-in-config",
+			cfg: configOptions{
+				opentelemetryConfig: OpenTelemetryConfiguration{
+					MeterProvider: &MeterProvider{
+						Readers: []MetricReader{
+							{
+								Periodic: &PeriodicMetricReader{},
+								Pull:     &PullMetricReader{},
+							},
+						},
+					},
+				},
+			},
+			wantProvider: noop.NewMeterProvider(),
+			wantErr:      errors.Join(errors.New("must not specify multiple metric reader type")),
+		},
+		{
+			name: "multiple-errors-in-config",
+			cfg: configOptions{
+				opentelemetryConfig: OpenTelemetryConfiguration{
+					MeterProvider: &MeterProvider{
+						Readers: []MetricReader{
+							{
+								Periodic: &PeriodicMetricReader{},
+								Pull:     &PullMetricReader{},
+							},
+						},
+						Views: []View{
+							{},
+						},
+					},
+				},
+			},
+			wantProvider: noop.NewMeterProvider(),
+			wantErr:      errors.Join(errors.New("must not specify multiple metric reader type"), errors.New("view: no selector provided")),
+		},
 	}
 	for _, tt := range tests {
-		mp, err := initMeterProvider(tt.cfg)
-		require.Equal(t, tt.wantProvider, mp)
-		require.NoError(t, tt.wantErr, err)
+		t.Run(tt.name, func(t *testing.T) {
+			mp, shutdown, err := meterProvider(tt.cfg, resource.Default())
+			require.Equal(t, tt.wantProvider, mp)
+			assert.Equal(t, tt.wantErr, err)
+			require.NoError(t, shutdown(context.Background()))
+		})
+	}
+}
+
+func TestReader(t *testing.T) {
+	consoleExporter, err := stdoutmetric.New(
+		stdoutmetric.WithPrettyPrint(),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+	otlpGRPCExporter, err := otlpmetricgrpc.New(ctx)
+	require.NoError(t, err)
+	otlpHTTPExporter, err := otlpmetrichttp.New(ctx)
+	require.NoError(t, err)
+	testCases := []struct {
+		name       string
+		reader     MetricReader
+		wantErr    error
+		wantReader sdkmetric.Reader
+	}{
+		{
+			name:    "no reader",
+			wantErr: errors.New("no valid metric reader"),
+		},
+		{
+			name: "pull/no-exporter",
+			reader: MetricReader{
+				Pull: &PullMetricReader{},
+			},
+			wantErr: errors.New("no valid metric exporter"),
+		},
+		{
+			name: "pull/otlp-exporter",
+			reader: MetricReader{
+				Pull: &PullMetricReader{
+					Exporter: MetricExporter{
+						OTLP: &OTLPMetric{},
+					},
+				},
+			},
+			wantErr: errors.New("pull metric reader only supports the prometheus exporter"),
+		},
+		{
+			name: "pull/prometheus-no-host",
+			reader: MetricReader{
+				Pull: &PullMetricReader{
+					Exporter: MetricExporter{
+						Prometheus: &Prometheus{},
+					},
+				},
+			},
+			wantErr: errors.New("host must be specified"),
+		},
+		{
+			name: "pull/prometheus-no-port",
+			reader: MetricReader{
+				Pull: &PullMetricReader{
+					Exporter: MetricExporter{
+						Prometheus: &Prometheus{
+							Host: ptr("localhost"),
+						},
+					},
+				},
+			},
+			wantErr: errors.New("port must be specified"),
+		},
+		{
+			name: "periodic/prometheus-exporter",
+			reader: MetricReader{
+				Periodic: &PeriodicMetricReader{
+					Exporter: MetricExporter{
+						Prometheus: &Prometheus{},
+					},
+				},
+			},
+			wantErr: errors.New("prometheus exporter must be used with a pull metric reader"),
+		},
+		{
+			name: "periodic/invalid-interval",
+			reader: MetricReader{
+				Periodic: &PeriodicMetricReader{
+					Interval: ptr(-1),
+					Exporter: MetricExporter{
+						Console: Console{},
+					},
+				},
+			},
+			wantErr: errors.New("invalid interval -1"),
+		},
+		{
+			name: "periodic/invalid-timeout",
+			reader: MetricReader{
+				Periodic: &PeriodicMetricReader{
+					Timeout: ptr(-2),
+					Exporter: MetricExporter{
+						Console: Console{},
+					},
+				},
+			},
+			wantErr: errors.New("invalid timeout -2"),
+		},
+		{
+			name: "periodic/no-exporter",
+			reader: MetricReader{
+				Periodic: &PeriodicMetricReader{},
+			},
+			wantErr: errors.New("no valid metric exporter"),
+		},
+		{
+			name: "periodic/multiple-exporters",
+			reader: MetricReader{
+				Periodic: &PeriodicMetricReader{
+					Exporter: MetricExporter{
+						Console: Console{},
+						OTLP:    &OTLPMetric{},
+					},
+				},
+			},
+			wantErr: errors.New("must not specify multiple exporters"),
+		},
+		{
+			name: "periodic/console-exporter",
+			reader: MetricReader{
+				Periodic: &PeriodicMetricReader{
+					Interval: ptr(1000),
+					Timeout:  ptr(500),
+					Exporter: MetricExporter{
+						Console: Console{},
+					},
+				},
+			},
+			wantReader: sdkmetric.NewPeriodicReader(consoleExporter),
+		},
+		{
+			name: "periodic/otlp-exporter-invalid-protocol",
+			reader: MetricReader{
+				Periodic: &PeriodicMetricReader{
+					Exporter: MetricExporter{
+						OTLP: &OTLPMetric{
+							Protocol: *ptr("http/invalid"),
+						},
+					},
+				},
+			},
+			wantErr: errors.New("unsupported protocol \"http/invalid\""),
+		},
+		{
+			name: "periodic/otlp-grpc-exporter",
+			reader: MetricReader{
+				Periodic: &PeriodicMetricReader{
+					Exporter: MetricExporter{
+						OTLP: &OTLPMetric{
+							Protocol:    "grpc/protobuf",
+							Endpoint:    "http://localhost:4317",
+							Compression: ptr("gzip"),
+							Timeout:     ptr(1000),
+							Headers: map[string]string{
+								"test": "test1",
+							},
+							TemporalityPreference:       ptr("delta"),
+							DefaultHistogramAggregation: ptr(OTLPMetricDefaultHistogramAggregationBase2ExponentialBucketHistogram),
+						},
+					},
+				},
+			},
+			wantReader: sdkmetric.NewPeriodicReader(otlpGRPCExporter),
+		},
+		{
+			name: "periodic/otlp-grpc-exporter-no-scheme",
+			reader: MetricReader{
+				Periodic: &PeriodicMetricReader{
+					Exporter: MetricExporter{
+						OTLP: &OTLPMetric{
+							Protocol:    "grpc/protobuf",
+							Endpoint:    "localhost:4317",
+							Compression: ptr("none"),
+						},
+					},
+				},
+			},
+			wantReader: sdkmetric.NewPeriodicReader(otlpGRPCExporter),
+		},
+		{
+			name: "periodic/otlp-grpc-invalid-endpoint",
+			reader: MetricReader{
+				Periodic: &PeriodicMetricReader{
+					Exporter: MetricExporter{
+						OTLP: &OTLPMetric{
+							Protocol: "grpc/protobuf",
+							Endpoint: " ",
+						},
+					},
+				},
+			},
+			wantErr: &url.Error{Op: "parse", URL: " ", Err: errors.New("invalid URI for request")},
+		},
+		{
+			name: "periodic/otlp-grpc-invalid-compression",
+			reader: MetricReader{
+				Periodic: &PeriodicMetricReader{
+					Exporter: MetricExporter{
+						OTLP: &OTLPMetric{
+							Protocol:    "grpc/protobuf",
+							Endpoint:    "localhost:4317",
+							Compression: ptr("invalid"),
+						},
+					},
+				},
+			},
+			wantErr: errors.New("unsupported compression \"invalid\""),
+		},
+		{
+			name: "periodic/otlp-grpc-invalid-temporality",
+			reader: MetricReader{
+				Periodic: &PeriodicMetricReader{
+					Exporter: MetricExporter{
+						OTLP: &OTLPMetric{
+							Protocol:              "grpc/protobuf",
+							Endpoint:              "localhost:4317",
+							TemporalityPreference: ptr("invalid"),
+						},
+					},
+				},
+			},
+			wantErr: errors.New("unsupported temporality preference \"invalid\""),
+		},
+		{
+			name: "periodic/otlp-http-exporter",
+			reader: MetricReader{
+				Periodic: &PeriodicMetricReader{
+					Exporter: MetricExporter{
+						OTLP: &OTLPMetric{
+							Protocol:    "http/protobuf",
+							Endpoint:    "http://localhost:4318/path/123",
+							Compression: ptr("gzip"),
+							Timeout:     ptr(1000),
+							Headers: map[string]string{
+								"test": "test1",
+							},
+							TemporalityPreference:       ptr("lowmemory"),
+							DefaultHistogramAggregation: ptr(OTLPMetricDefaultHistogramAggregationExplicitBucketHistogram),
+						},
+					},
+				},
+			},
+			wantReader: sdkmetric.NewPeriodicReader(otlpHTTPExporter),
+		},
+		{
+			name: "periodic/otlp-http-exporter-no-endpoint",
+			reader: MetricReader{
+				Periodic: &PeriodicMetricReader{
+					Exporter: MetricExporter{
+						OTLP: &OTLPMetric{
+							Protocol:              "http/protobuf",
+							Compression:           ptr("none"),
+							TemporalityPreference: ptr("cumulative"),
+						},
+					},
+				},
+			},
+			wantReader: sdkmetric.NewPeriodicReader(otlpHTTPExporter),
+		},
+		{
+			name: "periodic/otlp-http-invalid-endpoint",
+			reader: MetricReader{
+				Periodic: &PeriodicMetricReader{
+					Exporter: MetricExporter{
+						OTLP: &OTLPMetric{
+							Protocol: "http/protobuf",
+							Endpoint: " ",
+						},
+					},
+				},
+			},
+			wantErr: &url.Error{Op: "parse", URL: " ", Err: errors.New("invalid URI for request")},
+		},
+		{
+			name: "periodic/otlp-http-invalid-compression",
+			reader: MetricReader{
+				Periodic: &PeriodicMetricReader{
+					Exporter: MetricExporter{
+						OTLP: &OTLPMetric{
+							Protocol:    "http/protobuf",
+							Endpoint:    "localhost:4318",
+							Compression: ptr("invalid"),
+						},
+					},
+				},
+			},
+			wantErr: errors.New("unsupported compression \"invalid\""),
+		},
+		{
+			name: "periodic/otlp-http-invalid-histogram-aggregation",
+			reader: MetricReader{
+				Periodic: &PeriodicMetricReader{
+					Exporter: MetricExporter{
+						OTLP: &OTLPMetric{
+							Protocol:                    "http/protobuf",
+							Endpoint:                    "localhost:4318",
+							DefaultHistogramAggregation: ptr(OTLPMetricDefaultHistogramAggregation("invalid")),
+						},
+					},
+				},
+			},
+			wantErr: errors.New("unsupported default histogram aggregation \"invalid\""),
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := metricReader(context.Background(), tt.reader, nil)
+			require.Equal(t, tt.wantErr, err)
+			if tt.wantReader == nil {
+				require.Nil(t, got)
+			} else {
+				require.Equal(t, reflect.TypeOf(tt.wantReader), reflect.TypeOf(got))
+				wantExporterType := reflect.Indirect(reflect.ValueOf(tt.wantReader)).FieldByName("exporter").Elem().Type()
+				gotExporterType := reflect.Indirect(reflect.ValueOf(got)).FieldByName("exporter").Elem().Type()
+				require.Equal(t, wantExporterType.String(), gotExporterType.String())
+				require.NoError(t, got.Shutdown(context.Background()))
+			}
+		})
+	}
+}
+
+func TestPrometheusReader(t *testing.T) {
+	r, err := metricReader(context.Background(), MetricReader{
+		Pull: &PullMetricReader{
+			Exporter: MetricExporter{
+				Prometheus: &Prometheus{
+					Host:              ptr("localhost"),
+					Port:              ptr(0),
+					WithoutScopeInfo:  ptr(true),
+					WithoutTypeSuffix: ptr(true),
+					WithoutUnits:      ptr(true),
+				},
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	// pull-based exporters like Prometheus need to be registered
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(r))
+
+	rws, ok := r.(readerWithServer)
+	require.True(t, ok, "expected readerWithServer but got %T", r)
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/metrics", rws.addr))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Contains(t, string(body), "# HELP")
+
+	require.NoError(t, mp.Shutdown(context.Background()))
+}
+
+func TestPrometheusReaderAddressInUse(t *testing.T) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	port := lis.Addr().(*net.TCPAddr).Port
+	require.NoError(t, lis.Close())
+
+	cfg := OpenTelemetryConfiguration{
+		MeterProvider: &MeterProvider{
+			Readers: []MetricReader{{
+				Pull: &PullMetricReader{Exporter: MetricExporter{Prometheus: &Prometheus{
+					Host: ptr("localhost"),
+					Port: ptr(port),
+				}}},
+			}},
+		},
 	}
+	sdk, err := NewSDK(WithOpenTelemetryConfiguration(cfg))
+	require.NoError(t, err)
+
+	// Only the readers of a ReloadableSDK share their server.
+	_, err = NewSDK(WithOpenTelemetryConfiguration(cfg))
+	assert.ErrorContains(t, err, "address already in use")
+
+	require.NoError(t, sdk.Shutdown(context.Background()))
+}
+
+func TestPrometheusReaderInvalidAddress(t *testing.T) {
+	_, err := metricReader(context.Background(), MetricReader{
+		Pull: &PullMetricReader{
+			Exporter: MetricExporter{
+				Prometheus: &Prometheus{
+					Host: ptr("invalid-host"),
+					Port: ptr(-1),
+				},
+			},
+		},
+	}, nil)
+	assert.ErrorContains(t, err, "binding")
+}
+
+func TestTemporalitySelector(t *testing.T) {
+	for _, tt := range []struct {
+		preference string
+		kind       sdkmetric.InstrumentKind
+		want       metricdata.Temporality
+	}{
+		{"cumulative", sdkmetric.InstrumentKindCounter, metricdata.CumulativeTemporality},
+		{"delta", sdkmetric.InstrumentKindCounter, metricdata.DeltaTemporality},
+		{"delta", sdkmetric.InstrumentKindObservableCounter, metricdata.DeltaTemporality},
+		{"delta", sdkmetric.InstrumentKindUpDownCounter, metricdata.CumulativeTemporality},
+		{"lowmemory", sdkmetric.InstrumentKindHistogram, metricdata.DeltaTemporality},
+		{"lowmemory", sdkmetric.InstrumentKindObservableCounter, metricdata.CumulativeTemporality},
+	} {
+		t.Run(fmt.Sprintf("%s/%d", tt.preference, tt.kind), func(t *testing.T) {
+			ts, err := temporalitySelector(tt.preference)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ts(tt.kind))
+		})
+	}
+}
+
+func TestView(t *testing.T) {
+	testCases := []struct {
+		name            string
+		view            View
+		wantErr         string
+		matchInstrument *sdkmetric.Instrument
+		wantStream      sdkmetric.Stream
+		wantResult      bool
+	}{
+		{
+			name:    "no selector",
+			wantErr: "view: no selector provided",
+		},
+		{
+			name: "selector/invalid_type",
+			view: View{
+				Selector: &ViewSelector{
+					InstrumentType: (*ViewSelectorInstrumentType)(ptr("invalid_type")),
+				},
+			},
+			wantErr: "view_selector: instrument_type: invalid value \"invalid_type\"",
+		},
+		{
+			name: "selector/empty",
+			view: View{
+				Selector: &ViewSelector{},
+			},
+			wantErr: "view_selector: empty selector not supported",
+		},
+		{
+			name: "all selectors match",
+			view: View{
+				Selector: &ViewSelector{
+					InstrumentName: ptr("test_name"),
+					InstrumentType: (*ViewSelectorInstrumentType)(ptr("counter")),
+					Unit:           ptr("test_unit"),
+					MeterName:      ptr("test_meter_name"),
+					MeterVersion:   ptr("test_meter_version"),
+					MeterSchemaUrl: ptr("test_schema_url"),
+				},
+			},
+			matchInstrument: &sdkmetric.Instrument{
+				Name: "test_name",
+				Unit: "test_unit",
+				Kind: sdkmetric.InstrumentKindCounter,
+				Scope: instrumentation.Scope{
+					Name:      "test_meter_name",
+					Version:   "test_meter_version",
+					SchemaURL: "test_schema_url",
+				},
+			},
+			wantStream: sdkmetric.Stream{Name: "test_name", Unit: "test_unit"},
+			wantResult: true,
+		},
+		{
+			name: "all selectors no match name",
+			view: View{
+				Selector: &ViewSelector{
+					InstrumentName: ptr("test_name"),
+					InstrumentType: (*ViewSelectorInstrumentType)(ptr("counter")),
+					Unit:           ptr("test_unit"),
+					MeterName:      ptr("test_meter_name"),
+					MeterVersion:   ptr("test_meter_version"),
+					MeterSchemaUrl: ptr("test_schema_url"),
+				},
+			},
+			matchInstrument: &sdkmetric.Instrument{
+				Name: "not_match",
+				Unit: "test_unit",
+				Kind: sdkmetric.InstrumentKindCounter,
+				Scope: instrumentation.Scope{
+					Name:      "test_meter_name",
+					Version:   "test_meter_version",
+					SchemaURL: "test_schema_url",
+				},
+			},
+			wantStream: sdkmetric.Stream{},
+			wantResult: false,
+		},
+		{
+			name: "with stream",
+			view: View{
+				Selector: &ViewSelector{
+					InstrumentName: ptr("test_name"),
+					Unit:           ptr("test_unit"),
+				},
+				Stream: &ViewStream{
+					Name:          ptr("new_name"),
+					Description:   ptr("new_description"),
+					AttributeKeys: []string{"foo", "bar"},
+					Aggregation: &ViewStreamAggregation{
+						Sum: make(ViewStreamAggregationSum),
+					},
+				},
+			},
+			matchInstrument: &sdkmetric.Instrument{
+				Name:        "test_name",
+				Description: "test_description",
+				Unit:        "test_unit",
+			},
+			wantStream: sdkmetric.Stream{
+				Name:        "new_name",
+				Description: "new_description",
+				Unit:        "test_unit",
+				Aggregation: sdkmetric.AggregationSum{},
+			},
+			wantResult: true,
+		},
+		{
+			name: "stream/multiple-aggregations",
+			view: View{
+				Selector: &ViewSelector{
+					InstrumentName: ptr("test_name"),
+				},
+				Stream: &ViewStream{
+					Aggregation: &ViewStreamAggregation{
+						Sum:  make(ViewStreamAggregationSum),
+						Drop: make(ViewStreamAggregationDrop),
+					},
+				},
+			},
+			wantErr: "view_stream: must not specify multiple aggregations",
+		},
+		{
+			name: "stream/invalid-boundaries",
+			view: View{
+				Selector: &ViewSelector{
+					InstrumentName: ptr("test_name"),
+				},
+				Stream: &ViewStream{
+					Aggregation: &ViewStreamAggregation{
+						ExplicitBucketHistogram: &ViewStreamAggregationExplicitBucketHistogram{
+							Boundaries: []float64{1, 5, 2},
+						},
+					},
+				},
+			},
+			wantErr: "view_stream: non-monotonic boundaries [1 5 2]",
+		},
+		{
+			name: "stream/invalid-max-scale",
+			view: View{
+				Selector: &ViewSelector{
+					InstrumentName: ptr("test_name"),
+				},
+				Stream: &ViewStream{
+					Aggregation: &ViewStreamAggregation{
+						Base2ExponentialBucketHistogram: &ViewStreamAggregationBase2ExponentialBucketHistogram{
+							MaxScale: ptr(21),
+						},
+					},
+				},
+			},
+			wantErr: "view_stream: invalid max_scale 21",
+		},
+		{
+			name: "stream/invalid-max-size",
+			view: View{
+				Selector: &ViewSelector{
+					InstrumentName: ptr("test_name"),
+				},
+				Stream: &ViewStream{
+					Aggregation: &ViewStreamAggregation{
+						Base2ExponentialBucketHistogram: &ViewStreamAggregationBase2ExponentialBucketHistogram{
+							MaxSize: ptr(0),
+						},
+					},
+				},
+			},
+			wantErr: "view_stream: invalid max_size 0",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := view(tt.view)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				require.Nil(t, got)
+			} else {
+				require.NoError(t, err)
+				gotStream, gotResult := got(*tt.matchInstrument)
+				// Remove filter, since it cannot be compared
+				gotStream.AttributeFilter = nil
+				require.Equal(t, tt.wantStream, gotStream)
+				require.Equal(t, tt.wantResult, gotResult)
+			}
+		})
+	}
+}
+
+func TestAttributeFilter(t *testing.T) {
+	filter := attributeFilter([]string{"foo", "bar"})
+	assert.True(t, filter(attribute.String("foo", "v")))
+	assert.True(t, filter(attribute.String("bar", "v")))
+	assert.False(t, filter(attribute.String("baz", "v")))
+}
+
+func TestAggregation(t *testing.T) {
+	testCases := []struct {
+		name            string
+		aggregation     *ViewStreamAggregation
+		wantAggregation sdkmetric.Aggregation
+	}{
+		{
+			name:            "nil",
+			wantAggregation: nil,
+		},
+		{
+			name:            "empty",
+			aggregation:     &ViewStreamAggregation{},
+			wantAggregation: nil,
+		},
+		{
+			name: "default",
+			aggregation: &ViewStreamAggregation{
+				Default: make(ViewStreamAggregationDefault),
+			},
+			wantAggregation: nil,
+		},
+		{
+			name: "base2_exponential_bucket_histogram",
+			aggregation: &ViewStreamAggregation{
+				Base2ExponentialBucketHistogram: &ViewStreamAggregationBase2ExponentialBucketHistogram{
+					MaxScale:     ptr(2),
+					MaxSize:      ptr(3),
+					RecordMinMax: ptr(true),
+				},
+			},
+			wantAggregation: sdkmetric.AggregationBase2ExponentialHistogram{
+				MaxSize:  3,
+				MaxScale: 2,
+				NoMinMax: false,
+			},
+		},
+		{
+			name: "base2_exponential_bucket_histogram/defaults",
+			aggregation: &ViewStreamAggregation{
+				Base2ExponentialBucketHistogram: &ViewStreamAggregationBase2ExponentialBucketHistogram{},
+			},
+			wantAggregation: sdkmetric.AggregationBase2ExponentialHistogram{
+				MaxSize:  160,
+				MaxScale: 20,
+				NoMinMax: false,
+			},
+		},
+		{
+			name: "drop",
+			aggregation: &ViewStreamAggregation{
+				Drop: make(ViewStreamAggregationDrop),
+			},
+			wantAggregation: sdkmetric.AggregationDrop{},
+		},
+		{
+			name: "explicit_bucket_histogram",
+			aggregation: &ViewStreamAggregation{
+				ExplicitBucketHistogram: &ViewStreamAggregationExplicitBucketHistogram{
+					Boundaries:   []float64{1, 2, 3},
+					RecordMinMax: ptr(false),
+				},
+			},
+			wantAggregation: sdkmetric.AggregationExplicitBucketHistogram{
+				Boundaries: []float64{1, 2, 3},
+				NoMinMax:   true,
+			},
+		},
+		{
+			name: "last_value",
+			aggregation: &ViewStreamAggregation{
+				LastValue: make(ViewStreamAggregationLastValue),
+			},
+			wantAggregation: sdkmetric.AggregationLastValue{},
+		},
+		{
+			name: "sum",
+			aggregation: &ViewStreamAggregation{
+				Sum: make(ViewStreamAggregationSum),
+			},
+			wantAggregation: sdkmetric.AggregationSum{},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := aggregation(tt.aggregation)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantAggregation, got)
+		})
+	}
+}
+
+func TestNewSDKWithMetricReader(t *testing.T) {
+	sdk, err := NewSDK(WithOpenTelemetryConfiguration(OpenTelemetryConfiguration{
+		MeterProvider: &MeterProvider{
+			Readers: []MetricReader{
+				{
+					Periodic: &PeriodicMetricReader{
+						Interval: ptr(int(time.Hour / time.Millisecond)),
+						Exporter: MetricExporter{
+							Console: Console{},
+						},
+					},
+				},
+			},
+		},
+	}))
+	require.NoError(t, err)
+	assert.IsType(t, &sdkmetric.MeterProvider{}, sdk.MeterProvider())
+	require.NoError(t, sdk.Shutdown(context.Background()))
 }
@@ -0,0 +1,326 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package config // import "go.opentelemetry.io/contrib/config"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// supportedFileFormats are the file_format values of the configuration
+// schema the generated model is compatible with.
+var supportedFileFormats = []string{"0.1"}
+
+var (
+	// envReferenceRegexp matches "$$" escape sequences and "${...}"
+	// environment variable references.
+	envReferenceRegexp = regexp.MustCompile(`\$\$|\$\{([^}]*)\}`)
+	// envSubstitutionRegexp matches the contents of a valid "${...}"
+	// environment variable reference: an optional "env:" prefix, the
+	// variable name and an optional ":-" separated default value.
+	envSubstitutionRegexp = regexp.MustCompile(`^(?:env:)?([a-zA-Z_][a-zA-Z0-9_]*)(?::-(.*))?$`)
+)
+
+// ParseYAML parses a YAML configuration file into an OpenTelemetryConfiguration.
+//
+// Environment variable references of the form ${ENV} or ${ENV:-default}
+// in the scalar values of the file are replaced with the value of the
+// environment variable. The substituted values are never parsed as YAML, a
+// value containing a newline or a colon does not change the structure of the
+// document, and references in comments are ignored. Use $$ to write a
+// literal $.
+func ParseYAML(file []byte) (*OpenTelemetryConfiguration, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(file, &doc); err != nil {
+		return nil, err
+	}
+	if err := expandEnvNode(&doc); err != nil {
+		return nil, err
+	}
+
+	var raw interface{}
+	if err := doc.Decode(&raw); err != nil {
+		return nil, err
+	}
+	// Round-trip the YAML document through JSON so the same decoding rules
+	// and validations apply to both file formats.
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	return parseJSON(b)
+}
+
+// ParseJSON parses a JSON configuration file into an OpenTelemetryConfiguration.
+//
+// Environment variable references of the form ${ENV} or ${ENV:-default}
+// in the string values of the file are replaced with the value of the
+// environment variable. The substituted values are never parsed as JSON, a
+// value containing a quote or a backslash does not change the structure of
+// the document. Use $$ to write a literal $.
+func ParseJSON(file []byte) (*OpenTelemetryConfiguration, error) {
+	var raw interface{}
+	if err := json.Unmarshal(file, &raw); err != nil {
+		return nil, err
+	}
+	raw, err := expandEnvValue(raw)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	return parseJSON(b)
+}
+
+func parseJSON(file []byte) (*OpenTelemetryConfiguration, error) {
+	var cfg OpenTelemetryConfiguration
+	if err := json.Unmarshal(file, &cfg); err != nil {
+		return nil, err
+	}
+	if err := checkFileFormat(cfg.FileFormat); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// MarshalYAML returns the YAML encoding of the configuration model cfg.
+//
+// The returned document can be parsed with ParseYAML to reproduce cfg. Any
+// "$" of the string values is escaped as "$$" so it is not interpreted as an
+// environment variable reference when the document is parsed.
+func MarshalYAML(cfg *OpenTelemetryConfiguration) ([]byte, error) {
+	// Encode through JSON so the field names and omitted values of the
+	// document match the ones ParseYAML decodes.
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	escapeNode(&doc)
+	return yaml.Marshal(&doc)
+}
+
+// escapeNode resets the JSON flow style of n and its children to the block
+// style and escapes the "$" of its string scalars.
+func escapeNode(n *yaml.Node) {
+	n.Style = 0
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!str" {
+		n.Value = strings.ReplaceAll(n.Value, "$", "$$")
+	}
+	for _, c := range n.Content {
+		escapeNode(c)
+	}
+}
+
+// marshalObject returns the JSON encoding of v with an empty object added for
+// each of the keys of empty that are true and omitted by the encoding, and
+// with the members of additional. Empty objects are valid values of the
+// configuration schema (e.g. "console: {}") that are otherwise dropped by the
+// omitempty option of the generated model.
+func marshalObject(v interface{}, empty map[string]bool, additional map[string]interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	for k, ok := range empty {
+		if _, found := m[k]; ok && !found {
+			m[k] = json.RawMessage("{}")
+		}
+	}
+	for k, v := range additional {
+		if _, found := m[k]; found {
+			continue
+		}
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		m[k] = raw
+	}
+	return json.Marshal(m)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (j SpanExporter) MarshalJSON() ([]byte, error) {
+	type Plain SpanExporter
+	return marshalObject(Plain(j), map[string]bool{"console": j.Console != nil}, j.AdditionalProperties)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (j SpanProcessor) MarshalJSON() ([]byte, error) {
+	type Plain SpanProcessor
+	return marshalObject(Plain(j), nil, j.AdditionalProperties)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (j MetricExporter) MarshalJSON() ([]byte, error) {
+	type Plain MetricExporter
+	return marshalObject(Plain(j), map[string]bool{"console": j.Console != nil}, j.AdditionalProperties)
+}
+
+// emptyNullMembers returns the JSON object b with the null values of its
+// names members replaced with empty objects. Empty objects are valid values
+// of the configuration schema that YAML documents write as keys without a
+// value (e.g. "console:"), which are decoded as null.
+func emptyNullMembers(b []byte, names ...string) ([]byte, error) {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil || m == nil {
+		// Let the decoding of the model report the invalid values.
+		return b, nil
+	}
+	var replaced bool
+	for _, name := range names {
+		if v, ok := m[name]; ok && string(v) == "null" {
+			m[name] = json.RawMessage("{}")
+			replaced = true
+		}
+	}
+	if !replaced {
+		return b, nil
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *LogRecordExporter) UnmarshalJSON(b []byte) error {
+	b, err := emptyNullMembers(b, "console")
+	if err != nil {
+		return err
+	}
+	type Plain LogRecordExporter
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = LogRecordExporter(plain)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (j LogRecordExporter) MarshalJSON() ([]byte, error) {
+	type Plain LogRecordExporter
+	return marshalObject(Plain(j), map[string]bool{"console": j.Console != nil}, nil)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (j Sampler) MarshalJSON() ([]byte, error) {
+	type Plain Sampler
+	return marshalObject(Plain(j), map[string]bool{
+		"always_off": j.AlwaysOff != nil,
+		"always_on":  j.AlwaysOn != nil,
+	}, j.AdditionalProperties)
+}
+
+func checkFileFormat(fileFormat string) error {
+	for _, f := range supportedFileFormats {
+		if f == fileFormat {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported file_format %q, supported values: %q", fileFormat, supportedFileFormats)
+}
+
+// expandEnvValue replaces the environment variable references in the string
+// values of the decoded JSON value v and its children. Object keys are left
+// unchanged.
+func expandEnvValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case []interface{}:
+		var errs []error
+		for i, e := range v {
+			var err error
+			v[i], err = expandEnvValue(e)
+			errs = append(errs, err)
+		}
+		return v, errors.Join(errs...)
+	case map[string]interface{}:
+		var errs []error
+		for k, e := range v {
+			var err error
+			v[k], err = expandEnvValue(e)
+			errs = append(errs, err)
+		}
+		return v, errors.Join(errs...)
+	case string:
+		if !strings.Contains(v, "$") {
+			return v, nil
+		}
+		return expandEnvString(v)
+	}
+	return v, nil
+}
+
+// expandEnvNode replaces the environment variable references in the scalar
+// values of n and its children. Mapping keys, comments and the other
+// syntactic elements of the document are left unchanged. Plain scalars
+// containing a reference are resolved again once substituted, so that a
+// variable can set a number or a boolean value.
+func expandEnvNode(n *yaml.Node) error {
+	switch n.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		var errs []error
+		for _, c := range n.Content {
+			errs = append(errs, expandEnvNode(c))
+		}
+		return errors.Join(errs...)
+	case yaml.MappingNode:
+		var errs []error
+		for i := 1; i < len(n.Content); i += 2 {
+			errs = append(errs, expandEnvNode(n.Content[i]))
+		}
+		return errors.Join(errs...)
+	case yaml.ScalarNode:
+		if !strings.Contains(n.Value, "$") {
+			return nil
+		}
+		v, err := expandEnvString(n.Value)
+		if err != nil {
+			return err
+		}
+		n.Value = v
+		if n.Style&(yaml.TaggedStyle|yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+			n.Tag = ""
+		}
+	}
+	return nil
+}
+
+// expandEnvString replaces the environment variable references in s.
+// An error is returned for references with an invalid syntax.
+func expandEnvString(s string) (string, error) {
+	var errs []error
+	out := envReferenceRegexp.ReplaceAllStringFunc(s, func(ref string) string {
+		if ref == "$$" {
+			return "$"
+		}
+		m := envSubstitutionRegexp.FindStringSubmatch(ref[2 : len(ref)-1])
+		if m == nil {
+			errs = append(errs, fmt.Errorf("invalid environment variable reference %q", ref))
+			return ref
+		}
+		if v, ok := os.LookupEnv(m[1]); ok && v != "" {
+			return v
+		}
+		return m[2]
+	})
+	if len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+	return out, nil
+}
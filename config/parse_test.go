@@ -0,0 +1,442 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var v01OpenTelemetryConfig = OpenTelemetryConfiguration{
+	Disabled:   ptr(false),
+	FileFormat: "0.1",
+	AttributeLimits: &AttributeLimits{
+		AttributeCountLimit:       ptr(128),
+		AttributeValueLengthLimit: ptr(4096),
+	},
+	Resource: &Resource{
+		Attributes: &Attributes{
+			ServiceName: ptr("unknown_service"),
+		},
+		SchemaUrl: ptr("https://opentelemetry.io/schemas/1.24.0"),
+	},
+	Propagator: &Propagator{
+		Composite: []string{"tracecontext", "baggage"},
+	},
+	TracerProvider: &TracerProvider{
+		Limits: &SpanLimits{
+			AttributeCountLimit: ptr(128),
+			EventCountLimit:     ptr(128),
+		},
+		Processors: []SpanProcessor{
+			{
+				Batch: &BatchSpanProcessor{
+					ExportTimeout:      ptr(30000),
+					MaxExportBatchSize: ptr(512),
+					MaxQueueSize:       ptr(2048),
+					ScheduleDelay:      ptr(5000),
+					Exporter: SpanExporter{
+						OTLP: &OTLP{
+							Compression: ptr("gzip"),
+							Endpoint:    "http://collector:4318",
+							Headers: Headers{
+								"api-key": "${NOT_EXPANDED}",
+							},
+							Protocol: "http/protobuf",
+							Timeout:  ptr(10000),
+						},
+					},
+				},
+			},
+			{
+				Simple: &SimpleSpanProcessor{
+					Exporter: SpanExporter{
+						Console: Console{},
+					},
+				},
+			},
+		},
+		Sampler: &Sampler{
+			ParentBased: &SamplerParentBased{
+				Root: &Sampler{
+					TraceIDRatioBased: &SamplerTraceIDRatioBased{
+						Ratio: ptr(0.0001),
+					},
+				},
+			},
+		},
+	},
+	MeterProvider: &MeterProvider{
+		Readers: []MetricReader{
+			{
+				Pull: &PullMetricReader{
+					Exporter: MetricExporter{
+						Prometheus: &Prometheus{
+							Host: ptr("localhost"),
+							Port: ptr(9464),
+						},
+					},
+				},
+			},
+			{
+				Periodic: &PeriodicMetricReader{
+					Interval: ptr(5000),
+					Timeout:  ptr(30000),
+					Exporter: MetricExporter{
+						OTLP: &OTLPMetric{
+							DefaultHistogramAggregation: ptr(OTLPMetricDefaultHistogramAggregationBase2ExponentialBucketHistogram),
+							Endpoint:                    "http://localhost:4317",
+							Protocol:                    "grpc/protobuf",
+							TemporalityPreference:       ptr("delta"),
+						},
+					},
+				},
+			},
+		},
+		Views: []View{
+			{
+				Selector: &ViewSelector{
+					InstrumentName: ptr("my-instrument"),
+					InstrumentType: ptr(ViewSelectorInstrumentTypeHistogram),
+				},
+				Stream: &ViewStream{
+					Name: ptr("new_instrument_name"),
+					Aggregation: &ViewStreamAggregation{
+						ExplicitBucketHistogram: &ViewStreamAggregationExplicitBucketHistogram{
+							Boundaries:   []float64{0, 5, 10},
+							RecordMinMax: ptr(true),
+						},
+					},
+					AttributeKeys: []string{"key1"},
+				},
+			},
+		},
+	},
+}
+
+func TestParseYAML(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318")
+
+	tests := []struct {
+		name     string
+		input    string
+		wantErr  error
+		wantType *OpenTelemetryConfiguration
+	}{
+		{
+			name:    "valid YAML config",
+			input:   `valid_empty.yaml`,
+			wantErr: nil,
+			wantType: &OpenTelemetryConfiguration{
+				Disabled:   ptr(false),
+				FileFormat: "0.1",
+			},
+		},
+		{
+			name:    "invalid config",
+			input:   "invalid_bool.yaml",
+			wantErr: errors.New("json: cannot unmarshal string into Go struct field Plain.disabled of type bool"),
+		},
+		{
+			name:     "valid v0.1 config",
+			input:    "v0.1.yaml",
+			wantType: &v01OpenTelemetryConfig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := os.ReadFile(filepath.Join("testdata", tt.input))
+			require.NoError(t, err)
+
+			got, err := ParseYAML(b)
+			if tt.wantErr != nil {
+				require.EqualError(t, err, tt.wantErr.Error())
+				require.Nil(t, got)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantType, got)
+			}
+		})
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318")
+
+	b, err := os.ReadFile(filepath.Join("testdata", "v0.1.json"))
+	require.NoError(t, err)
+
+	got, err := ParseJSON(b)
+	require.NoError(t, err)
+	assert.Equal(t, &v01OpenTelemetryConfig, got)
+}
+
+func TestParseJSONEnv(t *testing.T) {
+	t.Setenv("SERVICE_NAME", `my "quoted" \service`)
+	t.Setenv("WITH_QUOTE", `", "file_format": "1.0`)
+
+	got, err := ParseJSON([]byte(`{
+  "file_format": "0.1",
+  "resource": {
+    "attributes": {
+      "service.name": "${SERVICE_NAME}"
+    },
+    "schema_url": "${WITH_QUOTE}"
+  }
+}`))
+	require.NoError(t, err)
+	assert.Equal(t, "0.1", got.FileFormat)
+	assert.Equal(t, `my "quoted" \service`, *got.Resource.Attributes.ServiceName)
+	assert.Equal(t, `", "file_format": "1.0`, *got.Resource.SchemaUrl)
+
+	_, err = ParseJSON([]byte(`{"file_format": "${1INVALID}"}`))
+	require.EqualError(t, err, `invalid environment variable reference "${1INVALID}"`)
+}
+
+func TestParseYAMLNullObjects(t *testing.T) {
+	got, err := ParseYAML([]byte(`
+file_format: "0.1"
+tracer_provider:
+  processors:
+    - simple:
+        exporter:
+          console:
+  sampler:
+    parent_based:
+      root:
+        always_on:
+      remote_parent_sampled:
+        always_off:
+meter_provider:
+  readers:
+    - periodic:
+        exporter:
+          console:
+logger_provider:
+  processors:
+    - simple:
+        exporter:
+          console:
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, Console{}, got.TracerProvider.Processors[0].Simple.Exporter.Console)
+	assert.Equal(t, &Sampler{
+		ParentBased: &SamplerParentBased{
+			Root:                &Sampler{AlwaysOn: SamplerAlwaysOn{}},
+			RemoteParentSampled: &Sampler{AlwaysOff: SamplerAlwaysOff{}},
+		},
+	}, got.TracerProvider.Sampler)
+	assert.Equal(t, Console{}, got.MeterProvider.Readers[0].Periodic.Exporter.Console)
+	assert.Equal(t, Console{}, got.LoggerProvider.Processors[0].Simple.Exporter.Console)
+
+	sdk, err := NewSDK(WithOpenTelemetryConfiguration(*got))
+	require.NoError(t, err)
+	require.NoError(t, sdk.Shutdown(context.Background()))
+
+	got, err = ParseYAML([]byte(`
+file_format: "0.1"
+tracer_provider:
+  sampler:
+    parent_based:
+`))
+	require.NoError(t, err)
+	assert.Equal(t, &Sampler{ParentBased: &SamplerParentBased{}}, got.TracerProvider.Sampler)
+}
+
+func TestParseYAMLEnv(t *testing.T) {
+	t.Setenv("SERVICE_NAME", "my-service")
+	t.Setenv("MULTILINE", "line\ndisabled: true")
+	t.Setenv("WITH_COLON", "key: value")
+	t.Setenv("RATIO", "0.5")
+	t.Setenv("DISABLED", "false")
+
+	got, err := ParseYAML([]byte(`
+file_format: "0.1"
+# ${not valid} references in comments are not substituted.
+disabled: ${DISABLED}
+resource:
+  attributes:
+    service.name: ${SERVICE_NAME} # ${SERVICE_NAME}
+  schema_url: "${WITH_COLON}"
+tracer_provider:
+  sampler:
+    trace_id_ratio_based:
+      ratio: ${RATIO}
+  processors:
+    - simple:
+        exporter:
+          zipkin:
+            endpoint: ${MULTILINE}
+`))
+	require.NoError(t, err)
+	assert.False(t, *got.Disabled)
+	assert.Equal(t, "my-service", *got.Resource.Attributes.ServiceName)
+	assert.Equal(t, "key: value", *got.Resource.SchemaUrl)
+	assert.Equal(t, 0.5, *got.TracerProvider.Sampler.TraceIDRatioBased.Ratio)
+	assert.Equal(t, "line\ndisabled: true", got.TracerProvider.Processors[0].Simple.Exporter.Zipkin.Endpoint)
+
+	t.Setenv("MULTILINE", "line\nfile_format: \"1.0\"")
+	got, err = ParseYAML([]byte(`
+file_format: "0.1"
+resource:
+  schema_url: ${MULTILINE}
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "0.1", got.FileFormat)
+	assert.Equal(t, "line\nfile_format: \"1.0\"", *got.Resource.SchemaUrl)
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		parse   func([]byte) (*OpenTelemetryConfiguration, error)
+		input   string
+		wantErr string
+	}{
+		{
+			name:    "yaml/missing-file-format",
+			parse:   ParseYAML,
+			input:   "disabled: true",
+			wantErr: "field file_format in OpenTelemetryConfiguration: required",
+		},
+		{
+			name:    "yaml/unsupported-file-format",
+			parse:   ParseYAML,
+			input:   `file_format: "1.0"`,
+			wantErr: `unsupported file_format "1.0", supported values: ["0.1"]`,
+		},
+		{
+			name:  "yaml/missing-required-exporter",
+			parse: ParseYAML,
+			input: `
+file_format: "0.1"
+tracer_provider:
+  processors:
+    - batch:
+        schedule_delay: 5000
+`,
+			wantErr: "field exporter in BatchSpanProcessor: required",
+		},
+		{
+			name:  "yaml/invalid-enum",
+			parse: ParseYAML,
+			input: `
+file_format: "0.1"
+meter_provider:
+  views:
+    - selector:
+        instrument_type: invalid
+`,
+			wantErr: `invalid value (expected one of []interface {}{"counter", "histogram", "observable_counter", "observable_gauge", "observable_up_down_counter", "up_down_counter"}): "invalid"`,
+		},
+		{
+			name:    "yaml/invalid-env-reference",
+			parse:   ParseYAML,
+			input:   `file_format: "${1INVALID}"`,
+			wantErr: `invalid environment variable reference "${1INVALID}"`,
+		},
+		{
+			name:    "json/invalid-syntax",
+			parse:   ParseJSON,
+			input:   `{"file_format": "0.1"`,
+			wantErr: "unexpected end of JSON input",
+		},
+		{
+			name:    "json/unsupported-file-format",
+			parse:   ParseJSON,
+			input:   `{"file_format": "0.2"}`,
+			wantErr: `unsupported file_format "0.2", supported values: ["0.1"]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.parse([]byte(tt.input))
+			require.EqualError(t, err, tt.wantErr)
+			require.Nil(t, got)
+		})
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("STRING_VALUE", "value")
+	t.Setenv("EMPTY_VALUE", "")
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "no-reference",
+			input: "key: value",
+			want:  "key: value",
+		},
+		{
+			name:  "reference",
+			input: "key: ${STRING_VALUE}",
+			want:  "key: value",
+		},
+		{
+			name:  "env-prefixed-reference",
+			input: "key: ${env:STRING_VALUE}",
+			want:  "key: value",
+		},
+		{
+			name:  "undefined-reference",
+			input: "key: ${UNDEFINED_VALUE}",
+			want:  "key: ",
+		},
+		{
+			name:  "default-value",
+			input: "key: ${UNDEFINED_VALUE:-fallback}",
+			want:  "key: fallback",
+		},
+		{
+			name:  "default-value-for-empty-variable",
+			input: "key: ${EMPTY_VALUE:-fallback}",
+			want:  "key: fallback",
+		},
+		{
+			name:  "default-value-ignored",
+			input: "key: ${STRING_VALUE:-fallback}",
+			want:  "key: value",
+		},
+		{
+			name:  "escaped",
+			input: "key: $${STRING_VALUE}",
+			want:  "key: ${STRING_VALUE}",
+		},
+		{
+			name:  "multiple-references",
+			input: "key: ${STRING_VALUE}-${UNDEFINED_VALUE:-x}-$$",
+			want:  "key: value-x-$",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandEnvString(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMarshalYAML(t *testing.T) {
+	b, err := MarshalYAML(&v01OpenTelemetryConfig)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "api-key: $${NOT_EXPANDED}")
+	assert.Contains(t, string(b), "console: {}")
+
+	got, err := ParseYAML(b)
+	require.NoError(t, err)
+	assert.Equal(t, &v01OpenTelemetryConfig, got)
+}
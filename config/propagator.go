@@ -0,0 +1,37 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package config // import "go.opentelemetry.io/contrib/config"
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/contrib/propagators/autoprop"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// propagator returns the TextMapPropagator composed from the propagator
+// names of the configuration model. The names are resolved using the
+// autoprop registry, so propagators registered with
+// autoprop.RegisterTextMapPropagator can be used as well.
+func propagator(cfg configOptions) (propagation.TextMapPropagator, error) {
+	if cfg.opentelemetryConfig.Propagator == nil {
+		return propagation.NewCompositeTextMapPropagator(), nil
+	}
+
+	var names []string
+	for _, name := range cfg.opentelemetryConfig.Propagator.Composite {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return propagation.NewCompositeTextMapPropagator(), nil
+	}
+
+	p, err := autoprop.TextMapPropagator(names...)
+	if err != nil {
+		return nil, fmt.Errorf("propagator composite: %w", err)
+	}
+	return p, nil
+}
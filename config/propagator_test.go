@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestPropagator(t *testing.T) {
+	member, err := baggage.NewMember("key", "value")
+	require.NoError(t, err)
+	bag, err := baggage.New(member)
+	require.NoError(t, err)
+	ctx := baggage.ContextWithBaggage(context.Background(), bag)
+	ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x01},
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	for _, tt := range []struct {
+		name        string
+		propagator  *Propagator
+		wantHeaders []string
+		wantErr     string
+	}{
+		{
+			name: "no-propagator-configured",
+		},
+		{
+			name:       "empty-composite",
+			propagator: &Propagator{Composite: []string{""}},
+		},
+		{
+			name:        "tracecontext-baggage",
+			propagator:  &Propagator{Composite: []string{"tracecontext", "baggage"}},
+			wantHeaders: []string{"traceparent", "baggage"},
+		},
+		{
+			name:        "b3",
+			propagator:  &Propagator{Composite: []string{"b3"}},
+			wantHeaders: []string{"b3"},
+		},
+		{
+			name:        "b3multi",
+			propagator:  &Propagator{Composite: []string{"b3multi"}},
+			wantHeaders: []string{"x-b3-traceid", "x-b3-spanid", "x-b3-sampled"},
+		},
+		{
+			name:        "jaeger-xray-ottrace",
+			propagator:  &Propagator{Composite: []string{"jaeger", "xray", "ottrace"}},
+			wantHeaders: []string{"uber-trace-id", "X-Amzn-Trace-Id", "ot-tracer-traceid", "ot-tracer-spanid", "ot-tracer-sampled", "ot-baggage-key"},
+		},
+		{
+			name:       "none",
+			propagator: &Propagator{Composite: []string{"tracecontext", "none"}},
+		},
+		{
+			name:       "unknown-propagator",
+			propagator: &Propagator{Composite: []string{"tracecontext", "invalid", "unknown"}},
+			wantErr:    "propagator composite: unknown propagator: invalid,unknown",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := propagator(configOptions{
+				opentelemetryConfig: OpenTelemetryConfiguration{
+					Propagator: tt.propagator,
+				},
+			})
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				require.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			carrier := propagation.MapCarrier{}
+			got.Inject(ctx, carrier)
+			assert.ElementsMatch(t, tt.wantHeaders, carrier.Keys())
+		})
+	}
+}
@@ -0,0 +1,294 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package config // import "go.opentelemetry.io/contrib/config"
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/contrib/propagators/autoprop"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SpanExporterFactory creates a custom span exporter from its settings in
+// the configuration model.
+type SpanExporterFactory func(ctx context.Context, cfg map[string]interface{}) (sdktrace.SpanExporter, error)
+
+// SpanProcessorFactory creates a custom span processor from its settings in
+// the configuration model.
+type SpanProcessorFactory func(ctx context.Context, cfg map[string]interface{}) (sdktrace.SpanProcessor, error)
+
+// MetricExporterFactory creates a custom metric exporter from its settings
+// in the configuration model. Custom metric exporters are used with a
+// periodic metric reader.
+type MetricExporterFactory func(ctx context.Context, cfg map[string]interface{}) (sdkmetric.Exporter, error)
+
+// SamplerFactory creates a custom sampler from its settings in the
+// configuration model.
+type SamplerFactory func(ctx context.Context, cfg map[string]interface{}) (sdktrace.Sampler, error)
+
+// errDuplicateRegistration is returned when a name is registered twice.
+var errDuplicateRegistration = errors.New("duplicate registration")
+
+// registry maintains a map of component names to factories that is safe for
+// concurrent use by multiple goroutines without additional locking or
+// coordination.
+type registry[T any] struct {
+	mu    sync.Mutex
+	names map[string]func(context.Context, map[string]interface{}) (T, error)
+	// builtin are the names of the components defined by the configuration
+	// schema, which cannot be registered.
+	builtin map[string]bool
+}
+
+func newRegistry[T any](model interface{}) *registry[T] {
+	return &registry[T]{
+		names:   make(map[string]func(context.Context, map[string]interface{}) (T, error)),
+		builtin: jsonFieldNames(reflect.TypeOf(model)),
+	}
+}
+
+// store sets the factory for name. An error is returned if name is already
+// registered or is defined by the configuration schema.
+func (r *registry[T]) store(name string, factory func(context.Context, map[string]interface{}) (T, error)) error {
+	if name == "" {
+		return errors.New("empty name")
+	}
+	if factory == nil {
+		return fmt.Errorf("nil factory for %q", name)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.names[name]; ok || r.builtin[name] {
+		return fmt.Errorf("%w: %q", errDuplicateRegistration, name)
+	}
+	r.names[name] = factory
+	return nil
+}
+
+// load returns the component created by the factory registered with name
+// from its settings cfg.
+func (r *registry[T]) load(ctx context.Context, name string, cfg interface{}) (T, error) {
+	var zero T
+	r.mu.Lock()
+	factory, ok := r.names[name]
+	r.mu.Unlock()
+	if !ok {
+		return zero, fmt.Errorf("unsupported type %q, supported values: %q", name, r.supported())
+	}
+
+	var settings map[string]interface{}
+	if cfg != nil {
+		if settings, ok = cfg.(map[string]interface{}); !ok {
+			return zero, fmt.Errorf("%s: invalid settings type %T", name, cfg)
+		}
+	}
+	v, err := factory(ctx, settings)
+	if err != nil {
+		return zero, fmt.Errorf("%s: %w", name, err)
+	}
+	return v, nil
+}
+
+// registered returns whether a factory is registered with name.
+func (r *registry[T]) registered(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.names[name]
+	return ok
+}
+
+// supported returns the sorted names of the components defined by the
+// configuration schema and of the registered ones.
+func (r *registry[T]) supported() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	supported := make([]string, 0, len(r.names)+len(r.builtin))
+	for k := range r.names {
+		supported = append(supported, k)
+	}
+	for k := range r.builtin {
+		supported = append(supported, k)
+	}
+	sort.Strings(supported)
+	return supported
+}
+
+var (
+	spanExporters   = newRegistry[sdktrace.SpanExporter](SpanExporter{})
+	spanProcessors  = newRegistry[sdktrace.SpanProcessor](SpanProcessor{})
+	metricExporters = newRegistry[sdkmetric.Exporter](MetricExporter{})
+	samplers        = newRegistry[sdktrace.Sampler](Sampler{})
+)
+
+// RegisterSpanExporter sets the factory used to create the span exporters
+// configured with name, e.g. "my_exporter" for the following exporter of a
+// span processor:
+//
+//	exporter:
+//	  my_exporter:
+//	    endpoint: https://example.com
+//
+// The factory receives the decoded settings of the exporter. This will
+// panic if name has already been registered or is defined by the
+// configuration schema (e.g. "otlp").
+func RegisterSpanExporter(name string, factory SpanExporterFactory) {
+	must(spanExporters.store(name, factory))
+}
+
+// RegisterSpanProcessor sets the factory used to create the span processors
+// configured with name. The factory receives the decoded settings of the
+// processor. This will panic if name has already been registered or is
+// defined by the configuration schema (e.g. "batch").
+func RegisterSpanProcessor(name string, factory SpanProcessorFactory) {
+	must(spanProcessors.store(name, factory))
+}
+
+// RegisterMetricExporter sets the factory used to create the metric
+// exporters of periodic metric readers configured with name. The factory
+// receives the decoded settings of the exporter. This will panic if name has
+// already been registered or is defined by the configuration schema (e.g.
+// "otlp").
+func RegisterMetricExporter(name string, factory MetricExporterFactory) {
+	must(metricExporters.store(name, factory))
+}
+
+// RegisterSampler sets the factory used to create the samplers configured
+// with name. The factory receives the decoded settings of the sampler. This
+// will panic if name has already been registered or is defined by the
+// configuration schema (e.g. "parent_based").
+func RegisterSampler(name string, factory SamplerFactory) {
+	must(samplers.store(name, factory))
+}
+
+// RegisterPropagator sets the TextMapPropagator p to be used when the
+// composite propagator of the configuration model contains name. The
+// propagator is registered with autoprop.RegisterTextMapPropagator, so
+// this will panic if name has already been registered or is one of the
+// propagators supported by default (e.g. "tracecontext").
+func RegisterPropagator(name string, p propagation.TextMapPropagator) {
+	autoprop.RegisterTextMapPropagator(name, p)
+}
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+// jsonFieldNames returns the JSON names of the fields of the struct type t.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// unmarshalAdditionalProperties decodes the members of the JSON object b
+// that are not fields of the struct type of model.
+func unmarshalAdditionalProperties(b []byte, model interface{}) (map[string]interface{}, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+	for name := range jsonFieldNames(reflect.TypeOf(model)) {
+		delete(raw, name)
+	}
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	return raw, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Custom exporters are decoded
+// into AdditionalProperties.
+func (j *SpanExporter) UnmarshalJSON(b []byte) error {
+	b, err := emptyNullMembers(b, "console")
+	if err != nil {
+		return err
+	}
+	type Plain SpanExporter
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	additional, err := unmarshalAdditionalProperties(b, plain)
+	if err != nil {
+		return err
+	}
+	plain.AdditionalProperties = additional
+	*j = SpanExporter(plain)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Custom processors are decoded
+// into AdditionalProperties.
+func (j *SpanProcessor) UnmarshalJSON(b []byte) error {
+	type Plain SpanProcessor
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	additional, err := unmarshalAdditionalProperties(b, plain)
+	if err != nil {
+		return err
+	}
+	plain.AdditionalProperties = additional
+	*j = SpanProcessor(plain)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Custom exporters are decoded
+// into AdditionalProperties.
+func (j *MetricExporter) UnmarshalJSON(b []byte) error {
+	b, err := emptyNullMembers(b, "console")
+	if err != nil {
+		return err
+	}
+	type Plain MetricExporter
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	additional, err := unmarshalAdditionalProperties(b, plain)
+	if err != nil {
+		return err
+	}
+	plain.AdditionalProperties = additional
+	*j = MetricExporter(plain)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Custom samplers are decoded
+// into AdditionalProperties.
+func (j *Sampler) UnmarshalJSON(b []byte) error {
+	b, err := emptyNullMembers(b, "always_off", "always_on", "parent_based")
+	if err != nil {
+		return err
+	}
+	type Plain Sampler
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	additional, err := unmarshalAdditionalProperties(b, plain)
+	if err != nil {
+		return err
+	}
+	plain.AdditionalProperties = additional
+	*j = Sampler(plain)
+	return nil
+}
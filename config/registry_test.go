@@ -0,0 +1,177 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// resetRegistries replaces the registries with empty ones for the duration
+// of the test t.
+func resetRegistries(t *testing.T) {
+	se, sp, me, s := spanExporters, spanProcessors, metricExporters, samplers
+	spanExporters = newRegistry[sdktrace.SpanExporter](SpanExporter{})
+	spanProcessors = newRegistry[sdktrace.SpanProcessor](SpanProcessor{})
+	metricExporters = newRegistry[sdkmetric.Exporter](MetricExporter{})
+	samplers = newRegistry[sdktrace.Sampler](Sampler{})
+	t.Cleanup(func() {
+		spanExporters, spanProcessors, metricExporters, samplers = se, sp, me, s
+	})
+}
+
+func TestRegistry(t *testing.T) {
+	r := newRegistry[string](SpanExporter{})
+	factory := func(_ context.Context, cfg map[string]interface{}) (string, error) {
+		if cfg["fail"] == true {
+			return "", errors.New("failed")
+		}
+		return "created", nil
+	}
+
+	require.NoError(t, r.store("custom", factory))
+	assert.ErrorIs(t, r.store("custom", factory), errDuplicateRegistration)
+	assert.ErrorIs(t, r.store("otlp", factory), errDuplicateRegistration)
+	assert.EqualError(t, r.store("", factory), "empty name")
+	assert.EqualError(t, r.store("nil", nil), `nil factory for "nil"`)
+
+	v, err := r.load(context.Background(), "custom", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "created", v)
+
+	_, err = r.load(context.Background(), "custom", map[string]interface{}{"fail": true})
+	assert.EqualError(t, err, "custom: failed")
+
+	_, err = r.load(context.Background(), "custom", "settings")
+	assert.EqualError(t, err, "custom: invalid settings type string")
+
+	_, err = r.load(context.Background(), "unknown", nil)
+	assert.EqualError(t, err, `unsupported type "unknown", supported values: ["console" "custom" "otlp" "zipkin"]`)
+}
+
+func TestRegisterPanics(t *testing.T) {
+	assert.Panics(t, func() {
+		RegisterSpanExporter("otlp", func(context.Context, map[string]interface{}) (sdktrace.SpanExporter, error) {
+			return nil, nil
+		})
+	})
+	assert.Panics(t, func() {
+		RegisterSpanProcessor("batch", func(context.Context, map[string]interface{}) (sdktrace.SpanProcessor, error) {
+			return nil, nil
+		})
+	})
+	assert.Panics(t, func() {
+		RegisterMetricExporter("prometheus", func(context.Context, map[string]interface{}) (sdkmetric.Exporter, error) {
+			return nil, nil
+		})
+	})
+	assert.Panics(t, func() {
+		RegisterSampler("parent_based", func(context.Context, map[string]interface{}) (sdktrace.Sampler, error) {
+			return nil, nil
+		})
+	})
+	assert.Panics(t, func() {
+		RegisterPropagator("tracecontext", propagation.TraceContext{})
+	})
+}
+
+const customComponentsConfig = `
+file_format: "0.1"
+tracer_provider:
+  sampler:
+    parent_based:
+      root:
+        custom_sampler:
+          decision: drop
+  processors:
+    - custom_processor:
+        name: first
+    - simple:
+        exporter:
+          custom_exporter:
+            endpoint: https://example.com
+meter_provider:
+  readers:
+    - periodic:
+        exporter:
+          custom_metric_exporter:
+`
+
+func TestNewSDKCustomComponents(t *testing.T) {
+	resetRegistries(t)
+
+	settings := make(map[string]map[string]interface{})
+	spanRecorder := tracetest.NewSpanRecorder()
+	spanExporter := tracetest.NewInMemoryExporter()
+	RegisterSampler("custom_sampler", func(_ context.Context, cfg map[string]interface{}) (sdktrace.Sampler, error) {
+		settings["sampler"] = cfg
+		return sdktrace.AlwaysSample(), nil
+	})
+	RegisterSpanProcessor("custom_processor", func(_ context.Context, cfg map[string]interface{}) (sdktrace.SpanProcessor, error) {
+		settings["processor"] = cfg
+		return spanRecorder, nil
+	})
+	RegisterSpanExporter("custom_exporter", func(_ context.Context, cfg map[string]interface{}) (sdktrace.SpanExporter, error) {
+		settings["exporter"] = cfg
+		return spanExporter, nil
+	})
+	RegisterMetricExporter("custom_metric_exporter", func(_ context.Context, cfg map[string]interface{}) (sdkmetric.Exporter, error) {
+		settings["metric_exporter"] = cfg
+		return stdoutmetric.New(stdoutmetric.WithWriter(io.Discard))
+	})
+
+	cfg, err := ParseYAML([]byte(customComponentsConfig))
+	require.NoError(t, err)
+
+	sdk, err := NewSDK(WithOpenTelemetryConfiguration(*cfg))
+	require.NoError(t, err)
+	_, span := sdk.TracerProvider().Tracer("test").Start(context.Background(), "span")
+	span.End()
+	assert.Len(t, spanRecorder.Ended(), 1)
+	assert.Len(t, spanExporter.GetSpans(), 1)
+	require.NoError(t, sdk.Shutdown(context.Background()))
+
+	assert.Equal(t, map[string]map[string]interface{}{
+		"sampler":         {"decision": "drop"},
+		"processor":       {"name": "first"},
+		"exporter":        {"endpoint": "https://example.com"},
+		"metric_exporter": nil,
+	}, settings)
+
+	b, err := MarshalYAML(cfg)
+	require.NoError(t, err)
+	got, err := ParseYAML(b)
+	require.NoError(t, err)
+	assert.Equal(t, cfg, got)
+}
+
+func TestNewSDKCustomComponentsErrors(t *testing.T) {
+	resetRegistries(t)
+
+	_, err := NewSDK(WithOpenTelemetryConfiguration(OpenTelemetryConfiguration{
+		TracerProvider: &TracerProvider{
+			Sampler: &Sampler{
+				AlwaysOn:             SamplerAlwaysOn{},
+				AdditionalProperties: map[string]interface{}{"custom": nil},
+			},
+			Processors: []SpanProcessor{
+				{AdditionalProperties: map[string]interface{}{"unknown": nil}},
+			},
+		},
+	}))
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "must not specify multiple sampler types")
+	assert.ErrorContains(t, err, `unsupported type "unknown", supported values: ["batch" "simple"]`)
+}
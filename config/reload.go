@@ -0,0 +1,572 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package config // import "go.opentelemetry.io/contrib/config"
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	logembedded "go.opentelemetry.io/otel/log/embedded"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	traceembedded "go.opentelemetry.io/otel/trace/embedded"
+)
+
+const (
+	// defaultReloadInterval is the default interval at which a
+	// ReloadableSDK checks its configuration file for changes.
+	defaultReloadInterval = 10 * time.Second
+	// defaultReloadGracePeriod is the default maximum duration a
+	// ReloadableSDK waits for the spans of the previous configuration to
+	// end before shutting down its providers.
+	defaultReloadGracePeriod = 5 * time.Second
+)
+
+var errReloadableSDKShutdown = errors.New("reloadable sdk is shut down")
+
+// WithReloadInterval sets the interval at which the SDK returned by
+// NewReloadableSDK checks its configuration file for changes. A zero or
+// negative interval disables the watching of the file, the configuration is
+// then only reloaded by calls to ReloadableSDK.Reload.
+//
+// This option is ignored by NewSDK.
+func WithReloadInterval(d time.Duration) ConfigurationOption {
+	return configurationOptionFunc(func(c configOptions) configOptions {
+		c.reloadInterval = &d
+		return c
+	})
+}
+
+// WithReloadGracePeriod sets the maximum duration the SDK returned by
+// NewReloadableSDK waits, once the configuration is reloaded, for the spans
+// started with the previous configuration to end before shutting down its
+// providers. Spans that end after the grace period are dropped. The default
+// is 5 seconds.
+//
+// This option is ignored by NewSDK.
+func WithReloadGracePeriod(d time.Duration) ConfigurationOption {
+	return configurationOptionFunc(func(c configOptions) configOptions {
+		c.reloadGracePeriod = &d
+		return c
+	})
+}
+
+// withPrometheusServers shares the HTTP servers of the Prometheus pull readers
+// of the SDK with the readers of the other SDKs created with r.
+func withPrometheusServers(r *prometheusServerRegistry) ConfigurationOption {
+	return configurationOptionFunc(func(c configOptions) configOptions {
+		c.prometheusServers = r
+		return c
+	})
+}
+
+// withInFlightSpans counts the spans started and not yet ended by the
+// TracerProvider of the SDK with f.
+func withInFlightSpans(f *inFlightSpans) ConfigurationOption {
+	return configurationOptionFunc(func(c configOptions) configOptions {
+		c.inFlightSpans = f
+		return c
+	})
+}
+
+// ReloadableSDK is an SDK configured from a configuration file that is
+// reloaded when the file changes.
+//
+// The providers returned by a ReloadableSDK are stable: the tracers, meters,
+// loggers and instruments created from them delegate to the providers
+// configured by the latest valid version of the file. When the file changes,
+// new providers are created and swapped in atomically. The spans started
+// with the previous providers are then given the grace period set with
+// WithReloadGracePeriod to end, after which the previous providers are shut
+// down, flushing their pending telemetry.
+//
+// Prometheus pull readers of consecutive versions of the file configured with
+// the same host and port share their HTTP server, which serves the metrics of
+// the latest version once it is swapped in.
+//
+// If the new version of the file cannot be parsed or is invalid, the error is
+// reported to the global error handler and the providers of the previous
+// version are kept.
+type ReloadableSDK struct {
+	path        string
+	opts        []ConfigurationOption
+	ctx         context.Context
+	gracePeriod time.Duration
+
+	tracerProvider *reloadableTracerProvider
+	meterProvider  *reloadableMeterProvider
+	loggerProvider *reloadableLoggerProvider
+	propagator     *reloadablePropagator
+
+	// prometheusServers are shared by the Prometheus pull readers of the
+	// consecutive configurations.
+	prometheusServers *prometheusServerRegistry
+
+	// mu serializes reloads and the shutdown.
+	mu       sync.Mutex
+	current  loadedSDK
+	file     []byte
+	shutdown bool
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewReloadableSDK creates a ReloadableSDK from the YAML or, if the name of
+// the file has the .json extension, JSON configuration file at path. An
+// error is returned if the initial version of the file cannot be parsed or is
+// invalid.
+//
+// The file is checked for changes at the interval set with
+// WithReloadInterval, every 10 seconds by default. The ReloadableSDK must be
+// shut down to stop watching the file.
+func NewReloadableSDK(path string, opts ...ConfigurationOption) (*ReloadableSDK, error) {
+	o := configOptions{
+		ctx: context.Background(),
+	}
+	for _, opt := range opts {
+		o = opt.apply(o)
+	}
+
+	s := &ReloadableSDK{
+		path:        path,
+		opts:        opts,
+		ctx:         o.ctx,
+		gracePeriod: defaultReloadGracePeriod,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+
+		prometheusServers: newPrometheusServerRegistry(),
+	}
+	if o.reloadGracePeriod != nil {
+		s.gracePeriod = *o.reloadGracePeriod
+	}
+	file, loaded, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	s.file, s.current = file, loaded
+	s.tracerProvider = newReloadableTracerProvider(loaded.sdk.TracerProvider())
+	s.meterProvider = newReloadableMeterProvider(loaded.sdk.MeterProvider())
+	s.loggerProvider = newReloadableLoggerProvider(loaded.sdk.LoggerProvider())
+	s.propagator = newReloadablePropagator(loaded.sdk.TextMapPropagator())
+
+	interval := defaultReloadInterval
+	if o.reloadInterval != nil {
+		interval = *o.reloadInterval
+	}
+	if interval > 0 {
+		go s.watch(interval)
+	} else {
+		close(s.done)
+	}
+	return s, nil
+}
+
+// TracerProvider returns a trace.TracerProvider that delegates to the
+// TracerProvider of the current configuration.
+func (s *ReloadableSDK) TracerProvider() trace.TracerProvider {
+	return s.tracerProvider
+}
+
+// MeterProvider returns a metric.MeterProvider that delegates to the
+// MeterProvider of the current configuration.
+func (s *ReloadableSDK) MeterProvider() metric.MeterProvider {
+	return s.meterProvider
+}
+
+// LoggerProvider returns a log.LoggerProvider that delegates to the
+// LoggerProvider of the current configuration.
+func (s *ReloadableSDK) LoggerProvider() log.LoggerProvider {
+	return s.loggerProvider
+}
+
+// TextMapPropagator returns a propagation.TextMapPropagator that delegates to
+// the propagator of the current configuration.
+func (s *ReloadableSDK) TextMapPropagator() propagation.TextMapPropagator {
+	return s.propagator
+}
+
+// Reload reloads the configuration file, even if it has not changed. If the
+// file cannot be parsed or is invalid, the error is returned and the current
+// configuration is kept.
+func (s *ReloadableSDK) Reload(ctx context.Context) error {
+	s.mu.Lock()
+	previous, err := s.reload(false)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return s.retire(ctx, previous)
+}
+
+// Shutdown stops watching the configuration file and shuts down the
+// providers of the current configuration.
+func (s *ReloadableSDK) Shutdown(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stop) })
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.shutdown {
+		return nil
+	}
+	s.shutdown = true
+	return s.current.sdk.Shutdown(ctx)
+}
+
+func (s *ReloadableSDK) watch(interval time.Duration) {
+	defer close(s.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			previous, err := s.reload(true)
+			s.mu.Unlock()
+			if err == nil {
+				err = s.retire(s.ctx, previous)
+			}
+			if err != nil {
+				otel.Handle(fmt.Errorf("reload configuration file %q: %w", s.path, err))
+			}
+		}
+	}
+}
+
+// reload swaps the providers for the ones of the configuration file and
+// returns the SDK of the previous configuration, to be retired by the caller
+// once s.mu is released. If onChange is true, the file is only reloaded if it
+// changed since it was last read, nil is returned otherwise. The caller must
+// hold s.mu.
+func (s *ReloadableSDK) reload(onChange bool) (*loadedSDK, error) {
+	if s.shutdown {
+		return nil, errReloadableSDKShutdown
+	}
+	if onChange {
+		file, err := os.ReadFile(s.path)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(file, s.file) {
+			return nil, nil
+		}
+	}
+
+	file, loaded, err := s.load()
+	// An invalid file is not reloaded again until it changes.
+	s.file = file
+	if err != nil {
+		return nil, err
+	}
+
+	previous := s.current
+	s.current = loaded
+	s.tracerProvider.setDelegate(loaded.sdk.TracerProvider())
+	s.meterProvider.setDelegate(loaded.sdk.MeterProvider())
+	s.loggerProvider.setDelegate(loaded.sdk.LoggerProvider())
+	s.propagator.setDelegate(loaded.sdk.TextMapPropagator())
+	return &previous, nil
+}
+
+// retire shuts down the SDK of a previous configuration, if any, once its
+// spans ended or the grace period elapsed. It is called without s.mu held so
+// that the grace period does not block the other reloads and the shutdown.
+func (s *ReloadableSDK) retire(ctx context.Context, previous *loadedSDK) error {
+	if previous == nil {
+		return nil
+	}
+	// The spans started with the previous providers are dropped if they end
+	// after the providers are shut down.
+	previous.inFlightSpans.wait(ctx, s.gracePeriod)
+	return previous.sdk.Shutdown(ctx)
+}
+
+// loadedSDK is the SDK created from a version of the configuration file.
+type loadedSDK struct {
+	sdk           SDK
+	inFlightSpans *inFlightSpans
+}
+
+// load reads the configuration file and creates the SDK it configures.
+func (s *ReloadableSDK) load() ([]byte, loadedSDK, error) {
+	file, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, loadedSDK{}, err
+	}
+	parse := ParseYAML
+	if strings.EqualFold(filepath.Ext(s.path), ".json") {
+		parse = ParseJSON
+	}
+	cfg, err := parse(file)
+	if err != nil {
+		return file, loadedSDK{}, err
+	}
+	inFlight := &inFlightSpans{}
+	opts := append([]ConfigurationOption{}, s.opts...)
+	opts = append(opts, WithOpenTelemetryConfiguration(*cfg), withInFlightSpans(inFlight), withPrometheusServers(s.prometheusServers))
+	sdk, err := NewSDK(opts...)
+	if err != nil {
+		return file, loadedSDK{}, err
+	}
+	return file, loadedSDK{sdk: sdk, inFlightSpans: inFlight}, nil
+}
+
+// inFlightSpans is a span processor counting the spans started and not yet
+// ended.
+type inFlightSpans struct {
+	mu sync.Mutex
+	n  int
+	// ended is closed when n drops to zero, if it is waited for.
+	ended chan struct{}
+}
+
+var _ sdktrace.SpanProcessor = (*inFlightSpans)(nil)
+
+// OnStart implements sdktrace.SpanProcessor.
+func (f *inFlightSpans) OnStart(context.Context, sdktrace.ReadWriteSpan) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.n++
+}
+
+// OnEnd implements sdktrace.SpanProcessor.
+func (f *inFlightSpans) OnEnd(sdktrace.ReadOnlySpan) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.n--
+	if f.n == 0 && f.ended != nil {
+		close(f.ended)
+		f.ended = nil
+	}
+}
+
+// Shutdown implements sdktrace.SpanProcessor.
+func (*inFlightSpans) Shutdown(context.Context) error { return nil }
+
+// ForceFlush implements sdktrace.SpanProcessor.
+func (*inFlightSpans) ForceFlush(context.Context) error { return nil }
+
+// wait waits until all the spans have ended, at most d or until ctx is done.
+func (f *inFlightSpans) wait(ctx context.Context, d time.Duration) {
+	f.mu.Lock()
+	if f.n <= 0 || d <= 0 {
+		f.mu.Unlock()
+		return
+	}
+	if f.ended == nil {
+		f.ended = make(chan struct{})
+	}
+	ended := f.ended
+	f.mu.Unlock()
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ended:
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// delegate holds a value of the interface type T that can be swapped
+// atomically. Unlike atomic.Value, values of different concrete types can be
+// stored.
+type delegate[T any] struct {
+	v atomic.Pointer[T]
+}
+
+func (d *delegate[T]) store(v T) {
+	d.v.Store(&v)
+}
+
+func (d *delegate[T]) load() T {
+	return *d.v.Load()
+}
+
+// instrumentationKey identifies the tracers, meters and loggers of a
+// provider.
+type instrumentationKey struct {
+	name    string
+	version string
+	schema  string
+	attrs   attribute.Distinct
+}
+
+type reloadableTracerProvider struct {
+	traceembedded.TracerProvider
+
+	mu       sync.Mutex
+	delegate trace.TracerProvider
+	tracers  map[instrumentationKey]*reloadableTracer
+}
+
+func newReloadableTracerProvider(tp trace.TracerProvider) *reloadableTracerProvider {
+	return &reloadableTracerProvider{
+		delegate: tp,
+		tracers:  make(map[instrumentationKey]*reloadableTracer),
+	}
+}
+
+// setDelegate swaps the TracerProvider of p and of all the tracers it
+// created.
+func (p *reloadableTracerProvider) setDelegate(tp trace.TracerProvider) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.delegate = tp
+	for _, t := range p.tracers {
+		t.delegate.store(tp.Tracer(t.name, t.opts...))
+	}
+}
+
+// Tracer implements trace.TracerProvider.
+func (p *reloadableTracerProvider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	c := trace.NewTracerConfig(opts...)
+	attrs := c.InstrumentationAttributes()
+	key := instrumentationKey{
+		name:    name,
+		version: c.InstrumentationVersion(),
+		schema:  c.SchemaURL(),
+		attrs:   attrs.Equivalent(),
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if t, ok := p.tracers[key]; ok {
+		return t
+	}
+	t := &reloadableTracer{name: name, opts: opts}
+	t.delegate.store(p.delegate.Tracer(name, opts...))
+	p.tracers[key] = t
+	return t
+}
+
+type reloadableTracer struct {
+	traceembedded.Tracer
+
+	name     string
+	opts     []trace.TracerOption
+	delegate delegate[trace.Tracer]
+}
+
+// Start implements trace.Tracer.
+func (t *reloadableTracer) Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return t.delegate.load().Start(ctx, spanName, opts...)
+}
+
+type reloadableLoggerProvider struct {
+	logembedded.LoggerProvider
+
+	mu       sync.Mutex
+	delegate log.LoggerProvider
+	loggers  map[instrumentationKey]*reloadableLogger
+}
+
+func newReloadableLoggerProvider(lp log.LoggerProvider) *reloadableLoggerProvider {
+	return &reloadableLoggerProvider{
+		delegate: lp,
+		loggers:  make(map[instrumentationKey]*reloadableLogger),
+	}
+}
+
+// setDelegate swaps the LoggerProvider of p and of all the loggers it
+// created.
+func (p *reloadableLoggerProvider) setDelegate(lp log.LoggerProvider) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.delegate = lp
+	for _, l := range p.loggers {
+		l.delegate.store(lp.Logger(l.name, l.opts...))
+	}
+}
+
+// Logger implements log.LoggerProvider.
+func (p *reloadableLoggerProvider) Logger(name string, opts ...log.LoggerOption) log.Logger {
+	c := log.NewLoggerConfig(opts...)
+	attrs := c.InstrumentationAttributes()
+	key := instrumentationKey{
+		name:    name,
+		version: c.InstrumentationVersion(),
+		schema:  c.SchemaURL(),
+		attrs:   attrs.Equivalent(),
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if l, ok := p.loggers[key]; ok {
+		return l
+	}
+	l := &reloadableLogger{name: name, opts: opts}
+	l.delegate.store(p.delegate.Logger(name, opts...))
+	p.loggers[key] = l
+	return l
+}
+
+type reloadableLogger struct {
+	logembedded.Logger
+
+	name     string
+	opts     []log.LoggerOption
+	delegate delegate[log.Logger]
+}
+
+// Emit implements log.Logger.
+func (l *reloadableLogger) Emit(ctx context.Context, record log.Record) {
+	l.delegate.load().Emit(ctx, record)
+}
+
+// Enabled implements log.Logger.
+func (l *reloadableLogger) Enabled(ctx context.Context, param log.EnabledParameters) bool {
+	return l.delegate.load().Enabled(ctx, param)
+}
+
+type reloadablePropagator struct {
+	delegate delegate[propagation.TextMapPropagator]
+}
+
+func newReloadablePropagator(p propagation.TextMapPropagator) *reloadablePropagator {
+	r := &reloadablePropagator{}
+	r.setDelegate(p)
+	return r
+}
+
+func (p *reloadablePropagator) setDelegate(d propagation.TextMapPropagator) {
+	p.delegate.store(d)
+}
+
+// Inject implements propagation.TextMapPropagator.
+func (p *reloadablePropagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	p.delegate.load().Inject(ctx, carrier)
+}
+
+// Extract implements propagation.TextMapPropagator.
+func (p *reloadablePropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	return p.delegate.load().Extract(ctx, carrier)
+}
+
+// Fields implements propagation.TextMapPropagator.
+func (p *reloadablePropagator) Fields() []string {
+	return p.delegate.load().Fields()
+}
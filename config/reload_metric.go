@@ -0,0 +1,678 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package config // import "go.opentelemetry.io/contrib/config"
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	metricembedded "go.opentelemetry.io/otel/metric/embedded"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+type reloadableMeterProvider struct {
+	metricembedded.MeterProvider
+
+	mu       sync.Mutex
+	delegate metric.MeterProvider
+	meters   map[instrumentationKey]*reloadableMeter
+}
+
+func newReloadableMeterProvider(mp metric.MeterProvider) *reloadableMeterProvider {
+	return &reloadableMeterProvider{
+		delegate: mp,
+		meters:   make(map[instrumentationKey]*reloadableMeter),
+	}
+}
+
+// setDelegate swaps the MeterProvider of p and of all the meters it
+// created.
+func (p *reloadableMeterProvider) setDelegate(mp metric.MeterProvider) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.delegate = mp
+	for _, m := range p.meters {
+		m.setDelegate(mp.Meter(m.name, m.opts...))
+	}
+}
+
+// Meter implements metric.MeterProvider.
+func (p *reloadableMeterProvider) Meter(name string, opts ...metric.MeterOption) metric.Meter {
+	c := metric.NewMeterConfig(opts...)
+	attrs := c.InstrumentationAttributes()
+	key := instrumentationKey{
+		name:    name,
+		version: c.InstrumentationVersion(),
+		schema:  c.SchemaURL(),
+		attrs:   attrs.Equivalent(),
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if m, ok := p.meters[key]; ok {
+		return m
+	}
+	m := &reloadableMeter{
+		name:          name,
+		opts:          opts,
+		delegate:      p.delegate.Meter(name, opts...),
+		instruments:   make(map[instID]reloadableInstrument),
+		registrations: make(map[*reloadableRegistration]struct{}),
+	}
+	p.meters[key] = m
+	return m
+}
+
+// reloadableInstrument is an instrument that delegates to the instrument of
+// the same name of the current meter.
+type reloadableInstrument interface {
+	setDelegate(metric.Meter) error
+}
+
+// unwrapper is implemented by the observable instruments of a
+// reloadableMeter.
+type unwrapper interface {
+	// unwrap returns the instrument created by the meter m.
+	unwrap(m metric.Meter) metric.Observable
+}
+
+// instID are the identifying properties of an instrument.
+type instID struct {
+	name        string
+	description string
+	unit        string
+	kind        string
+}
+
+type reloadableMeter struct {
+	metricembedded.Meter
+
+	name string
+	opts []metric.MeterOption
+
+	mu            sync.Mutex
+	delegate      metric.Meter
+	instruments   map[instID]reloadableInstrument
+	registrations map[*reloadableRegistration]struct{}
+}
+
+// setDelegate recreates all the instruments and callback registrations of m
+// with the meter d.
+func (m *reloadableMeter) setDelegate(d metric.Meter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.delegate = d
+	for _, i := range m.instruments {
+		if err := i.setDelegate(d); err != nil {
+			otel.Handle(err)
+		}
+	}
+	// The instruments are swapped before the callbacks are registered so
+	// the callbacks observe the instruments of their meter.
+	for r := range m.registrations {
+		if err := r.setDelegate(d); err != nil {
+			otel.Handle(err)
+		}
+	}
+}
+
+// instrument returns the instrument identified by id, creating it with
+// newInstrument if it does not exist yet.
+func (m *reloadableMeter) instrument(id instID, newInstrument func() reloadableInstrument) (reloadableInstrument, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if i, ok := m.instruments[id]; ok {
+		return i, nil
+	}
+	i := newInstrument()
+	err := i.setDelegate(m.delegate)
+	m.instruments[id] = i
+	return i, err
+}
+
+// Int64Counter implements metric.Meter.
+func (m *reloadableMeter) Int64Counter(name string, options ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	cfg := metric.NewInt64CounterConfig(options...)
+	id := instID{name: name, description: cfg.Description(), unit: cfg.Unit(), kind: "Int64Counter"}
+	i, err := m.instrument(id, func() reloadableInstrument {
+		return &int64Counter{name: name, opts: options}
+	})
+	return i.(metric.Int64Counter), err
+}
+
+// Int64UpDownCounter implements metric.Meter.
+func (m *reloadableMeter) Int64UpDownCounter(name string, options ...metric.Int64UpDownCounterOption) (metric.Int64UpDownCounter, error) {
+	cfg := metric.NewInt64UpDownCounterConfig(options...)
+	id := instID{name: name, description: cfg.Description(), unit: cfg.Unit(), kind: "Int64UpDownCounter"}
+	i, err := m.instrument(id, func() reloadableInstrument {
+		return &int64UpDownCounter{name: name, opts: options}
+	})
+	return i.(metric.Int64UpDownCounter), err
+}
+
+// Int64Histogram implements metric.Meter.
+func (m *reloadableMeter) Int64Histogram(name string, options ...metric.Int64HistogramOption) (metric.Int64Histogram, error) {
+	cfg := metric.NewInt64HistogramConfig(options...)
+	id := instID{name: name, description: cfg.Description(), unit: cfg.Unit(), kind: "Int64Histogram"}
+	i, err := m.instrument(id, func() reloadableInstrument {
+		return &int64Histogram{name: name, opts: options}
+	})
+	return i.(metric.Int64Histogram), err
+}
+
+// Int64Gauge implements metric.Meter.
+func (m *reloadableMeter) Int64Gauge(name string, options ...metric.Int64GaugeOption) (metric.Int64Gauge, error) {
+	cfg := metric.NewInt64GaugeConfig(options...)
+	id := instID{name: name, description: cfg.Description(), unit: cfg.Unit(), kind: "Int64Gauge"}
+	i, err := m.instrument(id, func() reloadableInstrument {
+		return &int64Gauge{name: name, opts: options}
+	})
+	return i.(metric.Int64Gauge), err
+}
+
+// Int64ObservableCounter implements metric.Meter.
+func (m *reloadableMeter) Int64ObservableCounter(name string, options ...metric.Int64ObservableCounterOption) (metric.Int64ObservableCounter, error) {
+	cfg := metric.NewInt64ObservableCounterConfig(options...)
+	id := instID{name: name, description: cfg.Description(), unit: cfg.Unit(), kind: "Int64ObservableCounter"}
+	i, err := m.instrument(id, func() reloadableInstrument {
+		return &int64ObservableCounter{name: name, opts: options}
+	})
+	return i.(metric.Int64ObservableCounter), err
+}
+
+// Int64ObservableUpDownCounter implements metric.Meter.
+func (m *reloadableMeter) Int64ObservableUpDownCounter(name string, options ...metric.Int64ObservableUpDownCounterOption) (metric.Int64ObservableUpDownCounter, error) {
+	cfg := metric.NewInt64ObservableUpDownCounterConfig(options...)
+	id := instID{name: name, description: cfg.Description(), unit: cfg.Unit(), kind: "Int64ObservableUpDownCounter"}
+	i, err := m.instrument(id, func() reloadableInstrument {
+		return &int64ObservableUpDownCounter{name: name, opts: options}
+	})
+	return i.(metric.Int64ObservableUpDownCounter), err
+}
+
+// Int64ObservableGauge implements metric.Meter.
+func (m *reloadableMeter) Int64ObservableGauge(name string, options ...metric.Int64ObservableGaugeOption) (metric.Int64ObservableGauge, error) {
+	cfg := metric.NewInt64ObservableGaugeConfig(options...)
+	id := instID{name: name, description: cfg.Description(), unit: cfg.Unit(), kind: "Int64ObservableGauge"}
+	i, err := m.instrument(id, func() reloadableInstrument {
+		return &int64ObservableGauge{name: name, opts: options}
+	})
+	return i.(metric.Int64ObservableGauge), err
+}
+
+// Float64Counter implements metric.Meter.
+func (m *reloadableMeter) Float64Counter(name string, options ...metric.Float64CounterOption) (metric.Float64Counter, error) {
+	cfg := metric.NewFloat64CounterConfig(options...)
+	id := instID{name: name, description: cfg.Description(), unit: cfg.Unit(), kind: "Float64Counter"}
+	i, err := m.instrument(id, func() reloadableInstrument {
+		return &float64Counter{name: name, opts: options}
+	})
+	return i.(metric.Float64Counter), err
+}
+
+// Float64UpDownCounter implements metric.Meter.
+func (m *reloadableMeter) Float64UpDownCounter(name string, options ...metric.Float64UpDownCounterOption) (metric.Float64UpDownCounter, error) {
+	cfg := metric.NewFloat64UpDownCounterConfig(options...)
+	id := instID{name: name, description: cfg.Description(), unit: cfg.Unit(), kind: "Float64UpDownCounter"}
+	i, err := m.instrument(id, func() reloadableInstrument {
+		return &float64UpDownCounter{name: name, opts: options}
+	})
+	return i.(metric.Float64UpDownCounter), err
+}
+
+// Float64Histogram implements metric.Meter.
+func (m *reloadableMeter) Float64Histogram(name string, options ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	cfg := metric.NewFloat64HistogramConfig(options...)
+	id := instID{name: name, description: cfg.Description(), unit: cfg.Unit(), kind: "Float64Histogram"}
+	i, err := m.instrument(id, func() reloadableInstrument {
+		return &float64Histogram{name: name, opts: options}
+	})
+	return i.(metric.Float64Histogram), err
+}
+
+// Float64Gauge implements metric.Meter.
+func (m *reloadableMeter) Float64Gauge(name string, options ...metric.Float64GaugeOption) (metric.Float64Gauge, error) {
+	cfg := metric.NewFloat64GaugeConfig(options...)
+	id := instID{name: name, description: cfg.Description(), unit: cfg.Unit(), kind: "Float64Gauge"}
+	i, err := m.instrument(id, func() reloadableInstrument {
+		return &float64Gauge{name: name, opts: options}
+	})
+	return i.(metric.Float64Gauge), err
+}
+
+// Float64ObservableCounter implements metric.Meter.
+func (m *reloadableMeter) Float64ObservableCounter(name string, options ...metric.Float64ObservableCounterOption) (metric.Float64ObservableCounter, error) {
+	cfg := metric.NewFloat64ObservableCounterConfig(options...)
+	id := instID{name: name, description: cfg.Description(), unit: cfg.Unit(), kind: "Float64ObservableCounter"}
+	i, err := m.instrument(id, func() reloadableInstrument {
+		return &float64ObservableCounter{name: name, opts: options}
+	})
+	return i.(metric.Float64ObservableCounter), err
+}
+
+// Float64ObservableUpDownCounter implements metric.Meter.
+func (m *reloadableMeter) Float64ObservableUpDownCounter(name string, options ...metric.Float64ObservableUpDownCounterOption) (metric.Float64ObservableUpDownCounter, error) {
+	cfg := metric.NewFloat64ObservableUpDownCounterConfig(options...)
+	id := instID{name: name, description: cfg.Description(), unit: cfg.Unit(), kind: "Float64ObservableUpDownCounter"}
+	i, err := m.instrument(id, func() reloadableInstrument {
+		return &float64ObservableUpDownCounter{name: name, opts: options}
+	})
+	return i.(metric.Float64ObservableUpDownCounter), err
+}
+
+// Float64ObservableGauge implements metric.Meter.
+func (m *reloadableMeter) Float64ObservableGauge(name string, options ...metric.Float64ObservableGaugeOption) (metric.Float64ObservableGauge, error) {
+	cfg := metric.NewFloat64ObservableGaugeConfig(options...)
+	id := instID{name: name, description: cfg.Description(), unit: cfg.Unit(), kind: "Float64ObservableGauge"}
+	i, err := m.instrument(id, func() reloadableInstrument {
+		return &float64ObservableGauge{name: name, opts: options}
+	})
+	return i.(metric.Float64ObservableGauge), err
+}
+
+// RegisterCallback implements metric.Meter.
+func (m *reloadableMeter) RegisterCallback(f metric.Callback, insts ...metric.Observable) (metric.Registration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r := &reloadableRegistration{meter: m, function: f, instruments: insts}
+	if err := r.setDelegate(m.delegate); err != nil {
+		return nil, err
+	}
+	m.registrations[r] = struct{}{}
+	return r, nil
+}
+
+// observableDelegates holds the observable instruments an instrument
+// delegates to. The instrument of the previous meter is kept, as the
+// previous MeterProvider still collects it while it is shut down.
+type observableDelegates struct {
+	mu                sync.Mutex
+	current, previous observableDelegate
+}
+
+type observableDelegate struct {
+	meter      metric.Meter
+	observable metric.Observable
+}
+
+func (d *observableDelegates) set(m metric.Meter, o metric.Observable) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.previous = d.current
+	d.current = observableDelegate{meter: m, observable: o}
+}
+
+func (d *observableDelegates) unwrap(m metric.Meter) metric.Observable {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch m {
+	case d.current.meter:
+		return d.current.observable
+	case d.previous.meter:
+		return d.previous.observable
+	}
+	return nil
+}
+
+// unwrapObservable returns the instrument created by the meter m that o
+// delegates to, or o if it is not an instrument of a reloadableMeter.
+func unwrapObservable(o metric.Observable, m metric.Meter) metric.Observable {
+	if u, ok := o.(unwrapper); ok {
+		if unwrapped := u.unwrap(m); unwrapped != nil {
+			return unwrapped
+		}
+	}
+	return o
+}
+
+type reloadableRegistration struct {
+	metricembedded.Registration
+
+	meter       *reloadableMeter
+	function    metric.Callback
+	instruments []metric.Observable
+
+	mu                sync.Mutex
+	current, previous metric.Registration
+}
+
+// setDelegate registers the callback of r with the meter d.
+func (r *reloadableRegistration) setDelegate(d metric.Meter) error {
+	insts := make([]metric.Observable, len(r.instruments))
+	for i, inst := range r.instruments {
+		insts[i] = unwrapObservable(inst, d)
+	}
+	f := r.function
+	reg, err := d.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		return f(ctx, &unwrapObserver{Observer: o, meter: d})
+	}, insts...)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.previous, r.current = r.current, reg
+	return nil
+}
+
+// Unregister implements metric.Registration.
+func (r *reloadableRegistration) Unregister() error {
+	r.meter.mu.Lock()
+	delete(r.meter.registrations, r)
+	r.meter.mu.Unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var err error
+	for _, reg := range []metric.Registration{r.current, r.previous} {
+		if reg != nil {
+			if e := reg.Unregister(); e != nil {
+				err = e
+			}
+		}
+	}
+	r.current, r.previous = nil, nil
+	return err
+}
+
+// unwrapObserver passes the instruments of the meter it observes for to the
+// Observer of a callback.
+type unwrapObserver struct {
+	metric.Observer
+
+	meter metric.Meter
+}
+
+// ObserveFloat64 implements metric.Observer.
+func (o *unwrapObserver) ObserveFloat64(inst metric.Float64Observable, value float64, opts ...metric.ObserveOption) {
+	if unwrapped, ok := unwrapObservable(inst, o.meter).(metric.Float64Observable); ok {
+		inst = unwrapped
+	}
+	o.Observer.ObserveFloat64(inst, value, opts...)
+}
+
+// ObserveInt64 implements metric.Observer.
+func (o *unwrapObserver) ObserveInt64(inst metric.Int64Observable, value int64, opts ...metric.ObserveOption) {
+	if unwrapped, ok := unwrapObservable(inst, o.meter).(metric.Int64Observable); ok {
+		inst = unwrapped
+	}
+	o.Observer.ObserveInt64(inst, value, opts...)
+}
+
+// orNoop returns v, or fallback if v is nil.
+func orNoop[T any](v, fallback T) T {
+	if any(v) == nil {
+		return fallback
+	}
+	return v
+}
+
+type int64Counter struct {
+	metricembedded.Int64Counter
+
+	name     string
+	opts     []metric.Int64CounterOption
+	delegate delegate[metric.Int64Counter]
+}
+
+func (i *int64Counter) setDelegate(m metric.Meter) error {
+	v, err := m.Int64Counter(i.name, i.opts...)
+	i.delegate.store(orNoop[metric.Int64Counter](v, noop.Int64Counter{}))
+	return err
+}
+
+// Add implements metric.Int64Counter.
+func (i *int64Counter) Add(ctx context.Context, incr int64, opts ...metric.AddOption) {
+	i.delegate.load().Add(ctx, incr, opts...)
+}
+
+type int64UpDownCounter struct {
+	metricembedded.Int64UpDownCounter
+
+	name     string
+	opts     []metric.Int64UpDownCounterOption
+	delegate delegate[metric.Int64UpDownCounter]
+}
+
+func (i *int64UpDownCounter) setDelegate(m metric.Meter) error {
+	v, err := m.Int64UpDownCounter(i.name, i.opts...)
+	i.delegate.store(orNoop[metric.Int64UpDownCounter](v, noop.Int64UpDownCounter{}))
+	return err
+}
+
+// Add implements metric.Int64UpDownCounter.
+func (i *int64UpDownCounter) Add(ctx context.Context, incr int64, opts ...metric.AddOption) {
+	i.delegate.load().Add(ctx, incr, opts...)
+}
+
+type int64Histogram struct {
+	metricembedded.Int64Histogram
+
+	name     string
+	opts     []metric.Int64HistogramOption
+	delegate delegate[metric.Int64Histogram]
+}
+
+func (i *int64Histogram) setDelegate(m metric.Meter) error {
+	v, err := m.Int64Histogram(i.name, i.opts...)
+	i.delegate.store(orNoop[metric.Int64Histogram](v, noop.Int64Histogram{}))
+	return err
+}
+
+// Record implements metric.Int64Histogram.
+func (i *int64Histogram) Record(ctx context.Context, incr int64, opts ...metric.RecordOption) {
+	i.delegate.load().Record(ctx, incr, opts...)
+}
+
+type int64Gauge struct {
+	metricembedded.Int64Gauge
+
+	name     string
+	opts     []metric.Int64GaugeOption
+	delegate delegate[metric.Int64Gauge]
+}
+
+func (i *int64Gauge) setDelegate(m metric.Meter) error {
+	v, err := m.Int64Gauge(i.name, i.opts...)
+	i.delegate.store(orNoop[metric.Int64Gauge](v, noop.Int64Gauge{}))
+	return err
+}
+
+// Record implements metric.Int64Gauge.
+func (i *int64Gauge) Record(ctx context.Context, value int64, opts ...metric.RecordOption) {
+	i.delegate.load().Record(ctx, value, opts...)
+}
+
+type int64ObservableCounter struct {
+	metricembedded.Int64ObservableCounter
+	metric.Int64Observable
+
+	name      string
+	opts      []metric.Int64ObservableCounterOption
+	delegates observableDelegates
+}
+
+func (i *int64ObservableCounter) setDelegate(m metric.Meter) error {
+	v, err := m.Int64ObservableCounter(i.name, i.opts...)
+	i.delegates.set(m, orNoop[metric.Int64ObservableCounter](v, noop.Int64ObservableCounter{}))
+	return err
+}
+
+func (i *int64ObservableCounter) unwrap(m metric.Meter) metric.Observable {
+	return i.delegates.unwrap(m)
+}
+
+type int64ObservableUpDownCounter struct {
+	metricembedded.Int64ObservableUpDownCounter
+	metric.Int64Observable
+
+	name      string
+	opts      []metric.Int64ObservableUpDownCounterOption
+	delegates observableDelegates
+}
+
+func (i *int64ObservableUpDownCounter) setDelegate(m metric.Meter) error {
+	v, err := m.Int64ObservableUpDownCounter(i.name, i.opts...)
+	i.delegates.set(m, orNoop[metric.Int64ObservableUpDownCounter](v, noop.Int64ObservableUpDownCounter{}))
+	return err
+}
+
+func (i *int64ObservableUpDownCounter) unwrap(m metric.Meter) metric.Observable {
+	return i.delegates.unwrap(m)
+}
+
+type int64ObservableGauge struct {
+	metricembedded.Int64ObservableGauge
+	metric.Int64Observable
+
+	name      string
+	opts      []metric.Int64ObservableGaugeOption
+	delegates observableDelegates
+}
+
+func (i *int64ObservableGauge) setDelegate(m metric.Meter) error {
+	v, err := m.Int64ObservableGauge(i.name, i.opts...)
+	i.delegates.set(m, orNoop[metric.Int64ObservableGauge](v, noop.Int64ObservableGauge{}))
+	return err
+}
+
+func (i *int64ObservableGauge) unwrap(m metric.Meter) metric.Observable {
+	return i.delegates.unwrap(m)
+}
+
+type float64Counter struct {
+	metricembedded.Float64Counter
+
+	name     string
+	opts     []metric.Float64CounterOption
+	delegate delegate[metric.Float64Counter]
+}
+
+func (i *float64Counter) setDelegate(m metric.Meter) error {
+	v, err := m.Float64Counter(i.name, i.opts...)
+	i.delegate.store(orNoop[metric.Float64Counter](v, noop.Float64Counter{}))
+	return err
+}
+
+// Add implements metric.Float64Counter.
+func (i *float64Counter) Add(ctx context.Context, incr float64, opts ...metric.AddOption) {
+	i.delegate.load().Add(ctx, incr, opts...)
+}
+
+type float64UpDownCounter struct {
+	metricembedded.Float64UpDownCounter
+
+	name     string
+	opts     []metric.Float64UpDownCounterOption
+	delegate delegate[metric.Float64UpDownCounter]
+}
+
+func (i *float64UpDownCounter) setDelegate(m metric.Meter) error {
+	v, err := m.Float64UpDownCounter(i.name, i.opts...)
+	i.delegate.store(orNoop[metric.Float64UpDownCounter](v, noop.Float64UpDownCounter{}))
+	return err
+}
+
+// Add implements metric.Float64UpDownCounter.
+func (i *float64UpDownCounter) Add(ctx context.Context, incr float64, opts ...metric.AddOption) {
+	i.delegate.load().Add(ctx, incr, opts...)
+}
+
+type float64Histogram struct {
+	metricembedded.Float64Histogram
+
+	name     string
+	opts     []metric.Float64HistogramOption
+	delegate delegate[metric.Float64Histogram]
+}
+
+func (i *float64Histogram) setDelegate(m metric.Meter) error {
+	v, err := m.Float64Histogram(i.name, i.opts...)
+	i.delegate.store(orNoop[metric.Float64Histogram](v, noop.Float64Histogram{}))
+	return err
+}
+
+// Record implements metric.Float64Histogram.
+func (i *float64Histogram) Record(ctx context.Context, incr float64, opts ...metric.RecordOption) {
+	i.delegate.load().Record(ctx, incr, opts...)
+}
+
+type float64Gauge struct {
+	metricembedded.Float64Gauge
+
+	name     string
+	opts     []metric.Float64GaugeOption
+	delegate delegate[metric.Float64Gauge]
+}
+
+func (i *float64Gauge) setDelegate(m metric.Meter) error {
+	v, err := m.Float64Gauge(i.name, i.opts...)
+	i.delegate.store(orNoop[metric.Float64Gauge](v, noop.Float64Gauge{}))
+	return err
+}
+
+// Record implements metric.Float64Gauge.
+func (i *float64Gauge) Record(ctx context.Context, value float64, opts ...metric.RecordOption) {
+	i.delegate.load().Record(ctx, value, opts...)
+}
+
+type float64ObservableCounter struct {
+	metricembedded.Float64ObservableCounter
+	metric.Float64Observable
+
+	name      string
+	opts      []metric.Float64ObservableCounterOption
+	delegates observableDelegates
+}
+
+func (i *float64ObservableCounter) setDelegate(m metric.Meter) error {
+	v, err := m.Float64ObservableCounter(i.name, i.opts...)
+	i.delegates.set(m, orNoop[metric.Float64ObservableCounter](v, noop.Float64ObservableCounter{}))
+	return err
+}
+
+func (i *float64ObservableCounter) unwrap(m metric.Meter) metric.Observable {
+	return i.delegates.unwrap(m)
+}
+
+type float64ObservableUpDownCounter struct {
+	metricembedded.Float64ObservableUpDownCounter
+	metric.Float64Observable
+
+	name      string
+	opts      []metric.Float64ObservableUpDownCounterOption
+	delegates observableDelegates
+}
+
+func (i *float64ObservableUpDownCounter) setDelegate(m metric.Meter) error {
+	v, err := m.Float64ObservableUpDownCounter(i.name, i.opts...)
+	i.delegates.set(m, orNoop[metric.Float64ObservableUpDownCounter](v, noop.Float64ObservableUpDownCounter{}))
+	return err
+}
+
+func (i *float64ObservableUpDownCounter) unwrap(m metric.Meter) metric.Observable {
+	return i.delegates.unwrap(m)
+}
+
+type float64ObservableGauge struct {
+	metricembedded.Float64ObservableGauge
+	metric.Float64Observable
+
+	name      string
+	opts      []metric.Float64ObservableGaugeOption
+	delegates observableDelegates
+}
+
+func (i *float64ObservableGauge) setDelegate(m metric.Meter) error {
+	v, err := m.Float64ObservableGauge(i.name, i.opts...)
+	i.delegates.set(m, orNoop[metric.Float64ObservableGauge](v, noop.Float64ObservableGauge{}))
+	return err
+}
+
+func (i *float64ObservableGauge) unwrap(m metric.Meter) metric.Observable {
+	return i.delegates.unwrap(m)
+}
@@ -0,0 +1,334 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// testPipelines records the telemetry of the pipelines created by the
+// "test" span processor and metric exporter, keyed by their name setting.
+type testPipelines struct {
+	mu      sync.Mutex
+	spans   map[string]*tracetest.SpanRecorder
+	metrics map[string]*testMetricExporter
+}
+
+func newTestPipelines(t *testing.T) *testPipelines {
+	resetRegistries(t)
+	p := &testPipelines{
+		spans:   make(map[string]*tracetest.SpanRecorder),
+		metrics: make(map[string]*testMetricExporter),
+	}
+	RegisterSpanProcessor("test", func(_ context.Context, cfg map[string]interface{}) (sdktrace.SpanProcessor, error) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		r := tracetest.NewSpanRecorder()
+		p.spans[cfg["name"].(string)] = r
+		return r, nil
+	})
+	RegisterMetricExporter("test", func(_ context.Context, cfg map[string]interface{}) (sdkmetric.Exporter, error) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		e := &testMetricExporter{}
+		p.metrics[cfg["name"].(string)] = e
+		return e, nil
+	})
+	return p
+}
+
+func (p *testPipelines) spanNames(name string) []string {
+	p.mu.Lock()
+	r, ok := p.spans[name]
+	p.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	var names []string
+	for _, s := range r.Ended() {
+		names = append(names, s.Name())
+	}
+	return names
+}
+
+func (p *testPipelines) metricExporter(name string) *testMetricExporter {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.metrics[name]
+}
+
+type testMetricExporter struct {
+	mu      sync.Mutex
+	metrics map[string]int64
+}
+
+func (*testMetricExporter) Temporality(sdkmetric.InstrumentKind) metricdata.Temporality {
+	return metricdata.CumulativeTemporality
+}
+
+func (*testMetricExporter) Aggregation(k sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(k)
+}
+
+func (e *testMetricExporter) Export(_ context.Context, rm *metricdata.ResourceMetrics) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.metrics = make(map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				e.metrics[m.Name] = data.DataPoints[0].Value
+			case metricdata.Gauge[int64]:
+				e.metrics[m.Name] = data.DataPoints[0].Value
+			}
+		}
+	}
+	return nil
+}
+
+func (e *testMetricExporter) exported() map[string]int64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.metrics
+}
+
+func (*testMetricExporter) ForceFlush(context.Context) error { return nil }
+
+func (*testMetricExporter) Shutdown(context.Context) error { return nil }
+
+func writeConfig(t *testing.T, path, name string) {
+	cfg := fmt.Sprintf(`
+file_format: "0.1"
+tracer_provider:
+  processors:
+    - test:
+        name: %[1]s
+meter_provider:
+  readers:
+    - periodic:
+        interval: 3600000
+        exporter:
+          test:
+            name: %[1]s
+`, name)
+	require.NoError(t, os.WriteFile(path, []byte(cfg), 0o600))
+}
+
+func TestReloadableSDK(t *testing.T) {
+	pipelines := newTestPipelines(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, "first")
+
+	sdk, err := NewReloadableSDK(path, WithReloadInterval(0))
+	require.NoError(t, err)
+
+	tracer := sdk.TracerProvider().Tracer("test")
+	assert.Same(t, tracer, sdk.TracerProvider().Tracer("test"))
+	meter := sdk.MeterProvider().Meter("test")
+	counter, err := meter.Int64Counter("counter")
+	require.NoError(t, err)
+	gauge, err := meter.Int64ObservableGauge("gauge")
+	require.NoError(t, err)
+	var observed int64
+	reg, err := meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		observed++
+		o.ObserveInt64(gauge, observed)
+		return nil
+	}, gauge)
+	require.NoError(t, err)
+
+	_, span := tracer.Start(context.Background(), "before")
+	span.End()
+	counter.Add(context.Background(), 1)
+
+	writeConfig(t, path, "second")
+	require.NoError(t, sdk.Reload(context.Background()))
+
+	// The previous pipeline is flushed when it is shut down.
+	assert.Equal(t, map[string]int64{"counter": 1, "gauge": 1}, pipelines.metricExporter("first").exported())
+
+	_, span = tracer.Start(context.Background(), "after")
+	span.End()
+	counter.Add(context.Background(), 2)
+
+	// An invalid configuration keeps the current pipeline.
+	require.NoError(t, os.WriteFile(path, []byte("file_format: \"0.1\"\ntracer_provider:\n  processors:\n    - unknown: {}\n"), 0o600))
+	assert.ErrorContains(t, sdk.Reload(context.Background()), `unsupported type "unknown"`)
+
+	_, span = tracer.Start(context.Background(), "invalid")
+	span.End()
+
+	require.NoError(t, reg.Unregister())
+	require.NoError(t, sdk.Shutdown(context.Background()))
+	assert.ErrorIs(t, sdk.Reload(context.Background()), errReloadableSDKShutdown)
+
+	assert.Equal(t, []string{"before"}, pipelines.spanNames("first"))
+	assert.Equal(t, []string{"after", "invalid"}, pipelines.spanNames("second"))
+	assert.Equal(t, map[string]int64{"counter": 2}, pipelines.metricExporter("second").exported())
+}
+
+func TestReloadableSDKWatch(t *testing.T) {
+	pipelines := newTestPipelines(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, "first")
+
+	errs := make(chan error, 1)
+	handler := otel.GetErrorHandler()
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}))
+	t.Cleanup(func() { otel.SetErrorHandler(handler) })
+
+	sdk, err := NewReloadableSDK(path, WithReloadInterval(10*time.Millisecond))
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, sdk.Shutdown(context.Background())) })
+	tracer := sdk.TracerProvider().Tracer("test")
+
+	writeConfig(t, path, "second")
+	require.Eventually(t, func() bool {
+		_, span := tracer.Start(context.Background(), "span")
+		span.End()
+		return len(pipelines.spanNames("second")) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(path, []byte("file_format: invalid\n"), 0o600))
+	select {
+	case err := <-errs:
+		assert.ErrorContains(t, err, `unsupported file_format "invalid"`)
+	case <-time.After(5 * time.Second):
+		t.Fatal("invalid configuration file not reported")
+	}
+}
+
+func TestNewReloadableSDKErrors(t *testing.T) {
+	dir := t.TempDir()
+	_, err := NewReloadableSDK(filepath.Join(dir, "missing.yaml"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	path := filepath.Join(dir, "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"file_format": "0.1", "tracer_provider": {"processors": [{"batch": {}}]}}`), 0o600))
+	_, err = NewReloadableSDK(path)
+	assert.ErrorContains(t, err, "field exporter in BatchSpanProcessor: required")
+}
+
+func TestReloadableSDKInFlightSpans(t *testing.T) {
+	pipelines := newTestPipelines(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, "first")
+
+	sdk, err := NewReloadableSDK(path, WithReloadInterval(0), WithReloadGracePeriod(time.Minute))
+	require.NoError(t, err)
+	tracer := sdk.TracerProvider().Tracer("test")
+
+	_, span := tracer.Start(context.Background(), "in-flight")
+	writeConfig(t, path, "second")
+	reloaded := make(chan error)
+	go func() { reloaded <- sdk.Reload(context.Background()) }()
+
+	// The previous providers are not shut down while their spans are open.
+	select {
+	case err := <-reloaded:
+		t.Fatalf("reloaded before the in-flight span ended: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	// Waiting for the spans does not block the other reloads.
+	go func() { reloaded <- sdk.Reload(context.Background()) }()
+	select {
+	case err := <-reloaded:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("reload blocked by the in-flight span of a previous configuration")
+	}
+	span.End()
+	require.NoError(t, <-reloaded)
+	assert.Equal(t, []string{"in-flight"}, pipelines.spanNames("first"))
+
+	require.NoError(t, sdk.Shutdown(context.Background()))
+
+	// Spans ending after the grace period are dropped.
+	sdk, err = NewReloadableSDK(path, WithReloadInterval(0), WithReloadGracePeriod(10*time.Millisecond))
+	require.NoError(t, err)
+	_, span = sdk.TracerProvider().Tracer("test").Start(context.Background(), "dropped")
+	writeConfig(t, path, "third")
+	require.NoError(t, sdk.Reload(context.Background()))
+	span.End()
+	assert.Empty(t, pipelines.spanNames("second"))
+
+	require.NoError(t, sdk.Shutdown(context.Background()))
+}
+
+func TestReloadableSDKPrometheus(t *testing.T) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	port := lis.Addr().(*net.TCPAddr).Port
+	require.NoError(t, lis.Close())
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(name string) {
+		cfg := fmt.Sprintf(`
+file_format: "0.1"
+resource:
+  attributes:
+    service.name: %s
+meter_provider:
+  readers:
+    - pull:
+        exporter:
+          prometheus:
+            host: localhost
+            port: %d
+`, name, port)
+		require.NoError(t, os.WriteFile(path, []byte(cfg), 0o600))
+	}
+	scrape := func() string {
+		resp, err := http.Get(fmt.Sprintf("http://localhost:%d/metrics", port))
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return string(body)
+	}
+
+	write("first")
+	sdk, err := NewReloadableSDK(path, WithReloadInterval(0))
+	require.NoError(t, err)
+	counter, err := sdk.MeterProvider().Meter("test").Int64Counter("requests")
+	require.NoError(t, err)
+	counter.Add(context.Background(), 1)
+	assert.Contains(t, scrape(), `service_name="first"`)
+
+	write("second")
+	require.NoError(t, sdk.Reload(context.Background()))
+	counter.Add(context.Background(), 2)
+	body := scrape()
+	assert.Contains(t, body, `service_name="second"`)
+	assert.Contains(t, body, "requests_total{otel_scope_name=\"test\",otel_scope_version=\"\"} 2")
+
+	require.NoError(t, sdk.Shutdown(context.Background()))
+	_, err = http.Get(fmt.Sprintf("http://localhost:%d/metrics", port))
+	assert.Error(t, err, "the Prometheus server is not shut down")
+}
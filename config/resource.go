@@ -4,16 +4,249 @@
 package config // import "go.opentelemetry.io/contrib/config"
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.opentelemetry.io/contrib/detectors/aws/ec2"
+	"go.opentelemetry.io/contrib/detectors/aws/ecs"
+	"go.opentelemetry.io/contrib/detectors/aws/eks"
+	"go.opentelemetry.io/contrib/detectors/aws/lambda"
+	"go.opentelemetry.io/contrib/detectors/gcp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/sdk/resource"
-	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
-func newResource(res *Resource) (*resource.Resource, error) {
-	if res == nil || res.Attributes == nil {
+// resourceDetectors are the resource detectors that can be enabled with the
+// detectors field of the resource configuration.
+var resourceDetectors = map[string]func() resource.Option{
+	"host":      resource.WithHost,
+	"process":   resource.WithProcess,
+	"container": resource.WithContainer,
+	"env":       resource.WithFromEnv,
+	"aws/ec2": func() resource.Option {
+		return resource.WithDetectors(ec2.NewResourceDetector())
+	},
+	"aws/ecs": func() resource.Option {
+		return resource.WithDetectors(ecs.NewResourceDetector())
+	},
+	"aws/eks": func() resource.Option {
+		return resource.WithDetectors(eks.NewResourceDetector())
+	},
+	"aws/lambda": func() resource.Option {
+		return resource.WithDetectors(lambda.NewResourceDetector())
+	},
+	"gcp": func() resource.Option {
+		return resource.WithDetectors(gcp.NewDetector())
+	},
+}
+
+// newResource returns the resource of the configuration model.
+//
+// The attributes of the resource are merged in the following order, where
+// later sources take precedence over earlier ones:
+//
+//  1. The default resource of the SDK.
+//  2. The attributes found by the detectors, in the order they are listed.
+//  3. The attributes of the configuration model.
+func newResource(ctx context.Context, res *Resource) (*resource.Resource, error) {
+	if res == nil {
 		return resource.Default(), nil
 	}
-	return resource.Merge(resource.Default(),
-		resource.NewWithAttributes(*res.SchemaUrl,
-			semconv.ServiceName(*res.Attributes.ServiceName),
-		))
+
+	r := resource.Default()
+	for _, name := range res.Detectors {
+		detected, err := detectResource(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		// Detectors may use a different semantic conventions version than
+		// the SDK. Keep the detected attributes regardless, a conflicting
+		// schema URL only means the merged resource is schemaless.
+		merged, err := resource.Merge(r, detected)
+		if err != nil && !errors.Is(err, resource.ErrSchemaURLConflict) {
+			return nil, err
+		}
+		r = merged
+	}
+
+	if res.Attributes == nil {
+		return r, nil
+	}
+	attrs, err := resourceAttributes(res.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	var schemaURL string
+	if res.SchemaUrl != nil {
+		schemaURL = *res.SchemaUrl
+	}
+	return resource.Merge(r, resource.NewWithAttributes(schemaURL, attrs...))
+}
+
+// detectResource returns the resource detected by the detector name.
+// Detection failures are reported to the global error handler and an empty
+// resource is returned instead, as the availability of resource information
+// depends on the environment the application is run in.
+func detectResource(ctx context.Context, name string) (*resource.Resource, error) {
+	opt, ok := resourceDetectors[name]
+	if !ok {
+		supported := make([]string, 0, len(resourceDetectors))
+		for k := range resourceDetectors {
+			supported = append(supported, k)
+		}
+		sort.Strings(supported)
+		return nil, fmt.Errorf("unsupported resource detector %q, supported values: %q", name, supported)
+	}
+	r, err := resource.New(ctx, opt())
+	if err != nil {
+		otel.Handle(fmt.Errorf("resource detector %q: %w", name, err))
+	}
+	if r == nil {
+		r = resource.Empty()
+	}
+	return r, nil
+}
+
+func resourceAttributes(attrs *Attributes) ([]attribute.KeyValue, error) {
+	var kvs []attribute.KeyValue
+	if attrs.ServiceName != nil {
+		kvs = append(kvs, semconv.ServiceName(*attrs.ServiceName))
+	}
+
+	keys := make([]string, 0, len(attrs.AdditionalProperties))
+	for k := range attrs.AdditionalProperties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var errs []error
+	for _, k := range keys {
+		kv, err := keyValue(k, attrs.AdditionalProperties[k])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		kvs = append(kvs, kv)
+	}
+	return kvs, errors.Join(errs...)
+}
+
+// keyValue returns the attribute for the resource attribute key with the
+// decoded configuration value v.
+func keyValue(k string, v interface{}) (attribute.KeyValue, error) {
+	switch val := v.(type) {
+	case string:
+		return attribute.String(k, val), nil
+	case bool:
+		return attribute.Bool(k, val), nil
+	case int:
+		return attribute.Int(k, val), nil
+	case int64:
+		return attribute.Int64(k, val), nil
+	case float64:
+		return attribute.Float64(k, val), nil
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return attribute.Int64(k, i), nil
+		}
+		f, err := val.Float64()
+		if err != nil {
+			return attribute.KeyValue{}, fmt.Errorf("resource attribute %q: %w", k, err)
+		}
+		return attribute.Float64(k, f), nil
+	case []string:
+		return attribute.StringSlice(k, val), nil
+	case []interface{}:
+		return sliceKeyValue(k, val)
+	}
+	return attribute.KeyValue{}, fmt.Errorf("resource attribute %q: unsupported value type %T", k, v)
+}
+
+// sliceKeyValue returns the array attribute for the resource attribute key
+// with the values vals. All values must be of the same type.
+func sliceKeyValue(k string, vals []interface{}) (attribute.KeyValue, error) {
+	if len(vals) == 0 {
+		return attribute.StringSlice(k, nil), nil
+	}
+
+	kvs := make([]attribute.KeyValue, len(vals))
+	for i, v := range vals {
+		if _, ok := v.([]interface{}); ok {
+			return attribute.KeyValue{}, fmt.Errorf("resource attribute %q: nested arrays are not supported", k)
+		}
+		kv, err := keyValue(k, v)
+		if err != nil {
+			return attribute.KeyValue{}, err
+		}
+		if i > 0 && kv.Value.Type() != kvs[0].Value.Type() {
+			return attribute.KeyValue{}, fmt.Errorf("resource attribute %q: array values must be of the same type", k)
+		}
+		kvs[i] = kv
+	}
+
+	switch kvs[0].Value.Type() {
+	case attribute.STRING:
+		s := make([]string, len(kvs))
+		for i, kv := range kvs {
+			s[i] = kv.Value.AsString()
+		}
+		return attribute.StringSlice(k, s), nil
+	case attribute.BOOL:
+		s := make([]bool, len(kvs))
+		for i, kv := range kvs {
+			s[i] = kv.Value.AsBool()
+		}
+		return attribute.BoolSlice(k, s), nil
+	case attribute.INT64:
+		s := make([]int64, len(kvs))
+		for i, kv := range kvs {
+			s[i] = kv.Value.AsInt64()
+		}
+		return attribute.Int64Slice(k, s), nil
+	default:
+		s := make([]float64, len(kvs))
+		for i, kv := range kvs {
+			s[i] = kv.Value.AsFloat64()
+		}
+		return attribute.Float64Slice(k, s), nil
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Resource attributes other than
+// service.name are decoded into AdditionalProperties.
+func (j *Attributes) UnmarshalJSON(b []byte) error {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var raw map[string]interface{}
+	if err := d.Decode(&raw); err != nil {
+		return err
+	}
+	type Plain Attributes
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	delete(raw, "service.name")
+	if len(raw) > 0 {
+		plain.AdditionalProperties = raw
+	}
+	*j = Attributes(plain)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (j Attributes) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(j.AdditionalProperties)+1)
+	for k, v := range j.AdditionalProperties {
+		m[k] = v
+	}
+	if j.ServiceName != nil {
+		m["service.name"] = *j.ServiceName
+	}
+	return json.Marshal(m)
 }
@@ -4,13 +4,17 @@
 package config // import "go.opentelemetry.io/contrib/config"
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/sdk/resource"
-	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
 func TestNewResource(t *testing.T) {
@@ -55,12 +59,143 @@ func TestNewResource(t *testing.T) {
 			},
 			wantResource: res,
 		},
+		{
+			name: "resource-with-attributes-no-schema",
+			config: &Resource{
+				Attributes: &Attributes{
+					ServiceName: ptr("service-a"),
+				},
+			},
+			wantResource: res,
+		},
+		{
+			name: "resource-with-additional-attributes",
+			config: &Resource{
+				Attributes: &Attributes{
+					ServiceName: ptr("service-a"),
+					AdditionalProperties: map[string]interface{}{
+						"bool":         true,
+						"int":          json.Number("1"),
+						"float":        1.5,
+						"string":       "value",
+						"string-array": []interface{}{"a", "b"},
+						"int-array":    []interface{}{json.Number("1"), json.Number("2")},
+					},
+				},
+			},
+			wantResource: resource.NewWithAttributes(semconv.SchemaURL,
+				append(resource.Default().Attributes(),
+					semconv.ServiceName("service-a"),
+					attribute.Bool("bool", true),
+					attribute.Int64("int", 1),
+					attribute.Float64("float", 1.5),
+					attribute.String("string", "value"),
+					attribute.StringSlice("string-array", []string{"a", "b"}),
+					attribute.Int64Slice("int-array", []int64{1, 2}),
+				)...,
+			),
+		},
+		{
+			name: "resource-with-invalid-attributes",
+			config: &Resource{
+				Attributes: &Attributes{
+					AdditionalProperties: map[string]interface{}{
+						"map":         map[string]interface{}{"key": "value"},
+						"mixed-array": []interface{}{"a", true},
+					},
+				},
+			},
+			wantErr: errors.Join(
+				errors.New(`resource attribute "map": unsupported value type map[string]interface {}`),
+				errors.New(`resource attribute "mixed-array": array values must be of the same type`),
+			),
+		},
+		{
+			name: "resource-with-detectors",
+			config: &Resource{
+				Detectors: []string{"env"},
+				Attributes: &Attributes{
+					ServiceName: ptr("service-a"),
+				},
+			},
+			// Configured attributes take precedence over detected ones.
+			wantResource: resource.NewWithAttributes(semconv.SchemaURL,
+				append(resource.Default().Attributes(),
+					semconv.ServiceName("service-a"),
+					attribute.String("env.key", "env-value"),
+				)...,
+			),
+		},
+		{
+			name: "resource-with-unsupported-detector",
+			config: &Resource{
+				Detectors: []string{"invalid"},
+			},
+			wantErr: errors.New(`unsupported resource detector "invalid", supported values: ["aws/ec2" "aws/ecs" "aws/eks" "aws/lambda" "container" "env" "gcp" "host" "process"]`),
+		},
 	}
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "env.key=env-value,service.name=env-service")
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := newResource(tt.config)
+			got, err := newResource(context.Background(), tt.config)
+			if tt.wantErr != nil && !errors.Is(tt.wantErr, resource.ErrSchemaURLConflict) {
+				require.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
 			assert.ErrorIs(t, err, tt.wantErr)
-			assert.Equal(t, tt.wantResource, got)
+			assert.Equal(t, tt.wantResource.Set(), got.Set())
+			assert.Equal(t, tt.wantResource.SchemaURL(), got.SchemaURL())
+		})
+	}
+}
+
+func TestResourceDetectors(t *testing.T) {
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "")
+	for _, name := range []string{"host", "process", "container", "env"} {
+		t.Run(name, func(t *testing.T) {
+			got, err := detectResource(context.Background(), name)
+			require.NoError(t, err)
+			require.NotNil(t, got)
 		})
 	}
+
+	got, err := newResource(context.Background(), &Resource{
+		Detectors: []string{"host"},
+	})
+	require.NoError(t, err)
+	_, ok := got.Set().Value(semconv.HostNameKey)
+	assert.True(t, ok, "host.name attribute not detected")
+}
+
+func TestResourceDetectorsAWS(t *testing.T) {
+	t.Setenv("ECS_CONTAINER_METADATA_URI", "")
+	t.Setenv("ECS_CONTAINER_METADATA_URI_V4", "")
+	// The detectors return an empty resource outside of ECS and EKS.
+	for _, name := range []string{"aws/ecs", "aws/eks"} {
+		t.Run(name, func(t *testing.T) {
+			got, err := detectResource(context.Background(), name)
+			require.NoError(t, err)
+			assert.Equal(t, 0, got.Len())
+		})
+	}
+	// The EC2 detector queries the instance metadata service, not available
+	// in tests.
+	assert.Contains(t, resourceDetectors, "aws/ec2")
+}
+
+func TestAttributesJSON(t *testing.T) {
+	var attrs Attributes
+	require.NoError(t, json.Unmarshal([]byte(`{"service.name":"service-a","int":1,"float":1.5,"array":["a","b"]}`), &attrs))
+	assert.Equal(t, Attributes{
+		ServiceName: ptr("service-a"),
+		AdditionalProperties: map[string]interface{}{
+			"int":   json.Number("1"),
+			"float": json.Number("1.5"),
+			"array": []interface{}{"a", "b"},
+		},
+	}, attrs)
+
+	b, err := json.Marshal(attrs)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"service.name":"service-a","int":1,"float":1.5,"array":["a","b"]}`, string(b))
 }
@@ -7,14 +7,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"time"
 
+	"google.golang.org/grpc/credentials"
+
+	"go.opentelemetry.io/contrib/samplers/jaegerremote"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/exporters/zipkin"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
 	"go.opentelemetry.io/otel/trace/noop"
 )
@@ -27,6 +33,22 @@ func tracerProvider(cfg configOptions, res *resource.Resource) (trace.TracerProv
 		sdktrace.WithResource(res),
 	}
 	var errs []error
+
+	sl, err := spanLimits(cfg.opentelemetryConfig.TracerProvider.Limits, cfg.opentelemetryConfig.AttributeLimits)
+	if err == nil {
+		opts = append(opts, sdktrace.WithRawSpanLimits(sl))
+	} else {
+		errs = append(errs, err)
+	}
+
+	sb := samplerBuilder{ctx: cfg.ctx, serviceName: serviceName(res)}
+	s, err := sb.sampler(cfg.opentelemetryConfig.TracerProvider.Sampler)
+	if err == nil {
+		opts = append(opts, sdktrace.WithSampler(s))
+	} else {
+		errs = append(errs, err)
+	}
+
 	for _, processor := range cfg.opentelemetryConfig.TracerProvider.Processors {
 		sp, err := spanProcessor(cfg.ctx, processor)
 		if err == nil {
@@ -36,14 +58,208 @@ func tracerProvider(cfg configOptions, res *resource.Resource) (trace.TracerProv
 		}
 	}
 	if len(errs) > 0 {
+		sb.close()
 		return noop.NewTracerProvider(), noopShutdown, errors.Join(errs...)
 	}
+	if cfg.inFlightSpans != nil {
+		// Registered last so that the spans are passed to the other
+		// processors before they are no longer counted.
+		opts = append(opts, sdktrace.WithSpanProcessor(cfg.inFlightSpans))
+	}
 	tp := sdktrace.NewTracerProvider(opts...)
-	return tp, tp.Shutdown, nil
+	return tp, func(ctx context.Context) error {
+		err := tp.Shutdown(ctx)
+		sb.close()
+		return err
+	}, nil
+}
+
+// spanLimits returns the span limits of the configuration model. Limits that
+// are not set default to the general attribute limits, if applicable, or the
+// SDK defaults.
+func spanLimits(limits *SpanLimits, attrLimits *AttributeLimits) (sdktrace.SpanLimits, error) {
+	if limits == nil {
+		limits = &SpanLimits{}
+	}
+	if attrLimits == nil {
+		attrLimits = &AttributeLimits{}
+	}
+
+	sl := sdktrace.NewSpanLimits()
+	for _, l := range []struct {
+		name     string
+		value    *int
+		fallback *int
+		dst      *int
+	}{
+		{"attribute count limit", limits.AttributeCountLimit, attrLimits.AttributeCountLimit, &sl.AttributeCountLimit},
+		{"attribute value length limit", limits.AttributeValueLengthLimit, attrLimits.AttributeValueLengthLimit, &sl.AttributeValueLengthLimit},
+		{"event count limit", limits.EventCountLimit, nil, &sl.EventCountLimit},
+		{"event attribute count limit", limits.EventAttributeCountLimit, nil, &sl.AttributePerEventCountLimit},
+		{"link count limit", limits.LinkCountLimit, nil, &sl.LinkCountLimit},
+		{"link attribute count limit", limits.LinkAttributeCountLimit, nil, &sl.AttributePerLinkCountLimit},
+	} {
+		v, err := limit(l.name, l.value, l.fallback)
+		if err != nil {
+			return sdktrace.SpanLimits{}, err
+		}
+		if v != nil {
+			*l.dst = *v
+		}
+	}
+	return sl, nil
+}
+
+// serviceName returns the service.name attribute of res, if any.
+func serviceName(res *resource.Resource) string {
+	if v, ok := res.Set().Value(semconv.ServiceNameKey); ok {
+		return v.AsString()
+	}
+	return ""
+}
+
+// samplerBuilder creates samplers from the configuration model. It keeps
+// track of the remote samplers it creates so that their background polling
+// can be stopped when the TracerProvider is shut down.
+type samplerBuilder struct {
+	ctx         context.Context
+	serviceName string
+	remotes     []*jaegerremote.Sampler
+}
+
+func (sb *samplerBuilder) sampler(s *Sampler) (sdktrace.Sampler, error) {
+	if s == nil {
+		// If omitted, parent based sampler with a root of always_on is used.
+		return sdktrace.ParentBased(sdktrace.AlwaysSample()), nil
+	}
+
+	var set int
+	for _, ok := range []bool{
+		s.AlwaysOff != nil,
+		s.AlwaysOn != nil,
+		s.JaegerRemote != nil,
+		s.ParentBased != nil,
+		s.TraceIDRatioBased != nil,
+	} {
+		if ok {
+			set++
+		}
+	}
+	set += len(s.AdditionalProperties)
+	if set > 1 {
+		return nil, errors.New("must not specify multiple sampler types")
+	}
+
+	switch {
+	case s.AlwaysOff != nil:
+		return sdktrace.NeverSample(), nil
+	case s.AlwaysOn != nil:
+		return sdktrace.AlwaysSample(), nil
+	case s.JaegerRemote != nil:
+		return sb.jaegerRemoteSampler(s.JaegerRemote)
+	case s.ParentBased != nil:
+		return sb.parentBasedSampler(s.ParentBased)
+	case s.TraceIDRatioBased != nil:
+		return traceIDRatioBasedSampler(s.TraceIDRatioBased)
+	}
+	for name, cfg := range s.AdditionalProperties {
+		return samplers.load(sb.ctx, name, cfg)
+	}
+	return nil, errors.New("unsupported sampler type")
+}
+
+func traceIDRatioBasedSampler(s *SamplerTraceIDRatioBased) (sdktrace.Sampler, error) {
+	if s.Ratio == nil {
+		return sdktrace.TraceIDRatioBased(1), nil
+	}
+	if *s.Ratio < 0 || *s.Ratio > 1 {
+		return nil, fmt.Errorf("invalid sampling ratio %v", *s.Ratio)
+	}
+	return sdktrace.TraceIDRatioBased(*s.Ratio), nil
+}
+
+func (sb *samplerBuilder) parentBasedSampler(s *SamplerParentBased) (sdktrace.Sampler, error) {
+	root := sdktrace.AlwaysSample()
+	if s.Root != nil {
+		var err error
+		root, err = sb.sampler(s.Root)
+		if err != nil {
+			return nil, fmt.Errorf("parent_based root: %w", err)
+		}
+	}
+
+	var opts []sdktrace.ParentBasedSamplerOption
+	for _, delegate := range []struct {
+		name    string
+		sampler *Sampler
+		option  func(sdktrace.Sampler) sdktrace.ParentBasedSamplerOption
+	}{
+		{"remote_parent_sampled", s.RemoteParentSampled, sdktrace.WithRemoteParentSampled},
+		{"remote_parent_not_sampled", s.RemoteParentNotSampled, sdktrace.WithRemoteParentNotSampled},
+		{"local_parent_sampled", s.LocalParentSampled, sdktrace.WithLocalParentSampled},
+		{"local_parent_not_sampled", s.LocalParentNotSampled, sdktrace.WithLocalParentNotSampled},
+	} {
+		if delegate.sampler == nil {
+			continue
+		}
+		d, err := sb.sampler(delegate.sampler)
+		if err != nil {
+			return nil, fmt.Errorf("parent_based %s: %w", delegate.name, err)
+		}
+		opts = append(opts, delegate.option(d))
+	}
+	return sdktrace.ParentBased(root, opts...), nil
+}
+
+func (sb *samplerBuilder) jaegerRemoteSampler(s *SamplerJaegerRemote) (sdktrace.Sampler, error) {
+	var opts []jaegerremote.Option
+	if s.Endpoint != nil {
+		u, err := url.ParseRequestURI(*s.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, jaegerremote.WithSamplingServerURL(u.String()))
+	}
+	if s.Interval != nil {
+		if *s.Interval <= 0 {
+			return nil, fmt.Errorf("invalid jaeger_remote interval %d", *s.Interval)
+		}
+		opts = append(opts, jaegerremote.WithSamplingRefreshInterval(time.Millisecond*time.Duration(*s.Interval)))
+	}
+	if s.InitialSampler != nil {
+		initial, err := sb.sampler(s.InitialSampler)
+		if err != nil {
+			return nil, fmt.Errorf("jaeger_remote initial_sampler: %w", err)
+		}
+		opts = append(opts, jaegerremote.WithInitialSampler(initial))
+	}
+
+	remote := jaegerremote.New(sb.serviceName, opts...)
+	sb.remotes = append(sb.remotes, remote)
+	return remote, nil
+}
+
+// close stops the background polling of all remote samplers created by sb.
+func (sb *samplerBuilder) close() {
+	for _, r := range sb.remotes {
+		r.Close()
+	}
+	sb.remotes = nil
 }
 
 func spanExporter(ctx context.Context, exporter SpanExporter) (sdktrace.SpanExporter, error) {
-	if exporter.Console != nil && exporter.OTLP != nil {
+	var set int
+	for _, ok := range []bool{
+		exporter.Console != nil,
+		exporter.OTLP != nil,
+		exporter.Zipkin != nil,
+	} {
+		if ok {
+			set++
+		}
+	}
+	set += len(exporter.AdditionalProperties)
+	if set > 1 {
 		return nil, errors.New("must not specify multiple exporters")
 	}
 
@@ -62,11 +278,40 @@ func spanExporter(ctx context.Context, exporter SpanExporter) (sdktrace.SpanExpo
 			return nil, fmt.Errorf("unsupported protocol %q", exporter.OTLP.Protocol)
 		}
 	}
+	if exporter.Zipkin != nil {
+		return zipkinSpanExporter(exporter.Zipkin)
+	}
+	for name, cfg := range exporter.AdditionalProperties {
+		return spanExporters.load(ctx, name, cfg)
+	}
 	return nil, errors.New("no valid span exporter")
 }
 
+func zipkinSpanExporter(zipkinConfig *Zipkin) (sdktrace.SpanExporter, error) {
+	var opts []zipkin.Option
+	if zipkinConfig.Timeout != nil {
+		if *zipkinConfig.Timeout < 0 {
+			return nil, fmt.Errorf("invalid zipkin timeout %d", *zipkinConfig.Timeout)
+		}
+		opts = append(opts, zipkin.WithClient(&http.Client{
+			Timeout: time.Millisecond * time.Duration(*zipkinConfig.Timeout),
+		}))
+	}
+	return zipkin.New(zipkinConfig.Endpoint, opts...)
+}
+
 func spanProcessor(ctx context.Context, processor SpanProcessor) (sdktrace.SpanProcessor, error) {
-	if processor.Batch != nil && processor.Simple != nil {
+	var set int
+	for _, ok := range []bool{
+		processor.Batch != nil,
+		processor.Simple != nil,
+	} {
+		if ok {
+			set++
+		}
+	}
+	set += len(processor.AdditionalProperties)
+	if set > 1 {
 		return nil, errors.New("must not specify multiple span processor type")
 	}
 	if processor.Batch != nil {
@@ -83,6 +328,9 @@ func spanProcessor(ctx context.Context, processor SpanProcessor) (sdktrace.SpanP
 		}
 		return sdktrace.NewSimpleSpanProcessor(exp), nil
 	}
+	for name, cfg := range processor.AdditionalProperties {
+		return spanProcessors.load(ctx, name, cfg)
+	}
 	return nil, fmt.Errorf("unsupported span processor type %v", processor)
 }
 
@@ -126,6 +374,13 @@ func otlpGRPCSpanExporter(ctx context.Context, otlpConfig *OTLP) (sdktrace.SpanE
 	if len(otlpConfig.Headers) > 0 {
 		opts = append(opts, otlptracegrpc.WithHeaders(otlpConfig.Headers))
 	}
+	tlsCfg, err := tlsConfig(otlpConfig.Certificate, otlpConfig.ClientCertificate, otlpConfig.ClientKey)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(tlsCfg)))
+	}
 
 	return otlptracegrpc.New(ctx, opts...)
 }
@@ -163,6 +418,13 @@ func otlpHTTPSpanExporter(ctx context.Context, otlpConfig *OTLP) (sdktrace.SpanE
 	if len(otlpConfig.Headers) > 0 {
 		opts = append(opts, otlptracehttp.WithHeaders(otlpConfig.Headers))
 	}
+	tlsCfg, err := tlsConfig(otlpConfig.Certificate, otlpConfig.ClientCertificate, otlpConfig.ClientKey)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsCfg))
+	}
 
 	return otlptracehttp.New(ctx, opts...)
 }
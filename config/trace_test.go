@@ -5,9 +5,18 @@ package config
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -16,8 +25,11 @@ import (
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/exporters/zipkin"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
 	"go.opentelemetry.io/otel/trace/noop"
 )
@@ -75,6 +87,32 @@ func TestTracerPovider(t *testing.T) {
 			wantProvider: noop.NewTracerProvider(),
 			wantErr:      errors.Join(errors.New("must not specify multiple span processor type"), errors.New("must not specify multiple exporters")),
 		},
+		{
+			name: "invalid-sampler-config",
+			cfg: configOptions{
+				opentelemetryConfig: OpenTelemetryConfiguration{
+					TracerProvider: &TracerProvider{
+						Sampler: &Sampler{},
+					},
+				},
+			},
+			wantProvider: noop.NewTracerProvider(),
+			wantErr:      errors.Join(errors.New("unsupported sampler type")),
+		},
+		{
+			name: "invalid-span-limits",
+			cfg: configOptions{
+				opentelemetryConfig: OpenTelemetryConfiguration{
+					TracerProvider: &TracerProvider{
+						Limits: &SpanLimits{
+							LinkCountLimit: ptr(-1),
+						},
+					},
+				},
+			},
+			wantProvider: noop.NewTracerProvider(),
+			wantErr:      errors.Join(errors.New("invalid link count limit -1")),
+		},
 	}
 	for _, tt := range tests {
 		tp, shutdown, err := tracerProvider(tt.cfg, resource.Default())
@@ -84,6 +122,99 @@ func TestTracerPovider(t *testing.T) {
 	}
 }
 
+func TestSpanLimits(t *testing.T) {
+	defaults := sdktrace.NewSpanLimits()
+	for _, tt := range []struct {
+		name       string
+		limits     *SpanLimits
+		attrLimits *AttributeLimits
+		want       func(*sdktrace.SpanLimits)
+		wantErr    error
+	}{
+		{
+			name: "no-limits",
+		},
+		{
+			name: "all-limits",
+			limits: &SpanLimits{
+				AttributeCountLimit:       ptr(1),
+				AttributeValueLengthLimit: ptr(2),
+				EventCountLimit:           ptr(3),
+				EventAttributeCountLimit:  ptr(4),
+				LinkCountLimit:            ptr(5),
+				LinkAttributeCountLimit:   ptr(0),
+			},
+			want: func(sl *sdktrace.SpanLimits) {
+				sl.AttributeCountLimit = 1
+				sl.AttributeValueLengthLimit = 2
+				sl.EventCountLimit = 3
+				sl.AttributePerEventCountLimit = 4
+				sl.LinkCountLimit = 5
+				sl.AttributePerLinkCountLimit = 0
+			},
+		},
+		{
+			name: "attribute-limits-fallback",
+			attrLimits: &AttributeLimits{
+				AttributeCountLimit:       ptr(10),
+				AttributeValueLengthLimit: ptr(20),
+			},
+			want: func(sl *sdktrace.SpanLimits) {
+				sl.AttributeCountLimit = 10
+				sl.AttributeValueLengthLimit = 20
+			},
+		},
+		{
+			name: "span-limits-override-attribute-limits",
+			limits: &SpanLimits{
+				AttributeCountLimit: ptr(5),
+			},
+			attrLimits: &AttributeLimits{
+				AttributeCountLimit:       ptr(10),
+				AttributeValueLengthLimit: ptr(20),
+			},
+			want: func(sl *sdktrace.SpanLimits) {
+				sl.AttributeCountLimit = 5
+				sl.AttributeValueLengthLimit = 20
+			},
+		},
+		{
+			name: "invalid-event-count-limit",
+			limits: &SpanLimits{
+				EventCountLimit: ptr(-1),
+			},
+			wantErr: errors.New("invalid event count limit -1"),
+		},
+		{
+			name: "invalid-link-attribute-count-limit",
+			limits: &SpanLimits{
+				LinkAttributeCountLimit: ptr(-2),
+			},
+			wantErr: errors.New("invalid link attribute count limit -2"),
+		},
+		{
+			name: "invalid-attribute-limits",
+			attrLimits: &AttributeLimits{
+				AttributeValueLengthLimit: ptr(-3),
+			},
+			wantErr: errors.New("attribute_limits: invalid attribute value length limit -3"),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := spanLimits(tt.limits, tt.attrLimits)
+			require.Equal(t, tt.wantErr, err)
+			if tt.wantErr != nil {
+				return
+			}
+			want := defaults
+			if tt.want != nil {
+				tt.want(&want)
+			}
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
 func TestSpanProcessor(t *testing.T) {
 	consoleExporter, err := stdouttrace.New(
 		stdouttrace.WithPrettyPrint(),
@@ -94,6 +225,9 @@ func TestSpanProcessor(t *testing.T) {
 	require.NoError(t, err)
 	otlpHTTPExporter, err := otlptracehttp.New(ctx)
 	require.NoError(t, err)
+	ca, cert, key := writeTLSFiles(t)
+	zipkinExporter, err := zipkin.New("http://localhost:9411/api/v2/spans")
+	require.NoError(t, err)
 	testCases := []struct {
 		name          string
 		processor     SpanProcessor
@@ -103,7 +237,7 @@ func TestSpanProcessor(t *testing.T) {
 	}{
 		{
 			name:    "no processor",
-			wantErr: errors.New("unsupported span processor type {<nil> <nil>}"),
+			wantErr: errors.New("unsupported span processor type {<nil> <nil> map[]}"),
 		},
 		{
 			name: "multiple processor types",
@@ -487,6 +621,93 @@ func TestSpanProcessor(t *testing.T) {
 			},
 			wantProcessor: sdktrace.NewSimpleSpanProcessor(consoleExporter),
 		},
+		{
+			name: "simple/zipkin-exporter",
+			processor: SpanProcessor{
+				Simple: &SimpleSpanProcessor{
+					Exporter: SpanExporter{
+						Zipkin: &Zipkin{
+							Endpoint: "http://localhost:9411/api/v2/spans",
+							Timeout:  ptr(1000),
+						},
+					},
+				},
+			},
+			wantProcessor: sdktrace.NewSimpleSpanProcessor(zipkinExporter),
+		},
+		{
+			name: "simple/zipkin-exporter-invalid-timeout",
+			processor: SpanProcessor{
+				Simple: &SimpleSpanProcessor{
+					Exporter: SpanExporter{
+						Zipkin: &Zipkin{
+							Endpoint: "http://localhost:9411/api/v2/spans",
+							Timeout:  ptr(-1),
+						},
+					},
+				},
+			},
+			wantErr: errors.New("invalid zipkin timeout -1"),
+		},
+		{
+			name: "simple/zipkin-and-otlp-exporters",
+			processor: SpanProcessor{
+				Simple: &SimpleSpanProcessor{
+					Exporter: SpanExporter{
+						OTLP:   &OTLP{},
+						Zipkin: &Zipkin{},
+					},
+				},
+			},
+			wantErr: errors.New("must not specify multiple exporters"),
+		},
+		{
+			name: "batch/otlp-grpc-exporter-tls",
+			processor: SpanProcessor{
+				Batch: &BatchSpanProcessor{
+					Exporter: SpanExporter{
+						OTLP: &OTLP{
+							Protocol:          "grpc/protobuf",
+							Endpoint:          "https://localhost:4317",
+							Certificate:       ptr(ca),
+							ClientCertificate: ptr(cert),
+							ClientKey:         ptr(key),
+						},
+					},
+				},
+			},
+			wantProcessor: sdktrace.NewBatchSpanProcessor(otlpGRPCExporter),
+		},
+		{
+			name: "batch/otlp-grpc-exporter-invalid-tls",
+			processor: SpanProcessor{
+				Batch: &BatchSpanProcessor{
+					Exporter: SpanExporter{
+						OTLP: &OTLP{
+							Protocol:  "grpc/protobuf",
+							Endpoint:  "https://localhost:4317",
+							ClientKey: ptr(key),
+						},
+					},
+				},
+			},
+			wantErr: errors.New("client_certificate and client_key must both be specified"),
+		},
+		{
+			name: "batch/otlp-http-exporter-tls",
+			processor: SpanProcessor{
+				Batch: &BatchSpanProcessor{
+					Exporter: SpanExporter{
+						OTLP: &OTLP{
+							Protocol:    "http/protobuf",
+							Endpoint:    "https://localhost:4318",
+							Certificate: ptr(ca),
+						},
+					},
+				},
+			},
+			wantProcessor: sdktrace.NewBatchSpanProcessor(otlpHTTPExporter),
+		},
 	}
 	for _, tt := range testCases {
 		t.Run(tt.name, func(t *testing.T) {
@@ -510,3 +731,269 @@ func TestSpanProcessor(t *testing.T) {
 		})
 	}
 }
+
+func TestOTLPHTTPSpanExporterMTLS(t *testing.T) {
+	ca, cert, key := writeTLSFiles(t)
+	caPEM, err := os.ReadFile(ca)
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	require.True(t, clientCAs.AppendCertsFromPEM(caPEM))
+
+	var (
+		mu       sync.Mutex
+		requests int
+	)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	srv.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	// The certificate of the test server is self-signed, use it as the
+	// certificate authority of the exporter.
+	serverCA := filepath.Join(t.TempDir(), "server-ca.pem")
+	require.NoError(t, os.WriteFile(serverCA, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: srv.Certificate().Raw,
+	}), 0o600))
+
+	ctx := context.Background()
+	exp, err := otlpHTTPSpanExporter(ctx, &OTLP{
+		Protocol:          "http/protobuf",
+		Endpoint:          srv.URL + "/v1/traces",
+		Certificate:       ptr(serverCA),
+		ClientCertificate: ptr(cert),
+		ClientKey:         ptr(key),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, exp.Shutdown(ctx)) })
+
+	require.NoError(t, exp.ExportSpans(ctx, tracetest.SpanStubs{{Name: "span"}}.Snapshots()))
+	mu.Lock()
+	assert.Equal(t, 1, requests)
+	mu.Unlock()
+
+	// Without the client certificate the server rejects the connection.
+	exp, err = otlpHTTPSpanExporter(ctx, &OTLP{
+		Protocol:    "http/protobuf",
+		Endpoint:    srv.URL + "/v1/traces",
+		Certificate: ptr(serverCA),
+		Timeout:     ptr(1000),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, exp.Shutdown(ctx)) })
+	assert.Error(t, exp.ExportSpans(ctx, tracetest.SpanStubs{{Name: "span"}}.Snapshots()))
+}
+
+func TestSampler(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		sampler     *Sampler
+		wantSampler sdktrace.Sampler
+		wantErr     error
+	}{
+		{
+			name:        "no-sampler-configuration",
+			wantSampler: sdktrace.ParentBased(sdktrace.AlwaysSample()),
+		},
+		{
+			name:    "invalid-sampler",
+			sampler: &Sampler{},
+			wantErr: errors.New("unsupported sampler type"),
+		},
+		{
+			name: "multiple-samplers",
+			sampler: &Sampler{
+				AlwaysOn:  SamplerAlwaysOn{},
+				AlwaysOff: SamplerAlwaysOff{},
+			},
+			wantErr: errors.New("must not specify multiple sampler types"),
+		},
+		{
+			name: "always-on",
+			sampler: &Sampler{
+				AlwaysOn: SamplerAlwaysOn{},
+			},
+			wantSampler: sdktrace.AlwaysSample(),
+		},
+		{
+			name: "always-off",
+			sampler: &Sampler{
+				AlwaysOff: SamplerAlwaysOff{},
+			},
+			wantSampler: sdktrace.NeverSample(),
+		},
+		{
+			name: "trace-id-ratio-based",
+			sampler: &Sampler{
+				TraceIDRatioBased: &SamplerTraceIDRatioBased{
+					Ratio: ptr(0.54),
+				},
+			},
+			wantSampler: sdktrace.TraceIDRatioBased(0.54),
+		},
+		{
+			name: "trace-id-ratio-based-no-ratio",
+			sampler: &Sampler{
+				TraceIDRatioBased: &SamplerTraceIDRatioBased{},
+			},
+			wantSampler: sdktrace.TraceIDRatioBased(1),
+		},
+		{
+			name: "trace-id-ratio-based-invalid-ratio",
+			sampler: &Sampler{
+				TraceIDRatioBased: &SamplerTraceIDRatioBased{
+					Ratio: ptr(1.5),
+				},
+			},
+			wantErr: errors.New("invalid sampling ratio 1.5"),
+		},
+		{
+			name: "parent-based-no-root",
+			sampler: &Sampler{
+				ParentBased: &SamplerParentBased{},
+			},
+			wantSampler: sdktrace.ParentBased(sdktrace.AlwaysSample()),
+		},
+		{
+			name: "parent-based-all-delegates",
+			sampler: &Sampler{
+				ParentBased: &SamplerParentBased{
+					Root: &Sampler{
+						TraceIDRatioBased: &SamplerTraceIDRatioBased{
+							Ratio: ptr(0.25),
+						},
+					},
+					RemoteParentSampled: &Sampler{
+						AlwaysOn: SamplerAlwaysOn{},
+					},
+					RemoteParentNotSampled: &Sampler{
+						AlwaysOff: SamplerAlwaysOff{},
+					},
+					LocalParentSampled: &Sampler{
+						AlwaysOn: SamplerAlwaysOn{},
+					},
+					LocalParentNotSampled: &Sampler{
+						AlwaysOff: SamplerAlwaysOff{},
+					},
+				},
+			},
+			wantSampler: sdktrace.ParentBased(
+				sdktrace.TraceIDRatioBased(0.25),
+				sdktrace.WithRemoteParentSampled(sdktrace.AlwaysSample()),
+				sdktrace.WithRemoteParentNotSampled(sdktrace.NeverSample()),
+				sdktrace.WithLocalParentSampled(sdktrace.AlwaysSample()),
+				sdktrace.WithLocalParentNotSampled(sdktrace.NeverSample()),
+			),
+		},
+		{
+			name: "parent-based-invalid-root",
+			sampler: &Sampler{
+				ParentBased: &SamplerParentBased{
+					Root: &Sampler{},
+				},
+			},
+			wantErr: fmt.Errorf("parent_based root: %w", errors.New("unsupported sampler type")),
+		},
+		{
+			name: "parent-based-invalid-nested-delegate",
+			sampler: &Sampler{
+				ParentBased: &SamplerParentBased{
+					LocalParentNotSampled: &Sampler{
+						ParentBased: &SamplerParentBased{
+							RemoteParentSampled: &Sampler{
+								TraceIDRatioBased: &SamplerTraceIDRatioBased{
+									Ratio: ptr(-1.0),
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: fmt.Errorf("parent_based local_parent_not_sampled: %w",
+				fmt.Errorf("parent_based remote_parent_sampled: %w", errors.New("invalid sampling ratio -1"))),
+		},
+		{
+			name: "jaeger-remote-invalid-endpoint",
+			sampler: &Sampler{
+				JaegerRemote: &SamplerJaegerRemote{
+					Endpoint: ptr(" "),
+				},
+			},
+			wantErr: &url.Error{Op: "parse", URL: " ", Err: errors.New("invalid URI for request")},
+		},
+		{
+			name: "jaeger-remote-invalid-interval",
+			sampler: &Sampler{
+				JaegerRemote: &SamplerJaegerRemote{
+					Interval: ptr(0),
+				},
+			},
+			wantErr: errors.New("invalid jaeger_remote interval 0"),
+		},
+		{
+			name: "jaeger-remote-invalid-initial-sampler",
+			sampler: &Sampler{
+				JaegerRemote: &SamplerJaegerRemote{
+					InitialSampler: &Sampler{},
+				},
+			},
+			wantErr: fmt.Errorf("jaeger_remote initial_sampler: %w", errors.New("unsupported sampler type")),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb samplerBuilder
+			got, err := sb.sampler(tt.sampler)
+			defer sb.close()
+			require.Equal(t, tt.wantErr, err)
+			if tt.wantSampler == nil {
+				require.Nil(t, got)
+			} else {
+				require.Equal(t, tt.wantSampler.Description(), got.Description())
+			}
+		})
+	}
+}
+
+func TestJaegerRemoteSampler(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "service-a", r.URL.Query().Get("service"))
+		_, _ = w.Write([]byte(`{"strategyType": "PROBABILISTIC", "probabilisticSampling": {"samplingRate": 0.5}}`))
+	}))
+	defer srv.Close()
+
+	res := resource.NewSchemaless(semconv.ServiceName("service-a"))
+	tp, shutdown, err := tracerProvider(configOptions{
+		opentelemetryConfig: OpenTelemetryConfiguration{
+			TracerProvider: &TracerProvider{
+				Sampler: &Sampler{
+					ParentBased: &SamplerParentBased{
+						Root: &Sampler{
+							JaegerRemote: &SamplerJaegerRemote{
+								Endpoint: ptr(srv.URL),
+								Interval: ptr(60000),
+								InitialSampler: &Sampler{
+									AlwaysOff: SamplerAlwaysOff{},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, res)
+	require.NoError(t, err)
+	assert.IsType(t, &sdktrace.TracerProvider{}, tp)
+	require.NoError(t, shutdown(context.Background()))
+}
@@ -0,0 +1,427 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package config // import "go.opentelemetry.io/contrib/config"
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/contrib/propagators/autoprop"
+)
+
+// ValidationError is an invalid value of the configuration model.
+type ValidationError struct {
+	// Path is the path of the invalid value in the configuration file, e.g.
+	// "tracer_provider.processors[2].batch.exporter.otlp.protocol".
+	Path string
+	// Value is the invalid value, if any.
+	Value interface{}
+	// Allowed are the values supported at Path, if they can be enumerated.
+	Allowed []string
+	// Reason describes why the value is invalid.
+	Reason string
+}
+
+// Error returns the path, the reason and the allowed values of e.
+func (e *ValidationError) Error() string {
+	msg := e.Path + ": " + e.Reason
+	if len(e.Allowed) > 0 {
+		msg += fmt.Sprintf(", supported values: %q", e.Allowed)
+	}
+	return msg
+}
+
+var (
+	supportedProtocols    = []string{protocolProtobufGRPC, protocolProtobufHTTP}
+	supportedCompressions = []string{compressionGzip, compressionNone}
+	supportedTemporality  = []string{temporalityCumulative, temporalityDelta, temporalityLowMemory}
+)
+
+// Validate checks the configuration model cfg for the errors that would
+// prevent NewSDK from creating the SDK it configures, without creating any
+// of its components.
+//
+// The returned error joins a *ValidationError for each invalid value of
+// cfg. Use errors.As to retrieve the first one, or the Unwrap() []error
+// method of the returned error to retrieve all of them. Nil is returned if cfg
+// is valid.
+func Validate(cfg OpenTelemetryConfiguration) error {
+	var v validator
+	v.validate(&cfg)
+	return errors.Join(v.errs...)
+}
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) add(path string, value interface{}, reason string, allowed ...string) {
+	v.errs = append(v.errs, &ValidationError{
+		Path:    path,
+		Value:   value,
+		Allowed: allowed,
+		Reason:  reason,
+	})
+}
+
+func (v *validator) nonNegative(path string, value *int) {
+	if value != nil && *value < 0 {
+		v.add(path, *value, fmt.Sprintf("invalid value %d, must not be negative", *value))
+	}
+}
+
+func (v *validator) oneOf(path, value string, allowed []string) {
+	for _, a := range allowed {
+		if a == value {
+			return
+		}
+	}
+	v.add(path, value, fmt.Sprintf("unsupported value %q", value), allowed...)
+}
+
+// count returns the number of set fields and custom components of a model
+// that must have exactly one of them set.
+func count(additional map[string]interface{}, set ...bool) int {
+	n := len(additional)
+	for _, ok := range set {
+		if ok {
+			n++
+		}
+	}
+	return n
+}
+
+// custom checks the custom component of the configuration model at path,
+// which must be registered with r.
+func custom[T any](v *validator, path string, additional map[string]interface{}, r *registry[T]) {
+	for name, cfg := range additional {
+		if !r.registered(name) {
+			v.add(path, name, fmt.Sprintf("unsupported type %q", name), r.supported()...)
+			continue
+		}
+		if _, ok := cfg.(map[string]interface{}); cfg != nil && !ok {
+			v.add(path+"."+name, cfg, fmt.Sprintf("invalid settings type %T", cfg))
+		}
+	}
+}
+
+func (v *validator) validate(cfg *OpenTelemetryConfiguration) {
+	// The file format is only set by configuration files, which are checked
+	// when they are parsed.
+	if cfg.FileFormat != "" {
+		v.oneOf("file_format", cfg.FileFormat, supportedFileFormats)
+	}
+	if cfg.AttributeLimits != nil {
+		v.nonNegative("attribute_limits.attribute_count_limit", cfg.AttributeLimits.AttributeCountLimit)
+		v.nonNegative("attribute_limits.attribute_value_length_limit", cfg.AttributeLimits.AttributeValueLengthLimit)
+	}
+	if cfg.Resource != nil {
+		v.resource(cfg.Resource)
+	}
+	if cfg.Propagator != nil {
+		for i, name := range cfg.Propagator.Composite {
+			if name == "" {
+				continue
+			}
+			if _, err := autoprop.TextMapPropagator(name); err != nil {
+				v.add(fmt.Sprintf("propagator.composite[%d]", i), name, err.Error())
+			}
+		}
+	}
+	if cfg.TracerProvider != nil {
+		v.tracerProvider(cfg.TracerProvider)
+	}
+	if cfg.MeterProvider != nil {
+		v.meterProvider(cfg.MeterProvider)
+	}
+	if cfg.LoggerProvider != nil {
+		v.loggerProvider(cfg.LoggerProvider)
+	}
+}
+
+func (v *validator) resource(res *Resource) {
+	supported := make([]string, 0, len(resourceDetectors))
+	for name := range resourceDetectors {
+		supported = append(supported, name)
+	}
+	sort.Strings(supported)
+	for i, name := range res.Detectors {
+		v.oneOf(fmt.Sprintf("resource.detectors[%d]", i), name, supported)
+	}
+
+	if res.Attributes == nil {
+		return
+	}
+	keys := make([]string, 0, len(res.Attributes.AdditionalProperties))
+	for k := range res.Attributes.AdditionalProperties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		value := res.Attributes.AdditionalProperties[k]
+		if _, err := keyValue(k, value); err != nil {
+			reason := strings.TrimPrefix(err.Error(), fmt.Sprintf("resource attribute %q: ", k))
+			v.add(fmt.Sprintf("resource.attributes[%q]", k), value, reason)
+		}
+	}
+}
+
+func (v *validator) tracerProvider(tp *TracerProvider) {
+	if tp.Limits != nil {
+		v.nonNegative("tracer_provider.limits.attribute_count_limit", tp.Limits.AttributeCountLimit)
+		v.nonNegative("tracer_provider.limits.attribute_value_length_limit", tp.Limits.AttributeValueLengthLimit)
+		v.nonNegative("tracer_provider.limits.event_count_limit", tp.Limits.EventCountLimit)
+		v.nonNegative("tracer_provider.limits.event_attribute_count_limit", tp.Limits.EventAttributeCountLimit)
+		v.nonNegative("tracer_provider.limits.link_count_limit", tp.Limits.LinkCountLimit)
+		v.nonNegative("tracer_provider.limits.link_attribute_count_limit", tp.Limits.LinkAttributeCountLimit)
+	}
+	if tp.Sampler != nil {
+		v.sampler("tracer_provider.sampler", tp.Sampler)
+	}
+	for i, p := range tp.Processors {
+		v.spanProcessor(fmt.Sprintf("tracer_provider.processors[%d]", i), p)
+	}
+}
+
+func (v *validator) sampler(path string, s *Sampler) {
+	switch count(s.AdditionalProperties,
+		s.AlwaysOff != nil,
+		s.AlwaysOn != nil,
+		s.JaegerRemote != nil,
+		s.ParentBased != nil,
+		s.TraceIDRatioBased != nil,
+	) {
+	case 0:
+		v.add(path, nil, "no sampler type specified", samplers.supported()...)
+		return
+	case 1:
+	default:
+		v.add(path, nil, "must not specify multiple sampler types")
+		return
+	}
+
+	switch {
+	case s.JaegerRemote != nil:
+		path += ".jaeger_remote"
+		if s.JaegerRemote.Endpoint != nil {
+			if _, err := url.ParseRequestURI(*s.JaegerRemote.Endpoint); err != nil {
+				v.add(path+".endpoint", *s.JaegerRemote.Endpoint, err.Error())
+			}
+		}
+		if s.JaegerRemote.Interval != nil && *s.JaegerRemote.Interval <= 0 {
+			v.add(path+".interval", *s.JaegerRemote.Interval, fmt.Sprintf("invalid value %d, must be positive", *s.JaegerRemote.Interval))
+		}
+		if s.JaegerRemote.InitialSampler != nil {
+			v.sampler(path+".initial_sampler", s.JaegerRemote.InitialSampler)
+		}
+	case s.ParentBased != nil:
+		path += ".parent_based"
+		for _, delegate := range []struct {
+			name    string
+			sampler *Sampler
+		}{
+			{"root", s.ParentBased.Root},
+			{"remote_parent_sampled", s.ParentBased.RemoteParentSampled},
+			{"remote_parent_not_sampled", s.ParentBased.RemoteParentNotSampled},
+			{"local_parent_sampled", s.ParentBased.LocalParentSampled},
+			{"local_parent_not_sampled", s.ParentBased.LocalParentNotSampled},
+		} {
+			if delegate.sampler != nil {
+				v.sampler(path+"."+delegate.name, delegate.sampler)
+			}
+		}
+	case s.TraceIDRatioBased != nil:
+		if r := s.TraceIDRatioBased.Ratio; r != nil && (*r < 0 || *r > 1) {
+			v.add(path+".trace_id_ratio_based.ratio", *r, fmt.Sprintf("invalid value %v, must be in the range [0, 1]", *r))
+		}
+	default:
+		custom(v, path, s.AdditionalProperties, samplers)
+	}
+}
+
+func (v *validator) spanProcessor(path string, p SpanProcessor) {
+	switch count(p.AdditionalProperties, p.Batch != nil, p.Simple != nil) {
+	case 0:
+		v.add(path, nil, "no span processor type specified", spanProcessors.supported()...)
+		return
+	case 1:
+	default:
+		v.add(path, nil, "must not specify multiple span processor type")
+		return
+	}
+
+	switch {
+	case p.Batch != nil:
+		path += ".batch"
+		v.nonNegative(path+".export_timeout", p.Batch.ExportTimeout)
+		v.nonNegative(path+".max_export_batch_size", p.Batch.MaxExportBatchSize)
+		v.nonNegative(path+".max_queue_size", p.Batch.MaxQueueSize)
+		v.nonNegative(path+".schedule_delay", p.Batch.ScheduleDelay)
+		v.spanExporter(path+".exporter", p.Batch.Exporter)
+	case p.Simple != nil:
+		v.spanExporter(path+".simple.exporter", p.Simple.Exporter)
+	default:
+		custom(v, path, p.AdditionalProperties, spanProcessors)
+	}
+}
+
+func (v *validator) spanExporter(path string, e SpanExporter) {
+	switch count(e.AdditionalProperties, e.Console != nil, e.OTLP != nil, e.Zipkin != nil) {
+	case 0:
+		v.add(path, nil, "no exporter specified", spanExporters.supported()...)
+		return
+	case 1:
+	default:
+		v.add(path, nil, "must not specify multiple exporters")
+		return
+	}
+
+	switch {
+	case e.OTLP != nil:
+		v.otlp(path+".otlp", e.OTLP)
+	case e.Zipkin != nil:
+		if _, err := url.ParseRequestURI(e.Zipkin.Endpoint); err != nil {
+			v.add(path+".zipkin.endpoint", e.Zipkin.Endpoint, err.Error())
+		}
+		v.nonNegative(path+".zipkin.timeout", e.Zipkin.Timeout)
+	case e.Console != nil:
+	default:
+		custom(v, path, e.AdditionalProperties, spanExporters)
+	}
+}
+
+func (v *validator) otlp(path string, o *OTLP) {
+	v.otlpCommon(path, o.Protocol, o.Endpoint, o.Compression, o.Timeout, o.ClientCertificate, o.ClientKey)
+}
+
+func (v *validator) otlpCommon(path, protocol, endpoint string, compression *string, timeout *int, clientCertificate, clientKey *string) {
+	v.oneOf(path+".protocol", protocol, supportedProtocols)
+	if endpoint != "" {
+		if _, err := url.ParseRequestURI(endpoint); err != nil {
+			v.add(path+".endpoint", endpoint, err.Error())
+		}
+	}
+	if compression != nil {
+		v.oneOf(path+".compression", *compression, supportedCompressions)
+	}
+	v.nonNegative(path+".timeout", timeout)
+	if (clientCertificate == nil) != (clientKey == nil) {
+		v.add(path, nil, "client_certificate and client_key must both be specified")
+	}
+}
+
+func (v *validator) meterProvider(mp *MeterProvider) {
+	for i, r := range mp.Readers {
+		v.metricReader(fmt.Sprintf("meter_provider.readers[%d]", i), r)
+	}
+	for i, vw := range mp.Views {
+		if _, err := view(vw); err != nil {
+			v.add(fmt.Sprintf("meter_provider.views[%d]", i), nil, err.Error())
+		}
+	}
+}
+
+func (v *validator) metricReader(path string, r MetricReader) {
+	switch {
+	case r.Periodic != nil && r.Pull != nil:
+		v.add(path, nil, "must not specify multiple metric reader type")
+	case r.Periodic != nil:
+		path += ".periodic"
+		v.nonNegative(path+".interval", r.Periodic.Interval)
+		v.nonNegative(path+".timeout", r.Periodic.Timeout)
+		v.periodicExporter(path+".exporter", r.Periodic.Exporter)
+	case r.Pull != nil:
+		path += ".pull.exporter"
+		e := r.Pull.Exporter
+		if e.Prometheus == nil || count(e.AdditionalProperties, e.Console != nil, e.OTLP != nil) > 0 {
+			v.add(path, nil, "pull metric reader only supports the prometheus exporter")
+			return
+		}
+		if e.Prometheus.Host == nil {
+			v.add(path+".prometheus.host", nil, "host must be specified")
+		}
+		if e.Prometheus.Port == nil {
+			v.add(path+".prometheus.port", nil, "port must be specified")
+		}
+	default:
+		v.add(path, nil, "no metric reader type specified", "periodic", "pull")
+	}
+}
+
+func (v *validator) periodicExporter(path string, e MetricExporter) {
+	if e.Prometheus != nil {
+		v.add(path+".prometheus", nil, "prometheus exporter must be used with a pull metric reader")
+		return
+	}
+	switch count(e.AdditionalProperties, e.Console != nil, e.OTLP != nil) {
+	case 0:
+		v.add(path, nil, "no exporter specified", metricExporters.supported()...)
+		return
+	case 1:
+	default:
+		v.add(path, nil, "must not specify multiple exporters")
+		return
+	}
+
+	switch {
+	case e.OTLP != nil:
+		path += ".otlp"
+		o := e.OTLP
+		v.otlpCommon(path, o.Protocol, o.Endpoint, o.Compression, o.Timeout, o.ClientCertificate, o.ClientKey)
+		if o.TemporalityPreference != nil {
+			v.oneOf(path+".temporality_preference", *o.TemporalityPreference, supportedTemporality)
+		}
+		if o.DefaultHistogramAggregation != nil {
+			if _, err := aggregationSelector(*o.DefaultHistogramAggregation); err != nil {
+				allowed := make([]string, len(enumValues_OTLPMetricDefaultHistogramAggregation))
+				for i, a := range enumValues_OTLPMetricDefaultHistogramAggregation {
+					allowed[i] = fmt.Sprint(a)
+				}
+				v.add(path+".default_histogram_aggregation", string(*o.DefaultHistogramAggregation), err.Error(), allowed...)
+			}
+		}
+	case e.Console != nil:
+	default:
+		custom(v, path, e.AdditionalProperties, metricExporters)
+	}
+}
+
+func (v *validator) loggerProvider(lp *LoggerProvider) {
+	if lp.Limits != nil {
+		v.nonNegative("logger_provider.limits.attribute_count_limit", lp.Limits.AttributeCountLimit)
+		v.nonNegative("logger_provider.limits.attribute_value_length_limit", lp.Limits.AttributeValueLengthLimit)
+	}
+	for i, p := range lp.Processors {
+		path := fmt.Sprintf("logger_provider.processors[%d]", i)
+		switch {
+		case p.Batch != nil && p.Simple != nil:
+			v.add(path, nil, "must not specify multiple log processor type")
+		case p.Batch != nil:
+			path += ".batch"
+			v.nonNegative(path+".export_timeout", p.Batch.ExportTimeout)
+			v.nonNegative(path+".max_export_batch_size", p.Batch.MaxExportBatchSize)
+			v.nonNegative(path+".max_queue_size", p.Batch.MaxQueueSize)
+			v.nonNegative(path+".schedule_delay", p.Batch.ScheduleDelay)
+			v.logExporter(path+".exporter", p.Batch.Exporter)
+		case p.Simple != nil:
+			v.logExporter(path+".simple.exporter", p.Simple.Exporter)
+		default:
+			v.add(path, nil, "no log processor type specified", "batch", "simple")
+		}
+	}
+}
+
+func (v *validator) logExporter(path string, e LogRecordExporter) {
+	switch {
+	case e.Console != nil && e.OTLP != nil:
+		v.add(path, nil, "must not specify multiple exporters")
+	case e.OTLP != nil:
+		v.otlp(path+".otlp", e.OTLP)
+	case e.Console == nil:
+		v.add(path, nil, "no exporter specified", "console", "otlp")
+	}
+}
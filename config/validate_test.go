@@ -0,0 +1,218 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	otlpExporter := SpanExporter{OTLP: &OTLP{Protocol: protocolProtobufHTTP, Endpoint: "http://localhost:4318"}}
+
+	tests := []struct {
+		name string
+		cfg  OpenTelemetryConfiguration
+		want []*ValidationError
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "valid",
+			cfg:  v01OpenTelemetryConfig,
+		},
+		{
+			name: "file format and limits",
+			cfg: OpenTelemetryConfiguration{
+				FileFormat:      "0.2",
+				AttributeLimits: &AttributeLimits{AttributeCountLimit: ptr(-1)},
+				TracerProvider: &TracerProvider{
+					Limits: &SpanLimits{LinkCountLimit: ptr(-2)},
+				},
+				LoggerProvider: &LoggerProvider{
+					Limits: &LogRecordLimits{AttributeValueLengthLimit: ptr(-3)},
+				},
+			},
+			want: []*ValidationError{
+				{Path: "file_format", Value: "0.2", Allowed: []string{"0.1"}, Reason: `unsupported value "0.2"`},
+				{Path: "attribute_limits.attribute_count_limit", Value: -1, Reason: "invalid value -1, must not be negative"},
+				{Path: "tracer_provider.limits.link_count_limit", Value: -2, Reason: "invalid value -2, must not be negative"},
+				{Path: "logger_provider.limits.attribute_value_length_limit", Value: -3, Reason: "invalid value -3, must not be negative"},
+			},
+		},
+		{
+			name: "resource and propagator",
+			cfg: OpenTelemetryConfiguration{
+				Resource: &Resource{
+					Detectors: []string{"host", "unknown"},
+					Attributes: &Attributes{AdditionalProperties: map[string]interface{}{
+						"valid":   "value",
+						"invalid": map[string]interface{}{},
+					}},
+				},
+				Propagator: &Propagator{Composite: []string{"tracecontext", "", "invalid"}},
+			},
+			want: []*ValidationError{
+				{
+					Path:    "resource.detectors[1]",
+					Value:   "unknown",
+					Allowed: []string{"aws/ec2", "aws/ecs", "aws/eks", "aws/lambda", "container", "env", "gcp", "host", "process"},
+					Reason:  `unsupported value "unknown"`,
+				},
+				{Path: `resource.attributes["invalid"]`, Value: map[string]interface{}{}, Reason: "unsupported value type map[string]interface {}"},
+				{Path: "propagator.composite[2]", Value: "invalid", Reason: "unknown propagator: invalid"},
+			},
+		},
+		{
+			name: "tracer provider",
+			cfg: OpenTelemetryConfiguration{
+				TracerProvider: &TracerProvider{
+					Sampler: &Sampler{ParentBased: &SamplerParentBased{
+						Root: &Sampler{TraceIDRatioBased: &SamplerTraceIDRatioBased{Ratio: ptr(1.5)}},
+						RemoteParentSampled: &Sampler{JaegerRemote: &SamplerJaegerRemote{
+							Interval:       ptr(0),
+							InitialSampler: &Sampler{AlwaysOn: SamplerAlwaysOn{}, AlwaysOff: SamplerAlwaysOff{}},
+						}},
+					}},
+					Processors: []SpanProcessor{
+						{Batch: &BatchSpanProcessor{Exporter: otlpExporter}},
+						{},
+						{Batch: &BatchSpanProcessor{MaxExportBatchSize: ptr(-1), Exporter: otlpExporter}, Simple: &SimpleSpanProcessor{}},
+						{Batch: &BatchSpanProcessor{
+							MaxQueueSize: ptr(-1),
+							Exporter: SpanExporter{OTLP: &OTLP{
+								Protocol:    "http/json",
+								Endpoint:    "::bad",
+								Compression: ptr("zstd"),
+								ClientKey:   ptr("key.pem"),
+							}},
+						}},
+						{Simple: &SimpleSpanProcessor{Exporter: SpanExporter{}}},
+						{Simple: &SimpleSpanProcessor{Exporter: SpanExporter{
+							Zipkin: &Zipkin{Endpoint: "http://localhost:9411/api/v2/spans", Timeout: ptr(-1)},
+						}}},
+						{AdditionalProperties: map[string]interface{}{"unknown": nil}},
+						{AdditionalProperties: map[string]interface{}{"first": nil, "second": nil}},
+					},
+				},
+			},
+			want: []*ValidationError{
+				{Path: "tracer_provider.sampler.parent_based.root.trace_id_ratio_based.ratio", Value: 1.5, Reason: "invalid value 1.5, must be in the range [0, 1]"},
+				{Path: "tracer_provider.sampler.parent_based.remote_parent_sampled.jaeger_remote.interval", Value: 0, Reason: "invalid value 0, must be positive"},
+				{Path: "tracer_provider.sampler.parent_based.remote_parent_sampled.jaeger_remote.initial_sampler", Reason: "must not specify multiple sampler types"},
+				{Path: "tracer_provider.processors[1]", Allowed: []string{"batch", "simple"}, Reason: "no span processor type specified"},
+				{Path: "tracer_provider.processors[2]", Reason: "must not specify multiple span processor type"},
+				{Path: "tracer_provider.processors[3].batch.max_queue_size", Value: -1, Reason: "invalid value -1, must not be negative"},
+				{Path: "tracer_provider.processors[3].batch.exporter.otlp.protocol", Value: "http/json", Allowed: []string{"grpc/protobuf", "http/protobuf"}, Reason: `unsupported value "http/json"`},
+				{Path: "tracer_provider.processors[3].batch.exporter.otlp.endpoint", Value: "::bad", Reason: `parse "::bad": missing protocol scheme`},
+				{Path: "tracer_provider.processors[3].batch.exporter.otlp.compression", Value: "zstd", Allowed: []string{"gzip", "none"}, Reason: `unsupported value "zstd"`},
+				{Path: "tracer_provider.processors[3].batch.exporter.otlp", Reason: "client_certificate and client_key must both be specified"},
+				{Path: "tracer_provider.processors[4].simple.exporter", Allowed: []string{"console", "otlp", "zipkin"}, Reason: "no exporter specified"},
+				{Path: "tracer_provider.processors[5].simple.exporter.zipkin.timeout", Value: -1, Reason: "invalid value -1, must not be negative"},
+				{Path: "tracer_provider.processors[6]", Value: "unknown", Allowed: []string{"batch", "simple"}, Reason: `unsupported type "unknown"`},
+				{Path: "tracer_provider.processors[7]", Reason: "must not specify multiple span processor type"},
+			},
+		},
+		{
+			name: "meter provider",
+			cfg: OpenTelemetryConfiguration{
+				MeterProvider: &MeterProvider{
+					Readers: []MetricReader{
+						{},
+						{Periodic: &PeriodicMetricReader{}, Pull: &PullMetricReader{}},
+						{Periodic: &PeriodicMetricReader{
+							Interval: ptr(-1),
+							Exporter: MetricExporter{OTLP: &OTLPMetric{
+								Protocol:                    protocolProtobufGRPC,
+								TemporalityPreference:       ptr("sometimes"),
+								DefaultHistogramAggregation: ptr(OTLPMetricDefaultHistogramAggregation("linear")),
+							}},
+						}},
+						{Periodic: &PeriodicMetricReader{Exporter: MetricExporter{Prometheus: &Prometheus{}}}},
+						{Pull: &PullMetricReader{Exporter: MetricExporter{Console: Console{}}}},
+						{Pull: &PullMetricReader{Exporter: MetricExporter{Prometheus: &Prometheus{Host: ptr("localhost")}}}},
+					},
+					Views: []View{{}},
+				},
+			},
+			want: []*ValidationError{
+				{Path: "meter_provider.readers[0]", Allowed: []string{"periodic", "pull"}, Reason: "no metric reader type specified"},
+				{Path: "meter_provider.readers[1]", Reason: "must not specify multiple metric reader type"},
+				{Path: "meter_provider.readers[2].periodic.interval", Value: -1, Reason: "invalid value -1, must not be negative"},
+				{Path: "meter_provider.readers[2].periodic.exporter.otlp.temporality_preference", Value: "sometimes", Allowed: []string{"cumulative", "delta", "lowmemory"}, Reason: `unsupported value "sometimes"`},
+				{
+					Path:    "meter_provider.readers[2].periodic.exporter.otlp.default_histogram_aggregation",
+					Value:   "linear",
+					Allowed: []string{"explicit_bucket_histogram", "base2_exponential_bucket_histogram"},
+					Reason:  `unsupported default histogram aggregation "linear"`,
+				},
+				{Path: "meter_provider.readers[3].periodic.exporter.prometheus", Reason: "prometheus exporter must be used with a pull metric reader"},
+				{Path: "meter_provider.readers[4].pull.exporter", Reason: "pull metric reader only supports the prometheus exporter"},
+				{Path: "meter_provider.readers[5].pull.exporter.prometheus.port", Reason: "port must be specified"},
+				{Path: "meter_provider.views[0]", Reason: "view: no selector provided"},
+			},
+		},
+		{
+			name: "logger provider",
+			cfg: OpenTelemetryConfiguration{
+				LoggerProvider: &LoggerProvider{
+					Processors: []LogRecordProcessor{
+						{},
+						{Batch: &BatchLogRecordProcessor{}, Simple: &SimpleLogRecordProcessor{}},
+						{Batch: &BatchLogRecordProcessor{ScheduleDelay: ptr(-1), Exporter: LogRecordExporter{Console: Console{}}}},
+						{Simple: &SimpleLogRecordProcessor{Exporter: LogRecordExporter{Console: Console{}, OTLP: &OTLP{}}}},
+						{Simple: &SimpleLogRecordProcessor{Exporter: LogRecordExporter{OTLP: &OTLP{Protocol: protocolProtobufHTTP, Timeout: ptr(-1)}}}},
+					},
+				},
+			},
+			want: []*ValidationError{
+				{Path: "logger_provider.processors[0]", Allowed: []string{"batch", "simple"}, Reason: "no log processor type specified"},
+				{Path: "logger_provider.processors[1]", Reason: "must not specify multiple log processor type"},
+				{Path: "logger_provider.processors[2].batch.schedule_delay", Value: -1, Reason: "invalid value -1, must not be negative"},
+				{Path: "logger_provider.processors[3].simple.exporter", Reason: "must not specify multiple exporters"},
+				{Path: "logger_provider.processors[4].simple.exporter.otlp.timeout", Value: -1, Reason: "invalid value -1, must not be negative"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.cfg)
+			if len(tt.want) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			var got []*ValidationError
+			for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+				var ve *ValidationError
+				require.ErrorAs(t, e, &ve)
+				got = append(got, ve)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	err := &ValidationError{
+		Path:    "tracer_provider.processors[2].batch.exporter.otlp.protocol",
+		Value:   "http/json",
+		Allowed: []string{"grpc/protobuf", "http/protobuf"},
+		Reason:  `unsupported value "http/json"`,
+	}
+	assert.EqualError(t, err, `tracer_provider.processors[2].batch.exporter.otlp.protocol: unsupported value "http/json", supported values: ["grpc/protobuf" "http/protobuf"]`)
+
+	_, sdkErr := NewSDK(WithOpenTelemetryConfiguration(OpenTelemetryConfiguration{
+		TracerProvider: &TracerProvider{
+			Processors: []SpanProcessor{{Batch: &BatchSpanProcessor{Exporter: SpanExporter{OTLP: &OTLP{Protocol: "http/json"}}}}},
+		},
+	}))
+	var ve *ValidationError
+	require.True(t, errors.As(sdkErr, &ve))
+	assert.Equal(t, "tracer_provider.processors[0].batch.exporter.otlp.protocol", ve.Path)
+}
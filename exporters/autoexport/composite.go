@@ -0,0 +1,88 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoexport // import "go.opentelemetry.io/contrib/exporters/autoexport"
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// compositeSpanExporter is an implementation of trace.SpanExporter that
+// exports to all of its exporters.
+type compositeSpanExporter []trace.SpanExporter
+
+var _ trace.SpanExporter = compositeSpanExporter{}
+
+func newCompositeSpanExporter(exporters []trace.SpanExporter) trace.SpanExporter {
+	return compositeSpanExporter(exporters)
+}
+
+// ExportSpans is part of trace.SpanExporter interface.
+func (e compositeSpanExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	errs := make([]error, 0, len(e))
+	for _, exp := range e {
+		errs = append(errs, exp.ExportSpans(ctx, spans))
+	}
+	return errors.Join(errs...)
+}
+
+// Shutdown is part of trace.SpanExporter interface.
+func (e compositeSpanExporter) Shutdown(ctx context.Context) error {
+	errs := make([]error, 0, len(e))
+	for _, exp := range e {
+		errs = append(errs, exp.Shutdown(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// compositeLogExporter is an implementation of log.Exporter that exports to
+// all of its exporters.
+type compositeLogExporter []log.Exporter
+
+var _ log.Exporter = compositeLogExporter{}
+
+func newCompositeLogExporter(exporters []log.Exporter) log.Exporter {
+	return compositeLogExporter(exporters)
+}
+
+// Export is part of log.Exporter interface.
+func (e compositeLogExporter) Export(ctx context.Context, records []log.Record) error {
+	errs := make([]error, 0, len(e))
+	for _, exp := range e {
+		errs = append(errs, exp.Export(ctx, records))
+	}
+	return errors.Join(errs...)
+}
+
+// Shutdown is part of log.Exporter interface.
+func (e compositeLogExporter) Shutdown(ctx context.Context) error {
+	errs := make([]error, 0, len(e))
+	for _, exp := range e {
+		errs = append(errs, exp.Shutdown(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// ForceFlush is part of log.Exporter interface.
+func (e compositeLogExporter) ForceFlush(ctx context.Context) error {
+	errs := make([]error, 0, len(e))
+	for _, exp := range e {
+		errs = append(errs, exp.ForceFlush(ctx))
+	}
+	return errors.Join(errs...)
+}
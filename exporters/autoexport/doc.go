@@ -15,4 +15,8 @@
 // Package autoexport provides OpenTelemetry exporter factory functions
 // with defaults and environment variable support as defined by the
 // OpenTelemetry specification.
+//
+// The OTLP exporters read the other OTEL_EXPORTER_OTLP_* environment
+// variables themselves, such as the signal-specific endpoints, headers and
+// TLS certificates.
 package autoexport // import "go.opentelemetry.io/contrib/exporters/autoexport"
@@ -0,0 +1,352 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoexport // import "go.opentelemetry.io/contrib/exporters/autoexport"
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"go.opentelemetry.io/contrib/exporters/autoexport/internal/transform"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	logpb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// Environment variables of the "otlp/file" exporters.
+const (
+	otelExporterOTLPTracesFilePathEnvKey  = "OTEL_EXPORTER_OTLP_TRACES_FILE_PATH"
+	otelExporterOTLPMetricsFilePathEnvKey = "OTEL_EXPORTER_OTLP_METRICS_FILE_PATH"
+	otelExporterOTLPLogsFilePathEnvKey    = "OTEL_EXPORTER_OTLP_LOGS_FILE_PATH"
+	otelExporterOTLPFileMaxSizeEnvKey     = "OTEL_EXPORTER_OTLP_FILE_MAX_SIZE"
+	otelExporterOTLPFileCompressionEnvKey = "OTEL_EXPORTER_OTLP_FILE_COMPRESSION"
+)
+
+// errFileExporterClosed is returned when writing to a closed "otlp/file"
+// exporter.
+var errFileExporterClosed = errors.New("otlp/file exporter is shut down")
+
+// fileWriter writes OTLP JSON lines to a file that is rotated when it
+// reaches its maximum size. It is safe for concurrent use.
+type fileWriter struct {
+	path    string
+	maxSize int64
+	gzip    bool
+
+	mu     sync.Mutex
+	file   *os.File
+	gz     *gzip.Writer
+	size   int64
+	next   int
+	closed bool
+}
+
+// newFileWriter returns a fileWriter for the file at the path defined by the
+// environment variable pathEnvKey, configured with the
+// OTEL_EXPORTER_OTLP_FILE_MAX_SIZE and OTEL_EXPORTER_OTLP_FILE_COMPRESSION
+// environment variables.
+func newFileWriter(pathEnvKey string) (*fileWriter, error) {
+	path := os.Getenv(pathEnvKey)
+	if path == "" {
+		return nil, fmt.Errorf("%s must be set for the otlp/file exporter", pathEnvKey)
+	}
+
+	w := &fileWriter{path: path}
+	if v := os.Getenv(otelExporterOTLPFileMaxSizeEnvKey); v != "" {
+		size, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || size < 0 {
+			return nil, fmt.Errorf("invalid %s value %q: must be a non-negative number of bytes", otelExporterOTLPFileMaxSizeEnvKey, v)
+		}
+		w.maxSize = size
+	}
+	switch v := os.Getenv(otelExporterOTLPFileCompressionEnvKey); v {
+	case "", "none":
+	case "gzip":
+		w.gzip = true
+	default:
+		return nil, fmt.Errorf("unsupported %s value %q, should be one of ['gzip', 'none']", otelExporterOTLPFileCompressionEnvKey, v)
+	}
+
+	w.next = nextRotation(path)
+	if w.gzip {
+		// The last gzip member of an existing file is not terminated if the
+		// process exited without shutting down the exporter, a new member
+		// appended to it could not be decompressed. Rotate the file instead.
+		if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+			if err := os.Rename(path, fmt.Sprintf("%s.%d", path, w.next)); err != nil {
+				return nil, err
+			}
+			w.next++
+		}
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// nextRotation returns the suffix of the next rotated file of path, one more
+// than the largest suffix of the existing rotated files.
+func nextRotation(path string) int {
+	matches, _ := filepath.Glob(path + ".*")
+	next := 1
+	for _, m := range matches {
+		n, err := strconv.Atoi(strings.TrimPrefix(m, path+"."))
+		if err == nil && n >= next {
+			next = n + 1
+		}
+	}
+	return next
+}
+
+func (w *fileWriter) open() error {
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return errors.Join(err, f.Close())
+	}
+	w.file, w.size = f, info.Size()
+	if w.gzip {
+		// The file is either new or terminated by a complete gzip member
+		// if its rotation failed, a new member appended to it keeps it
+		// valid.
+		w.gz = gzip.NewWriter(countingWriter{f, &w.size})
+	}
+	return nil
+}
+
+// write marshals m as a JSON line and writes it to the file.
+func (w *fileWriter) write(m proto.Message) error {
+	b, err := protojson.Marshal(m)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return errFileExporterClosed
+	}
+	if w.gz != nil {
+		if _, err := w.gz.Write(b); err != nil {
+			return err
+		}
+		// Flush every line so the file can be replayed if the process
+		// exits without shutting down the exporter.
+		if err := w.gz.Flush(); err != nil {
+			return err
+		}
+	} else {
+		n, err := w.file.Write(b)
+		w.size += int64(n)
+		if err != nil {
+			return err
+		}
+	}
+
+	if w.maxSize > 0 && w.size >= w.maxSize {
+		// The line is written, a failed rotation does not fail the export.
+		if err := w.rotate(); err != nil {
+			otel.Handle(err)
+		}
+	}
+	return nil
+}
+
+// rotate renames the current file to its next rotated name and opens a new
+// file. The caller must hold w.mu.
+func (w *fileWriter) rotate() error {
+	if err := w.closeFile(); err != nil {
+		return err
+	}
+	if err := os.Rename(w.path, fmt.Sprintf("%s.%d", w.path, w.next)); err != nil {
+		// Keep appending to the current file.
+		return errors.Join(err, w.open())
+	}
+	w.next++
+	return w.open()
+}
+
+func (w *fileWriter) closeFile() error {
+	var err error
+	if w.gz != nil {
+		err = w.gz.Close()
+	}
+	return errors.Join(err, w.file.Close())
+}
+
+func (w *fileWriter) close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.closeFile()
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n *int64
+}
+
+func (c countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	*c.n += int64(n)
+	return n, err
+}
+
+// fileTraceClient is an otlptrace.Client writing the traces to a file.
+type fileTraceClient struct {
+	w *fileWriter
+}
+
+var _ otlptrace.Client = fileTraceClient{}
+
+func newFileSpanExporter(ctx context.Context) (*otlptrace.Exporter, error) {
+	w, err := newFileWriter(otelExporterOTLPTracesFilePathEnvKey)
+	if err != nil {
+		return nil, err
+	}
+	return otlptrace.New(ctx, fileTraceClient{w})
+}
+
+// Start is part of otlptrace.Client interface.
+func (c fileTraceClient) Start(context.Context) error {
+	return nil
+}
+
+// Stop is part of otlptrace.Client interface.
+func (c fileTraceClient) Stop(context.Context) error {
+	return c.w.close()
+}
+
+// UploadTraces is part of otlptrace.Client interface.
+func (c fileTraceClient) UploadTraces(_ context.Context, protoSpans []*tracepb.ResourceSpans) error {
+	return c.w.write(&tracepb.TracesData{ResourceSpans: protoSpans})
+}
+
+// fileMetricExporter is a metric.Exporter writing the metrics to a file.
+type fileMetricExporter struct {
+	w           *fileWriter
+	temporality metric.TemporalitySelector
+	aggregation metric.AggregationSelector
+}
+
+var _ metric.Exporter = (*fileMetricExporter)(nil)
+
+func newFileMetricExporter() (*fileMetricExporter, error) {
+	w, err := newFileWriter(otelExporterOTLPMetricsFilePathEnvKey)
+	if err != nil {
+		return nil, err
+	}
+	e := &fileMetricExporter{
+		w:           w,
+		temporality: otlpMetricsTemporalitySelector(),
+		aggregation: otlpMetricsAggregationSelector(),
+	}
+	if e.temporality == nil {
+		e.temporality = metric.DefaultTemporalitySelector
+	}
+	if e.aggregation == nil {
+		e.aggregation = metric.DefaultAggregationSelector
+	}
+	return e, nil
+}
+
+// Temporality is part of metric.Exporter interface.
+func (e *fileMetricExporter) Temporality(k metric.InstrumentKind) metricdata.Temporality {
+	return e.temporality(k)
+}
+
+// Aggregation is part of metric.Exporter interface.
+func (e *fileMetricExporter) Aggregation(k metric.InstrumentKind) metric.Aggregation {
+	return e.aggregation(k)
+}
+
+// Export is part of metric.Exporter interface.
+func (e *fileMetricExporter) Export(_ context.Context, rm *metricdata.ResourceMetrics) error {
+	if len(rm.ScopeMetrics) == 0 {
+		return nil
+	}
+	prm, err := transform.ResourceMetrics(rm)
+	if err != nil {
+		return err
+	}
+	return e.w.write(&metricpb.MetricsData{ResourceMetrics: []*metricpb.ResourceMetrics{prm}})
+}
+
+// ForceFlush is part of metric.Exporter interface.
+func (e *fileMetricExporter) ForceFlush(context.Context) error {
+	return nil
+}
+
+// Shutdown is part of metric.Exporter interface.
+func (e *fileMetricExporter) Shutdown(context.Context) error {
+	return e.w.close()
+}
+
+// fileLogExporter is a log.Exporter writing the log records to a file.
+type fileLogExporter struct {
+	w *fileWriter
+}
+
+var _ log.Exporter = fileLogExporter{}
+
+func newFileLogExporter() (fileLogExporter, error) {
+	w, err := newFileWriter(otelExporterOTLPLogsFilePathEnvKey)
+	if err != nil {
+		return fileLogExporter{}, err
+	}
+	return fileLogExporter{w}, nil
+}
+
+// Export is part of log.Exporter interface.
+func (e fileLogExporter) Export(_ context.Context, records []log.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return e.w.write(&logpb.LogsData{ResourceLogs: transform.ResourceLogs(records)})
+}
+
+// ForceFlush is part of log.Exporter interface.
+func (e fileLogExporter) ForceFlush(context.Context) error {
+	return nil
+}
+
+// Shutdown is part of log.Exporter interface.
+func (e fileLogExporter) Shutdown(context.Context) error {
+	return e.w.close()
+}
@@ -0,0 +1,319 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoexport // import "go.opentelemetry.io/contrib/exporters/autoexport"
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	logpb "go.opentelemetry.io/proto/otlp/logs/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpanExporterOTLPFile(t *testing.T) {
+	for _, compression := range []string{"", "none", "gzip"} {
+		t.Run(fmt.Sprintf("compression=%q", compression), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "traces.jsonl")
+			t.Setenv("OTEL_TRACES_EXPORTER", "otlp/file")
+			t.Setenv("OTEL_EXPORTER_OTLP_TRACES_FILE_PATH", path)
+			t.Setenv("OTEL_EXPORTER_OTLP_FILE_COMPRESSION", compression)
+
+			exp, err := NewSpanExporter(context.Background())
+			require.NoError(t, err)
+			assert.IsType(t, &otlptrace.Exporter{}, exp)
+
+			res := resource.NewSchemaless(attribute.String("service.name", "test"))
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp), sdktrace.WithResource(res))
+			tracer := tp.Tracer("scope", trace.WithInstrumentationVersion("v0.1.0"))
+			ctx, parent := tracer.Start(context.Background(), "parent", trace.WithSpanKind(trace.SpanKindServer))
+			_, child := tracer.Start(ctx, "child", trace.WithAttributes(attribute.Int("n", 1), attribute.StringSlice("s", []string{"a", "b"})))
+			child.AddEvent("event", trace.WithAttributes(attribute.Bool("b", true)))
+			child.End()
+			parent.End()
+			require.NoError(t, tp.Shutdown(context.Background()))
+
+			got := tracetest.NewInMemoryExporter()
+			require.NoError(t, ReplaySpans(context.Background(), path, got))
+			spans := got.GetSpans()
+			require.Len(t, spans, 2)
+
+			assert.Equal(t, "child", spans[0].Name)
+			assert.Equal(t, child.SpanContext().TraceID(), spans[0].SpanContext.TraceID())
+			assert.Equal(t, child.SpanContext().SpanID(), spans[0].SpanContext.SpanID())
+			assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent.SpanID())
+			assert.Equal(t, []attribute.KeyValue{attribute.Int("n", 1), attribute.StringSlice("s", []string{"a", "b"})}, spans[0].Attributes)
+			require.Len(t, spans[0].Events, 1)
+			assert.Equal(t, "event", spans[0].Events[0].Name)
+			assert.Equal(t, "scope", spans[0].InstrumentationScope.Name)
+			assert.Equal(t, "v0.1.0", spans[0].InstrumentationScope.Version)
+			assert.Equal(t, res.Equivalent(), spans[0].Resource.Equivalent())
+
+			assert.Equal(t, "parent", spans[1].Name)
+			assert.Equal(t, trace.SpanKindServer, spans[1].SpanKind)
+			assert.False(t, spans[1].Parent.IsValid())
+		})
+	}
+}
+
+func TestSpanExporterOTLPFileRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	t.Setenv("OTEL_TRACES_EXPORTER", "otlp/file")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_FILE_PATH", path)
+	t.Setenv("OTEL_EXPORTER_OTLP_FILE_MAX_SIZE", "1")
+
+	exp, err := NewSpanExporter(context.Background())
+	require.NoError(t, err)
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	for _, name := range []string{"a", "b", "c"} {
+		_, span := tp.Tracer("scope").Start(context.Background(), name)
+		span.End()
+	}
+	require.NoError(t, tp.Shutdown(context.Background()))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Zero(t, info.Size())
+
+	got := tracetest.NewInMemoryExporter()
+	for _, p := range []string{path + ".1", path + ".2", path + ".3", path} {
+		require.NoError(t, ReplaySpans(context.Background(), p, got))
+	}
+	var names []string
+	for _, s := range got.GetSpans() {
+		names = append(names, s.Name)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, names)
+
+	// A new exporter continues the numbering of the rotated files.
+	exp, err = NewSpanExporter(context.Background())
+	require.NoError(t, err)
+	tp = sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	_, span := tp.Tracer("scope").Start(context.Background(), "d")
+	span.End()
+	require.NoError(t, tp.Shutdown(context.Background()))
+	assert.FileExists(t, path+".4")
+}
+
+func TestSpanExporterOTLPFileGzipRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl.gz")
+	t.Setenv("OTEL_TRACES_EXPORTER", "otlp/file")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_FILE_PATH", path)
+	t.Setenv("OTEL_EXPORTER_OTLP_FILE_COMPRESSION", "gzip")
+
+	// The first exporter is not shut down, as if the process crashed.
+	exp, err := NewSpanExporter(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = exp.Shutdown(context.Background()) })
+	_, span := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp)).Tracer("scope").Start(context.Background(), "a")
+	span.End()
+
+	exp, err = NewSpanExporter(context.Background())
+	require.NoError(t, err)
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	_, span = tp.Tracer("scope").Start(context.Background(), "b")
+	span.End()
+	require.NoError(t, tp.Shutdown(context.Background()))
+
+	for p, want := range map[string]string{path + ".1": "a", path: "b"} {
+		got := tracetest.NewInMemoryExporter()
+		require.NoError(t, ReplaySpans(context.Background(), p, got))
+		spans := got.GetSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, want, spans[0].Name)
+	}
+}
+
+func TestFileWriterRotationError(t *testing.T) {
+	handled := recordOtelHandleErrors(t)
+
+	path := filepath.Join(t.TempDir(), "logs.jsonl")
+	t.Setenv("OTEL_EXPORTER_OTLP_LOGS_FILE_PATH", path)
+	t.Setenv("OTEL_EXPORTER_OTLP_FILE_MAX_SIZE", "1")
+
+	w, err := newFileWriter("OTEL_EXPORTER_OTLP_LOGS_FILE_PATH")
+	require.NoError(t, err)
+	// The file cannot be renamed to a directory.
+	require.NoError(t, os.MkdirAll(filepath.Join(path+".1", "dir"), 0o700))
+
+	require.NoError(t, w.write(&logpb.ResourceLogs{}))
+	assert.Len(t, *handled, 1)
+	require.NoError(t, w.write(&logpb.ResourceLogs{}))
+	require.NoError(t, w.close())
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "{}\n{}\n", string(b))
+}
+
+func TestSpanExporterOTLPFileInvalid(t *testing.T) {
+	t.Setenv("OTEL_TRACES_EXPORTER", "otlp/file")
+
+	_, err := NewSpanExporter(context.Background())
+	assert.ErrorContains(t, err, "OTEL_EXPORTER_OTLP_TRACES_FILE_PATH must be set")
+
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_FILE_PATH", filepath.Join(t.TempDir(), "traces.jsonl"))
+	for _, tc := range []struct {
+		key, value, err string
+	}{
+		{"OTEL_EXPORTER_OTLP_FILE_MAX_SIZE", "1MB", `invalid OTEL_EXPORTER_OTLP_FILE_MAX_SIZE value "1MB"`},
+		{"OTEL_EXPORTER_OTLP_FILE_MAX_SIZE", "-1", `invalid OTEL_EXPORTER_OTLP_FILE_MAX_SIZE value "-1"`},
+		{"OTEL_EXPORTER_OTLP_FILE_COMPRESSION", "zstd", `unsupported OTEL_EXPORTER_OTLP_FILE_COMPRESSION value "zstd"`},
+	} {
+		t.Run(tc.key+"="+tc.value, func(t *testing.T) {
+			t.Setenv(tc.key, tc.value)
+			_, err := NewSpanExporter(context.Background())
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestReplaySpansInvalidLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{}\n\nnot json\n"), 0o600))
+
+	err := ReplaySpans(context.Background(), path, tracetest.NewInMemoryExporter())
+	assert.ErrorContains(t, err, path+":3: ")
+}
+
+// recordingMetricExporter records the exported metrics.
+type recordingMetricExporter struct {
+	metric.Exporter
+	got []*metricdata.ResourceMetrics
+}
+
+func (e *recordingMetricExporter) Export(_ context.Context, rm *metricdata.ResourceMetrics) error {
+	e.got = append(e.got, rm)
+	return nil
+}
+
+func TestMetricReaderOTLPFile(t *testing.T) {
+	recordOtelHandleErrors(t)
+
+	path := filepath.Join(t.TempDir(), "metrics.jsonl")
+	t.Setenv("OTEL_METRICS_EXPORTER", "otlp/file")
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_FILE_PATH", path)
+	t.Setenv("OTEL_EXPORTER_OTLP_FILE_COMPRESSION", "gzip")
+
+	r, err := NewMetricReader(context.Background())
+	require.NoError(t, err)
+	assert.IsType(t, &metric.PeriodicReader{}, r)
+
+	res := resource.NewSchemaless(attribute.String("service.name", "test"))
+	mp := metric.NewMeterProvider(metric.WithReader(r), metric.WithResource(res))
+	meter := mp.Meter("scope")
+	counter, err := meter.Int64Counter("counter")
+	require.NoError(t, err)
+	counter.Add(context.Background(), 3)
+	histogram, err := meter.Float64Histogram("histogram")
+	require.NoError(t, err)
+	histogram.Record(context.Background(), 1.5)
+	require.NoError(t, mp.Shutdown(context.Background()))
+
+	got := &recordingMetricExporter{}
+	require.NoError(t, ReplayMetrics(context.Background(), path, got))
+	require.Len(t, got.got, 1)
+
+	want := metricdata.ResourceMetrics{
+		Resource: res,
+		ScopeMetrics: []metricdata.ScopeMetrics{{
+			Scope: got.got[0].ScopeMetrics[0].Scope,
+			Metrics: []metricdata.Metrics{
+				{
+					Name: "counter",
+					Data: metricdata.Sum[int64]{
+						Temporality: metricdata.CumulativeTemporality,
+						IsMonotonic: true,
+						DataPoints:  []metricdata.DataPoint[int64]{{Value: 3}},
+					},
+				},
+				{
+					Name: "histogram",
+					Data: metricdata.Histogram[float64]{
+						Temporality: metricdata.CumulativeTemporality,
+						DataPoints: []metricdata.HistogramDataPoint[float64]{{
+							Count:        1,
+							Sum:          1.5,
+							Min:          metricdata.NewExtrema(1.5),
+							Max:          metricdata.NewExtrema(1.5),
+							Bounds:       []float64{0, 5, 10, 25, 50, 75, 100, 250, 500, 750, 1000, 2500, 5000, 7500, 10000},
+							BucketCounts: []uint64{0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+						}},
+					},
+				},
+			},
+		}},
+	}
+	assert.Equal(t, "scope", got.got[0].ScopeMetrics[0].Scope.Name)
+	metricdatatest.AssertEqual(t, want, *got.got[0], metricdatatest.IgnoreTimestamp())
+}
+
+func TestLogExporterOTLPFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.jsonl")
+	t.Setenv("OTEL_LOGS_EXPORTER", "otlp/file")
+	t.Setenv("OTEL_EXPORTER_OTLP_LOGS_FILE_PATH", path)
+
+	exp, err := NewLogExporter(context.Background())
+	require.NoError(t, err)
+
+	lp := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exp)))
+	var record log.Record
+	record.SetTimestamp(time.Unix(0, 42))
+	record.SetSeverity(log.SeverityWarn)
+	record.SetBody(log.StringValue("hello"))
+	record.AddAttributes(log.Int("n", 1))
+	lp.Logger("scope").Emit(context.Background(), record)
+	require.NoError(t, lp.Shutdown(context.Background()))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, f.Close()) })
+	scanner := bufio.NewScanner(f)
+	require.True(t, scanner.Scan())
+	var data logpb.LogsData
+	require.NoError(t, protojson.Unmarshal(scanner.Bytes(), &data))
+	assert.False(t, scanner.Scan(), "unexpected line")
+
+	require.Len(t, data.ResourceLogs, 1)
+	require.Len(t, data.ResourceLogs[0].ScopeLogs, 1)
+	assert.Equal(t, "scope", data.ResourceLogs[0].ScopeLogs[0].Scope.GetName())
+	require.Len(t, data.ResourceLogs[0].ScopeLogs[0].LogRecords, 1)
+	lr := data.ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+	assert.Equal(t, uint64(42), lr.GetTimeUnixNano())
+	assert.Equal(t, logpb.SeverityNumber_SEVERITY_NUMBER_WARN, lr.GetSeverityNumber())
+	assert.Equal(t, "hello", lr.GetBody().GetStringValue())
+	require.Len(t, lr.GetAttributes(), 1)
+	assert.Equal(t, "n", lr.GetAttributes()[0].GetKey())
+	assert.Equal(t, int64(1), lr.GetAttributes()[0].GetValue().GetIntValue())
+}
@@ -0,0 +1,186 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package transform converts the telemetry of the OpenTelemetry SDK to and
+// from the OTLP protobuf messages.
+package transform // import "go.opentelemetry.io/contrib/exporters/autoexport/internal/transform"
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+// KeyValues returns the OTLP representation of attrs.
+func KeyValues(attrs []attribute.KeyValue) []*commonpb.KeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := make([]*commonpb.KeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		out = append(out, &commonpb.KeyValue{Key: string(kv.Key), Value: value(kv.Value)})
+	}
+	return out
+}
+
+func value(v attribute.Value) *commonpb.AnyValue {
+	av := new(commonpb.AnyValue)
+	switch v.Type() {
+	case attribute.BOOL:
+		av.Value = &commonpb.AnyValue_BoolValue{BoolValue: v.AsBool()}
+	case attribute.INT64:
+		av.Value = &commonpb.AnyValue_IntValue{IntValue: v.AsInt64()}
+	case attribute.FLOAT64:
+		av.Value = &commonpb.AnyValue_DoubleValue{DoubleValue: v.AsFloat64()}
+	case attribute.STRING:
+		av.Value = &commonpb.AnyValue_StringValue{StringValue: v.AsString()}
+	case attribute.BOOLSLICE:
+		av.Value = arrayValue(v.AsBoolSlice(), func(b bool) *commonpb.AnyValue {
+			return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: b}}
+		})
+	case attribute.INT64SLICE:
+		av.Value = arrayValue(v.AsInt64Slice(), func(i int64) *commonpb.AnyValue {
+			return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: i}}
+		})
+	case attribute.FLOAT64SLICE:
+		av.Value = arrayValue(v.AsFloat64Slice(), func(f float64) *commonpb.AnyValue {
+			return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: f}}
+		})
+	case attribute.STRINGSLICE:
+		av.Value = arrayValue(v.AsStringSlice(), func(s string) *commonpb.AnyValue {
+			return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: s}}
+		})
+	default:
+		av.Value = &commonpb.AnyValue_StringValue{StringValue: "INVALID"}
+	}
+	return av
+}
+
+func arrayValue[T any](values []T, f func(T) *commonpb.AnyValue) *commonpb.AnyValue_ArrayValue {
+	array := &commonpb.ArrayValue{Values: make([]*commonpb.AnyValue, 0, len(values))}
+	for _, v := range values {
+		array.Values = append(array.Values, f(v))
+	}
+	return &commonpb.AnyValue_ArrayValue{ArrayValue: array}
+}
+
+// Attributes returns the attributes of the OTLP kvs. Values that cannot be
+// represented as an attribute, such as maps, bytes and heterogeneous arrays,
+// are dropped.
+func Attributes(kvs []*commonpb.KeyValue) []attribute.KeyValue {
+	if len(kvs) == 0 {
+		return nil
+	}
+	out := make([]attribute.KeyValue, 0, len(kvs))
+	for _, kv := range kvs {
+		if v, ok := attributeValue(kv.GetValue()); ok {
+			out = append(out, attribute.KeyValue{Key: attribute.Key(kv.GetKey()), Value: v})
+		}
+	}
+	return out
+}
+
+func attributeValue(av *commonpb.AnyValue) (attribute.Value, bool) {
+	switch v := av.GetValue().(type) {
+	case *commonpb.AnyValue_BoolValue:
+		return attribute.BoolValue(v.BoolValue), true
+	case *commonpb.AnyValue_IntValue:
+		return attribute.Int64Value(v.IntValue), true
+	case *commonpb.AnyValue_DoubleValue:
+		return attribute.Float64Value(v.DoubleValue), true
+	case *commonpb.AnyValue_StringValue:
+		return attribute.StringValue(v.StringValue), true
+	case *commonpb.AnyValue_ArrayValue:
+		return arrayAttributeValue(v.ArrayValue.GetValues())
+	default:
+		return attribute.Value{}, false
+	}
+}
+
+func arrayAttributeValue(values []*commonpb.AnyValue) (attribute.Value, bool) {
+	if len(values) == 0 {
+		return attribute.Value{}, false
+	}
+	switch values[0].GetValue().(type) {
+	case *commonpb.AnyValue_BoolValue:
+		return sliceValue(values, attribute.BoolSliceValue, func(av *commonpb.AnyValue) (bool, bool) {
+			_, ok := av.GetValue().(*commonpb.AnyValue_BoolValue)
+			return av.GetBoolValue(), ok
+		})
+	case *commonpb.AnyValue_IntValue:
+		return sliceValue(values, attribute.Int64SliceValue, func(av *commonpb.AnyValue) (int64, bool) {
+			_, ok := av.GetValue().(*commonpb.AnyValue_IntValue)
+			return av.GetIntValue(), ok
+		})
+	case *commonpb.AnyValue_DoubleValue:
+		return sliceValue(values, attribute.Float64SliceValue, func(av *commonpb.AnyValue) (float64, bool) {
+			_, ok := av.GetValue().(*commonpb.AnyValue_DoubleValue)
+			return av.GetDoubleValue(), ok
+		})
+	case *commonpb.AnyValue_StringValue:
+		return sliceValue(values, attribute.StringSliceValue, func(av *commonpb.AnyValue) (string, bool) {
+			_, ok := av.GetValue().(*commonpb.AnyValue_StringValue)
+			return av.GetStringValue(), ok
+		})
+	default:
+		return attribute.Value{}, false
+	}
+}
+
+func sliceValue[T any](values []*commonpb.AnyValue, newValue func([]T) attribute.Value, get func(*commonpb.AnyValue) (T, bool)) (attribute.Value, bool) {
+	out := make([]T, 0, len(values))
+	for _, av := range values {
+		v, ok := get(av)
+		if !ok {
+			return attribute.Value{}, false
+		}
+		out = append(out, v)
+	}
+	return newValue(out), true
+}
+
+// Resource returns the OTLP representation of r.
+func Resource(r *resource.Resource) *resourcepb.Resource {
+	if r == nil {
+		return nil
+	}
+	return &resourcepb.Resource{Attributes: KeyValues(r.Attributes())}
+}
+
+// SDKResource returns the resource of the OTLP r with the schemaURL.
+func SDKResource(r *resourcepb.Resource, schemaURL string) *resource.Resource {
+	return resource.NewWithAttributes(schemaURL, Attributes(r.GetAttributes())...)
+}
+
+// InstrumentationScope returns the OTLP representation of s.
+func InstrumentationScope(s instrumentation.Scope) *commonpb.InstrumentationScope {
+	return &commonpb.InstrumentationScope{
+		Name:       s.Name,
+		Version:    s.Version,
+		Attributes: KeyValues(s.Attributes.ToSlice()),
+	}
+}
+
+// SDKInstrumentationScope returns the instrumentation scope of the OTLP s
+// with the schemaURL.
+func SDKInstrumentationScope(s *commonpb.InstrumentationScope, schemaURL string) instrumentation.Scope {
+	return instrumentation.Scope{
+		Name:       s.GetName(),
+		Version:    s.GetVersion(),
+		SchemaURL:  schemaURL,
+		Attributes: attribute.NewSet(Attributes(s.GetAttributes())...),
+	}
+}
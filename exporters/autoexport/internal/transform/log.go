@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform // import "go.opentelemetry.io/contrib/exporters/autoexport/internal/transform"
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logpb "go.opentelemetry.io/proto/otlp/logs/v1"
+)
+
+type scopeKey struct {
+	name      string
+	version   string
+	schemaURL string
+	attrs     attribute.Distinct
+}
+
+// ResourceLogs returns the OTLP representation of records, grouped by
+// resource and instrumentation scope.
+func ResourceLogs(records []sdklog.Record) []*logpb.ResourceLogs {
+	var out []*logpb.ResourceLogs
+	resources := make(map[attribute.Distinct]*logpb.ResourceLogs)
+	scopes := make(map[attribute.Distinct]map[scopeKey]*logpb.ScopeLogs)
+	for i := range records {
+		r := &records[i]
+
+		res := r.Resource()
+		resKey := res.Equivalent()
+		rl, ok := resources[resKey]
+		if !ok {
+			rl = &logpb.ResourceLogs{Resource: Resource(&res), SchemaUrl: res.SchemaURL()}
+			resources[resKey] = rl
+			scopes[resKey] = make(map[scopeKey]*logpb.ScopeLogs)
+			out = append(out, rl)
+		}
+
+		scope := r.InstrumentationScope()
+		key := scopeKey{
+			name:      scope.Name,
+			version:   scope.Version,
+			schemaURL: scope.SchemaURL,
+			attrs:     scope.Attributes.Equivalent(),
+		}
+		sl, ok := scopes[resKey][key]
+		if !ok {
+			sl = &logpb.ScopeLogs{Scope: InstrumentationScope(scope), SchemaUrl: scope.SchemaURL}
+			scopes[resKey][key] = sl
+			rl.ScopeLogs = append(rl.ScopeLogs, sl)
+		}
+		sl.LogRecords = append(sl.LogRecords, logRecord(r))
+	}
+	return out
+}
+
+func logRecord(r *sdklog.Record) *logpb.LogRecord {
+	out := &logpb.LogRecord{
+		TimeUnixNano:           unixNano(r.Timestamp()),
+		ObservedTimeUnixNano:   unixNano(r.ObservedTimestamp()),
+		SeverityNumber:         logpb.SeverityNumber(r.Severity()), // nolint:gosec // Severities are in the range of the OTLP severities.
+		SeverityText:           r.SeverityText(),
+		DroppedAttributesCount: clampUint32(r.DroppedAttributes()),
+		Flags:                  uint32(r.TraceFlags()),
+	}
+	if body := r.Body(); !body.Empty() {
+		out.Body = logValue(body)
+	}
+	if r.AttributesLen() > 0 {
+		out.Attributes = make([]*commonpb.KeyValue, 0, r.AttributesLen())
+		r.WalkAttributes(func(kv log.KeyValue) bool {
+			out.Attributes = append(out.Attributes, &commonpb.KeyValue{Key: kv.Key, Value: logValue(kv.Value)})
+			return true
+		})
+	}
+	if tid := r.TraceID(); tid.IsValid() {
+		out.TraceId = tid[:]
+	}
+	if sid := r.SpanID(); sid.IsValid() {
+		out.SpanId = sid[:]
+	}
+	return out
+}
+
+func logValue(v log.Value) *commonpb.AnyValue {
+	av := new(commonpb.AnyValue)
+	switch v.Kind() {
+	case log.KindBool:
+		av.Value = &commonpb.AnyValue_BoolValue{BoolValue: v.AsBool()}
+	case log.KindInt64:
+		av.Value = &commonpb.AnyValue_IntValue{IntValue: v.AsInt64()}
+	case log.KindFloat64:
+		av.Value = &commonpb.AnyValue_DoubleValue{DoubleValue: v.AsFloat64()}
+	case log.KindString:
+		av.Value = &commonpb.AnyValue_StringValue{StringValue: v.AsString()}
+	case log.KindBytes:
+		av.Value = &commonpb.AnyValue_BytesValue{BytesValue: v.AsBytes()}
+	case log.KindSlice:
+		array := &commonpb.ArrayValue{}
+		for _, e := range v.AsSlice() {
+			array.Values = append(array.Values, logValue(e))
+		}
+		av.Value = &commonpb.AnyValue_ArrayValue{ArrayValue: array}
+	case log.KindMap:
+		kvList := &commonpb.KeyValueList{}
+		for _, kv := range v.AsMap() {
+			kvList.Values = append(kvList.Values, &commonpb.KeyValue{Key: kv.Key, Value: logValue(kv.Value)})
+		}
+		av.Value = &commonpb.AnyValue_KvlistValue{KvlistValue: kvList}
+	}
+	return av
+}
@@ -0,0 +1,454 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform // import "go.opentelemetry.io/contrib/exporters/autoexport/internal/transform"
+
+import (
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+)
+
+// ResourceMetrics returns the OTLP representation of rm.
+func ResourceMetrics(rm *metricdata.ResourceMetrics) (*metricpb.ResourceMetrics, error) {
+	out := &metricpb.ResourceMetrics{
+		Resource:     Resource(rm.Resource),
+		ScopeMetrics: make([]*metricpb.ScopeMetrics, 0, len(rm.ScopeMetrics)),
+	}
+	if rm.Resource != nil {
+		out.SchemaUrl = rm.Resource.SchemaURL()
+	}
+	for _, sm := range rm.ScopeMetrics {
+		ms := make([]*metricpb.Metric, 0, len(sm.Metrics))
+		for _, m := range sm.Metrics {
+			pm, err := metric(m)
+			if err != nil {
+				return nil, err
+			}
+			ms = append(ms, pm)
+		}
+		out.ScopeMetrics = append(out.ScopeMetrics, &metricpb.ScopeMetrics{
+			Scope:     InstrumentationScope(sm.Scope),
+			Metrics:   ms,
+			SchemaUrl: sm.Scope.SchemaURL,
+		})
+	}
+	return out, nil
+}
+
+func metric(m metricdata.Metrics) (*metricpb.Metric, error) {
+	out := &metricpb.Metric{
+		Name:        m.Name,
+		Description: m.Description,
+		Unit:        m.Unit,
+	}
+	switch a := m.Data.(type) {
+	case metricdata.Gauge[int64]:
+		out.Data = &metricpb.Metric_Gauge{Gauge: &metricpb.Gauge{DataPoints: dataPoints(a.DataPoints)}}
+	case metricdata.Gauge[float64]:
+		out.Data = &metricpb.Metric_Gauge{Gauge: &metricpb.Gauge{DataPoints: dataPoints(a.DataPoints)}}
+	case metricdata.Sum[int64]:
+		out.Data = &metricpb.Metric_Sum{Sum: &metricpb.Sum{
+			AggregationTemporality: temporality(a.Temporality),
+			IsMonotonic:            a.IsMonotonic,
+			DataPoints:             dataPoints(a.DataPoints),
+		}}
+	case metricdata.Sum[float64]:
+		out.Data = &metricpb.Metric_Sum{Sum: &metricpb.Sum{
+			AggregationTemporality: temporality(a.Temporality),
+			IsMonotonic:            a.IsMonotonic,
+			DataPoints:             dataPoints(a.DataPoints),
+		}}
+	case metricdata.Histogram[int64]:
+		out.Data = &metricpb.Metric_Histogram{Histogram: &metricpb.Histogram{
+			AggregationTemporality: temporality(a.Temporality),
+			DataPoints:             histogramDataPoints(a.DataPoints),
+		}}
+	case metricdata.Histogram[float64]:
+		out.Data = &metricpb.Metric_Histogram{Histogram: &metricpb.Histogram{
+			AggregationTemporality: temporality(a.Temporality),
+			DataPoints:             histogramDataPoints(a.DataPoints),
+		}}
+	case metricdata.ExponentialHistogram[int64]:
+		out.Data = &metricpb.Metric_ExponentialHistogram{ExponentialHistogram: &metricpb.ExponentialHistogram{
+			AggregationTemporality: temporality(a.Temporality),
+			DataPoints:             exponentialHistogramDataPoints(a.DataPoints),
+		}}
+	case metricdata.ExponentialHistogram[float64]:
+		out.Data = &metricpb.Metric_ExponentialHistogram{ExponentialHistogram: &metricpb.ExponentialHistogram{
+			AggregationTemporality: temporality(a.Temporality),
+			DataPoints:             exponentialHistogramDataPoints(a.DataPoints),
+		}}
+	case metricdata.Summary:
+		out.Data = &metricpb.Metric_Summary{Summary: &metricpb.Summary{
+			DataPoints: summaryDataPoints(a.DataPoints),
+		}}
+	default:
+		return nil, fmt.Errorf("metric %q: unknown aggregation %T", m.Name, a)
+	}
+	return out, nil
+}
+
+func temporality(t metricdata.Temporality) metricpb.AggregationTemporality {
+	switch t {
+	case metricdata.DeltaTemporality:
+		return metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA
+	case metricdata.CumulativeTemporality:
+		return metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE
+	default:
+		return metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_UNSPECIFIED
+	}
+}
+
+func dataPoints[N int64 | float64](dps []metricdata.DataPoint[N]) []*metricpb.NumberDataPoint {
+	out := make([]*metricpb.NumberDataPoint, 0, len(dps))
+	for _, dp := range dps {
+		pdp := &metricpb.NumberDataPoint{
+			Attributes:        KeyValues(dp.Attributes.ToSlice()),
+			StartTimeUnixNano: unixNano(dp.StartTime),
+			TimeUnixNano:      unixNano(dp.Time),
+			Exemplars:         exemplars(dp.Exemplars),
+		}
+		switch v := any(dp.Value).(type) {
+		case int64:
+			pdp.Value = &metricpb.NumberDataPoint_AsInt{AsInt: v}
+		case float64:
+			pdp.Value = &metricpb.NumberDataPoint_AsDouble{AsDouble: v}
+		}
+		out = append(out, pdp)
+	}
+	return out
+}
+
+func histogramDataPoints[N int64 | float64](dps []metricdata.HistogramDataPoint[N]) []*metricpb.HistogramDataPoint {
+	out := make([]*metricpb.HistogramDataPoint, 0, len(dps))
+	for _, dp := range dps {
+		sum := float64(dp.Sum)
+		pdp := &metricpb.HistogramDataPoint{
+			Attributes:        KeyValues(dp.Attributes.ToSlice()),
+			StartTimeUnixNano: unixNano(dp.StartTime),
+			TimeUnixNano:      unixNano(dp.Time),
+			Count:             dp.Count,
+			Sum:               &sum,
+			BucketCounts:      dp.BucketCounts,
+			ExplicitBounds:    dp.Bounds,
+			Exemplars:         exemplars(dp.Exemplars),
+		}
+		if v, ok := dp.Min.Value(); ok {
+			vF64 := float64(v)
+			pdp.Min = &vF64
+		}
+		if v, ok := dp.Max.Value(); ok {
+			vF64 := float64(v)
+			pdp.Max = &vF64
+		}
+		out = append(out, pdp)
+	}
+	return out
+}
+
+func exponentialHistogramDataPoints[N int64 | float64](dps []metricdata.ExponentialHistogramDataPoint[N]) []*metricpb.ExponentialHistogramDataPoint {
+	out := make([]*metricpb.ExponentialHistogramDataPoint, 0, len(dps))
+	for _, dp := range dps {
+		sum := float64(dp.Sum)
+		pdp := &metricpb.ExponentialHistogramDataPoint{
+			Attributes:        KeyValues(dp.Attributes.ToSlice()),
+			StartTimeUnixNano: unixNano(dp.StartTime),
+			TimeUnixNano:      unixNano(dp.Time),
+			Count:             dp.Count,
+			Sum:               &sum,
+			Scale:             dp.Scale,
+			ZeroCount:         dp.ZeroCount,
+			ZeroThreshold:     dp.ZeroThreshold,
+			Positive: &metricpb.ExponentialHistogramDataPoint_Buckets{
+				Offset:       dp.PositiveBucket.Offset,
+				BucketCounts: dp.PositiveBucket.Counts,
+			},
+			Negative: &metricpb.ExponentialHistogramDataPoint_Buckets{
+				Offset:       dp.NegativeBucket.Offset,
+				BucketCounts: dp.NegativeBucket.Counts,
+			},
+			Exemplars: exemplars(dp.Exemplars),
+		}
+		if v, ok := dp.Min.Value(); ok {
+			vF64 := float64(v)
+			pdp.Min = &vF64
+		}
+		if v, ok := dp.Max.Value(); ok {
+			vF64 := float64(v)
+			pdp.Max = &vF64
+		}
+		out = append(out, pdp)
+	}
+	return out
+}
+
+func summaryDataPoints(dps []metricdata.SummaryDataPoint) []*metricpb.SummaryDataPoint {
+	out := make([]*metricpb.SummaryDataPoint, 0, len(dps))
+	for _, dp := range dps {
+		pdp := &metricpb.SummaryDataPoint{
+			Attributes:        KeyValues(dp.Attributes.ToSlice()),
+			StartTimeUnixNano: unixNano(dp.StartTime),
+			TimeUnixNano:      unixNano(dp.Time),
+			Count:             dp.Count,
+			Sum:               dp.Sum,
+		}
+		for _, q := range dp.QuantileValues {
+			pdp.QuantileValues = append(pdp.QuantileValues, &metricpb.SummaryDataPoint_ValueAtQuantile{
+				Quantile: q.Quantile,
+				Value:    q.Value,
+			})
+		}
+		out = append(out, pdp)
+	}
+	return out
+}
+
+func exemplars[N int64 | float64](exemplars []metricdata.Exemplar[N]) []*metricpb.Exemplar {
+	if len(exemplars) == 0 {
+		return nil
+	}
+	out := make([]*metricpb.Exemplar, 0, len(exemplars))
+	for _, e := range exemplars {
+		pe := &metricpb.Exemplar{
+			FilteredAttributes: KeyValues(e.FilteredAttributes),
+			TimeUnixNano:       unixNano(e.Time),
+			SpanId:             e.SpanID,
+			TraceId:            e.TraceID,
+		}
+		switch v := any(e.Value).(type) {
+		case int64:
+			pe.Value = &metricpb.Exemplar_AsInt{AsInt: v}
+		case float64:
+			pe.Value = &metricpb.Exemplar_AsDouble{AsDouble: v}
+		}
+		out = append(out, pe)
+	}
+	return out
+}
+
+func unixNano(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint64(max(0, t.UnixNano())) // nolint:gosec // Overflow checked.
+}
+
+// SDKResourceMetrics returns the metric data of the OTLP rm.
+//
+// The OTLP representation does not distinguish the integer and floating
+// point histograms, histograms are returned with float64 values. Gauges and
+// sums are returned with int64 values if all of their data points are
+// integers.
+func SDKResourceMetrics(rm *metricpb.ResourceMetrics) (*metricdata.ResourceMetrics, error) {
+	out := &metricdata.ResourceMetrics{
+		Resource:     SDKResource(rm.GetResource(), rm.GetSchemaUrl()),
+		ScopeMetrics: make([]metricdata.ScopeMetrics, 0, len(rm.GetScopeMetrics())),
+	}
+	for _, sm := range rm.GetScopeMetrics() {
+		ms := make([]metricdata.Metrics, 0, len(sm.GetMetrics()))
+		for _, m := range sm.GetMetrics() {
+			data, err := aggregation(m)
+			if err != nil {
+				return nil, err
+			}
+			ms = append(ms, metricdata.Metrics{
+				Name:        m.GetName(),
+				Description: m.GetDescription(),
+				Unit:        m.GetUnit(),
+				Data:        data,
+			})
+		}
+		out.ScopeMetrics = append(out.ScopeMetrics, metricdata.ScopeMetrics{
+			Scope:   SDKInstrumentationScope(sm.GetScope(), sm.GetSchemaUrl()),
+			Metrics: ms,
+		})
+	}
+	return out, nil
+}
+
+func aggregation(m *metricpb.Metric) (metricdata.Aggregation, error) {
+	switch d := m.GetData().(type) {
+	case *metricpb.Metric_Gauge:
+		dps := d.Gauge.GetDataPoints()
+		if isInt(dps) {
+			return metricdata.Gauge[int64]{DataPoints: sdkDataPoints[int64](dps)}, nil
+		}
+		return metricdata.Gauge[float64]{DataPoints: sdkDataPoints[float64](dps)}, nil
+	case *metricpb.Metric_Sum:
+		dps := d.Sum.GetDataPoints()
+		t := sdkTemporality(d.Sum.GetAggregationTemporality())
+		if isInt(dps) {
+			return metricdata.Sum[int64]{DataPoints: sdkDataPoints[int64](dps), Temporality: t, IsMonotonic: d.Sum.GetIsMonotonic()}, nil
+		}
+		return metricdata.Sum[float64]{DataPoints: sdkDataPoints[float64](dps), Temporality: t, IsMonotonic: d.Sum.GetIsMonotonic()}, nil
+	case *metricpb.Metric_Histogram:
+		return metricdata.Histogram[float64]{
+			DataPoints:  sdkHistogramDataPoints(d.Histogram.GetDataPoints()),
+			Temporality: sdkTemporality(d.Histogram.GetAggregationTemporality()),
+		}, nil
+	case *metricpb.Metric_ExponentialHistogram:
+		return metricdata.ExponentialHistogram[float64]{
+			DataPoints:  sdkExponentialHistogramDataPoints(d.ExponentialHistogram.GetDataPoints()),
+			Temporality: sdkTemporality(d.ExponentialHistogram.GetAggregationTemporality()),
+		}, nil
+	case *metricpb.Metric_Summary:
+		return metricdata.Summary{DataPoints: sdkSummaryDataPoints(d.Summary.GetDataPoints())}, nil
+	default:
+		return nil, fmt.Errorf("metric %q: unknown data type %T", m.GetName(), d)
+	}
+}
+
+func isInt(dps []*metricpb.NumberDataPoint) bool {
+	for _, dp := range dps {
+		if _, ok := dp.GetValue().(*metricpb.NumberDataPoint_AsInt); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func sdkTemporality(t metricpb.AggregationTemporality) metricdata.Temporality {
+	switch t {
+	case metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA:
+		return metricdata.DeltaTemporality
+	case metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE:
+		return metricdata.CumulativeTemporality
+	default:
+		return metricdata.Temporality(0)
+	}
+}
+
+func sdkDataPoints[N int64 | float64](dps []*metricpb.NumberDataPoint) []metricdata.DataPoint[N] {
+	out := make([]metricdata.DataPoint[N], 0, len(dps))
+	for _, dp := range dps {
+		var v N
+		switch pv := dp.GetValue().(type) {
+		case *metricpb.NumberDataPoint_AsInt:
+			v = N(pv.AsInt)
+		case *metricpb.NumberDataPoint_AsDouble:
+			v = N(pv.AsDouble)
+		}
+		out = append(out, metricdata.DataPoint[N]{
+			Attributes: attribute.NewSet(Attributes(dp.GetAttributes())...),
+			StartTime:  timeFromUnixNano(dp.GetStartTimeUnixNano()),
+			Time:       timeFromUnixNano(dp.GetTimeUnixNano()),
+			Value:      v,
+			Exemplars:  sdkExemplars[N](dp.GetExemplars()),
+		})
+	}
+	return out
+}
+
+func sdkHistogramDataPoints(dps []*metricpb.HistogramDataPoint) []metricdata.HistogramDataPoint[float64] {
+	out := make([]metricdata.HistogramDataPoint[float64], 0, len(dps))
+	for _, dp := range dps {
+		out = append(out, metricdata.HistogramDataPoint[float64]{
+			Attributes:   attribute.NewSet(Attributes(dp.GetAttributes())...),
+			StartTime:    timeFromUnixNano(dp.GetStartTimeUnixNano()),
+			Time:         timeFromUnixNano(dp.GetTimeUnixNano()),
+			Count:        dp.GetCount(),
+			Bounds:       dp.GetExplicitBounds(),
+			BucketCounts: dp.GetBucketCounts(),
+			Min:          extrema(dp.Min),
+			Max:          extrema(dp.Max),
+			Sum:          dp.GetSum(),
+			Exemplars:    sdkExemplars[float64](dp.GetExemplars()),
+		})
+	}
+	return out
+}
+
+func sdkExponentialHistogramDataPoints(dps []*metricpb.ExponentialHistogramDataPoint) []metricdata.ExponentialHistogramDataPoint[float64] {
+	out := make([]metricdata.ExponentialHistogramDataPoint[float64], 0, len(dps))
+	for _, dp := range dps {
+		out = append(out, metricdata.ExponentialHistogramDataPoint[float64]{
+			Attributes:    attribute.NewSet(Attributes(dp.GetAttributes())...),
+			StartTime:     timeFromUnixNano(dp.GetStartTimeUnixNano()),
+			Time:          timeFromUnixNano(dp.GetTimeUnixNano()),
+			Count:         dp.GetCount(),
+			Min:           extrema(dp.Min),
+			Max:           extrema(dp.Max),
+			Sum:           dp.GetSum(),
+			Scale:         dp.GetScale(),
+			ZeroCount:     dp.GetZeroCount(),
+			ZeroThreshold: dp.GetZeroThreshold(),
+			PositiveBucket: metricdata.ExponentialBucket{
+				Offset: dp.GetPositive().GetOffset(),
+				Counts: dp.GetPositive().GetBucketCounts(),
+			},
+			NegativeBucket: metricdata.ExponentialBucket{
+				Offset: dp.GetNegative().GetOffset(),
+				Counts: dp.GetNegative().GetBucketCounts(),
+			},
+			Exemplars: sdkExemplars[float64](dp.GetExemplars()),
+		})
+	}
+	return out
+}
+
+func sdkSummaryDataPoints(dps []*metricpb.SummaryDataPoint) []metricdata.SummaryDataPoint {
+	out := make([]metricdata.SummaryDataPoint, 0, len(dps))
+	for _, dp := range dps {
+		sdp := metricdata.SummaryDataPoint{
+			Attributes: attribute.NewSet(Attributes(dp.GetAttributes())...),
+			StartTime:  timeFromUnixNano(dp.GetStartTimeUnixNano()),
+			Time:       timeFromUnixNano(dp.GetTimeUnixNano()),
+			Count:      dp.GetCount(),
+			Sum:        dp.GetSum(),
+		}
+		for _, q := range dp.GetQuantileValues() {
+			sdp.QuantileValues = append(sdp.QuantileValues, metricdata.QuantileValue{
+				Quantile: q.GetQuantile(),
+				Value:    q.GetValue(),
+			})
+		}
+		out = append(out, sdp)
+	}
+	return out
+}
+
+func sdkExemplars[N int64 | float64](exemplars []*metricpb.Exemplar) []metricdata.Exemplar[N] {
+	if len(exemplars) == 0 {
+		return nil
+	}
+	out := make([]metricdata.Exemplar[N], 0, len(exemplars))
+	for _, e := range exemplars {
+		var v N
+		switch pv := e.GetValue().(type) {
+		case *metricpb.Exemplar_AsInt:
+			v = N(pv.AsInt)
+		case *metricpb.Exemplar_AsDouble:
+			v = N(pv.AsDouble)
+		}
+		out = append(out, metricdata.Exemplar[N]{
+			FilteredAttributes: Attributes(e.GetFilteredAttributes()),
+			Time:               timeFromUnixNano(e.GetTimeUnixNano()),
+			Value:              v,
+			SpanID:             e.GetSpanId(),
+			TraceID:            e.GetTraceId(),
+		})
+	}
+	return out
+}
+
+func extrema(v *float64) metricdata.Extrema[float64] {
+	if v == nil {
+		return metricdata.Extrema[float64]{}
+	}
+	return metricdata.NewExtrema(*v)
+}
@@ -0,0 +1,179 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform // import "go.opentelemetry.io/contrib/exporters/autoexport/internal/transform"
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+var (
+	start = time.Unix(1700000000, 1)
+	end   = start.Add(time.Second)
+	attrs = attribute.NewSet(attribute.String("key", "value"), attribute.Int64Slice("ints", []int64{1, 2}))
+)
+
+func TestResourceMetricsRoundTrip(t *testing.T) {
+	want := metricdata.ResourceMetrics{
+		Resource: resource.NewWithAttributes("https://example.com/schema", attribute.String("service.name", "test")),
+		ScopeMetrics: []metricdata.ScopeMetrics{{
+			Scope: instrumentation.Scope{
+				Name:       "scope",
+				Version:    "v1",
+				SchemaURL:  "https://example.com/scope",
+				Attributes: attribute.NewSet(attribute.String("scope.key", "scope.value")),
+			},
+			Metrics: []metricdata.Metrics{
+				{
+					Name:        "int64 sum",
+					Description: "a sum",
+					Unit:        "1",
+					Data: metricdata.Sum[int64]{
+						Temporality: metricdata.DeltaTemporality,
+						IsMonotonic: true,
+						DataPoints:  []metricdata.DataPoint[int64]{{Attributes: attrs, StartTime: start, Time: end, Value: 2}},
+					},
+				},
+				{
+					Name: "float64 gauge",
+					Data: metricdata.Gauge[float64]{
+						DataPoints: []metricdata.DataPoint[float64]{{
+							Attributes: attrs,
+							Time:       end,
+							Value:      1.5,
+							Exemplars: []metricdata.Exemplar[float64]{{
+								FilteredAttributes: []attribute.KeyValue{attribute.Bool("b", true)},
+								Time:               end,
+								Value:              1.5,
+								SpanID:             []byte{1, 2, 3, 4, 5, 6, 7, 8},
+								TraceID:            []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+							}},
+						}},
+					},
+				},
+				{
+					Name: "histogram",
+					Data: metricdata.Histogram[float64]{
+						Temporality: metricdata.CumulativeTemporality,
+						DataPoints: []metricdata.HistogramDataPoint[float64]{{
+							Attributes:   attrs,
+							StartTime:    start,
+							Time:         end,
+							Count:        3,
+							Bounds:       []float64{1, 10},
+							BucketCounts: []uint64{1, 1, 1},
+							Min:          metricdata.NewExtrema(0.5),
+							Max:          metricdata.NewExtrema(20.0),
+							Sum:          25.5,
+						}},
+					},
+				},
+				{
+					Name: "exponential histogram",
+					Data: metricdata.ExponentialHistogram[float64]{
+						Temporality: metricdata.DeltaTemporality,
+						DataPoints: []metricdata.ExponentialHistogramDataPoint[float64]{{
+							Attributes:     attrs,
+							StartTime:      start,
+							Time:           end,
+							Count:          4,
+							Sum:            10,
+							Scale:          2,
+							ZeroCount:      1,
+							PositiveBucket: metricdata.ExponentialBucket{Offset: 1, Counts: []uint64{1, 2}},
+							NegativeBucket: metricdata.ExponentialBucket{Offset: -1, Counts: []uint64{0}},
+						}},
+					},
+				},
+				{
+					Name: "summary",
+					Data: metricdata.Summary{
+						DataPoints: []metricdata.SummaryDataPoint{{
+							Attributes:     attrs,
+							StartTime:      start,
+							Time:           end,
+							Count:          2,
+							Sum:            3,
+							QuantileValues: []metricdata.QuantileValue{{Quantile: 0.5, Value: 1}, {Quantile: 1, Value: 2}},
+						}},
+					},
+				},
+			},
+		}},
+	}
+
+	pb, err := ResourceMetrics(&want)
+	require.NoError(t, err)
+	got, err := SDKResourceMetrics(pb)
+	require.NoError(t, err)
+	metricdatatest.AssertEqual(t, want, *got)
+}
+
+func TestResourceMetricsIntegerHistogram(t *testing.T) {
+	rm := &metricdata.ResourceMetrics{
+		Resource: resource.Empty(),
+		ScopeMetrics: []metricdata.ScopeMetrics{{
+			Metrics: []metricdata.Metrics{{
+				Name: "histogram",
+				Data: metricdata.Histogram[int64]{
+					Temporality: metricdata.DeltaTemporality,
+					DataPoints: []metricdata.HistogramDataPoint[int64]{{
+						Attributes:   *attribute.EmptySet(),
+						Time:         end,
+						Count:        1,
+						Bounds:       []float64{5},
+						BucketCounts: []uint64{1, 0},
+						Min:          metricdata.NewExtrema[int64](3),
+						Max:          metricdata.NewExtrema[int64](3),
+						Sum:          3,
+					}},
+				},
+			}},
+		}},
+	}
+
+	pb, err := ResourceMetrics(rm)
+	require.NoError(t, err)
+	got, err := SDKResourceMetrics(pb)
+	require.NoError(t, err)
+
+	want := metricdata.Metrics{
+		Name: "histogram",
+		Data: metricdata.Histogram[float64]{
+			Temporality: metricdata.DeltaTemporality,
+			DataPoints: []metricdata.HistogramDataPoint[float64]{{
+				Attributes:   *attribute.EmptySet(),
+				Time:         end,
+				Count:        1,
+				Bounds:       []float64{5},
+				BucketCounts: []uint64{1, 0},
+				Min:          metricdata.NewExtrema(3.0),
+				Max:          metricdata.NewExtrema(3.0),
+				Sum:          3,
+			}},
+		},
+	}
+	require.Len(t, got.ScopeMetrics, 1)
+	require.Len(t, got.ScopeMetrics[0].Metrics, 1)
+	metricdatatest.AssertEqual(t, want, got.ScopeMetrics[0].Metrics[0])
+}
@@ -0,0 +1,366 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform // import "go.opentelemetry.io/contrib/exporters/autoexport/internal/transform"
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// errInvalidID is returned for trace and span IDs with an invalid length.
+var errInvalidID = errors.New("invalid ID length")
+
+// Spans returns the read-only spans of the OTLP resourceSpans.
+//
+// The trace flags of the spans are not part of the OTLP spans, exported
+// spans being sampled they are all marked as sampled.
+func Spans(resourceSpans []*tracepb.ResourceSpans) ([]sdktrace.ReadOnlySpan, error) {
+	var spans []sdktrace.ReadOnlySpan
+	for _, rs := range resourceSpans {
+		res := SDKResource(rs.GetResource(), rs.GetSchemaUrl())
+		for _, ss := range rs.GetScopeSpans() {
+			scope := SDKInstrumentationScope(ss.GetScope(), ss.GetSchemaUrl())
+			for _, s := range ss.GetSpans() {
+				sd, err := readOnlySpanFromProto(s)
+				if err != nil {
+					return nil, fmt.Errorf("span %q: %w", s.GetName(), err)
+				}
+				sd.resource = res
+				sd.scope = scope
+				spans = append(spans, sd)
+			}
+		}
+	}
+	return spans, nil
+}
+
+var _ sdktrace.ReadOnlySpan = (*readOnlySpan)(nil)
+
+// readOnlySpan is an sdktrace.ReadOnlySpan decoded from an OTLP span.
+type readOnlySpan struct {
+	// Embed the interface to implement its private method, the other
+	// methods are all overridden.
+	sdktrace.ReadOnlySpan
+
+	name              string
+	spanContext       trace.SpanContext
+	parent            trace.SpanContext
+	spanKind          trace.SpanKind
+	startTime         time.Time
+	endTime           time.Time
+	attributes        []attribute.KeyValue
+	links             []sdktrace.Link
+	events            []sdktrace.Event
+	status            sdktrace.Status
+	scope             instrumentation.Scope
+	resource          *resource.Resource
+	droppedAttributes int
+	droppedLinks      int
+	droppedEvents     int
+}
+
+func (s *readOnlySpan) Name() string                                { return s.name }
+func (s *readOnlySpan) SpanContext() trace.SpanContext              { return s.spanContext }
+func (s *readOnlySpan) Parent() trace.SpanContext                   { return s.parent }
+func (s *readOnlySpan) SpanKind() trace.SpanKind                    { return s.spanKind }
+func (s *readOnlySpan) StartTime() time.Time                        { return s.startTime }
+func (s *readOnlySpan) EndTime() time.Time                          { return s.endTime }
+func (s *readOnlySpan) Attributes() []attribute.KeyValue            { return s.attributes }
+func (s *readOnlySpan) Links() []sdktrace.Link                      { return s.links }
+func (s *readOnlySpan) Events() []sdktrace.Event                    { return s.events }
+func (s *readOnlySpan) Status() sdktrace.Status                     { return s.status }
+func (s *readOnlySpan) InstrumentationScope() instrumentation.Scope { return s.scope }
+func (s *readOnlySpan) Resource() *resource.Resource                { return s.resource }
+func (s *readOnlySpan) DroppedAttributes() int                      { return s.droppedAttributes }
+func (s *readOnlySpan) DroppedLinks() int                           { return s.droppedLinks }
+func (s *readOnlySpan) DroppedEvents() int                          { return s.droppedEvents }
+
+//nolint:staticcheck // This method needs to be defined for backwards compatibility.
+func (s *readOnlySpan) InstrumentationLibrary() instrumentation.Library { return s.scope }
+
+// ChildSpanCount returns 0, the number of child spans is not part of the
+// OTLP spans.
+func (s *readOnlySpan) ChildSpanCount() int { return 0 }
+
+func readOnlySpanFromProto(s *tracepb.Span) (*readOnlySpan, error) {
+	traceID, err := toTraceID(s.GetTraceId())
+	if err != nil {
+		return nil, err
+	}
+	spanID, err := toSpanID(s.GetSpanId())
+	if err != nil {
+		return nil, err
+	}
+	traceState, err := trace.ParseTraceState(s.GetTraceState())
+	if err != nil {
+		return nil, err
+	}
+
+	sd := &readOnlySpan{
+		name: s.GetName(),
+		spanContext: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     spanID,
+			TraceFlags: trace.FlagsSampled,
+			TraceState: traceState,
+		}),
+		spanKind:          spanKind(s.GetKind()),
+		startTime:         timeFromUnixNano(s.GetStartTimeUnixNano()),
+		endTime:           timeFromUnixNano(s.GetEndTimeUnixNano()),
+		attributes:        Attributes(s.GetAttributes()),
+		status:            status(s.GetStatus()),
+		droppedAttributes: int(s.GetDroppedAttributesCount()),
+		droppedEvents:     int(s.GetDroppedEventsCount()),
+		droppedLinks:      int(s.GetDroppedLinksCount()),
+	}
+
+	if len(s.GetParentSpanId()) > 0 {
+		parentID, err := toSpanID(s.GetParentSpanId())
+		if err != nil {
+			return nil, err
+		}
+		sd.parent = trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     parentID,
+			TraceFlags: trace.FlagsSampled,
+			Remote:     isRemote(s.GetFlags()),
+		})
+	}
+
+	for _, e := range s.GetEvents() {
+		sd.events = append(sd.events, sdktrace.Event{
+			Name:                  e.GetName(),
+			Attributes:            Attributes(e.GetAttributes()),
+			DroppedAttributeCount: int(e.GetDroppedAttributesCount()),
+			Time:                  timeFromUnixNano(e.GetTimeUnixNano()),
+		})
+	}
+
+	for _, l := range s.GetLinks() {
+		linkTraceID, err := toTraceID(l.GetTraceId())
+		if err != nil {
+			return nil, err
+		}
+		linkSpanID, err := toSpanID(l.GetSpanId())
+		if err != nil {
+			return nil, err
+		}
+		linkTraceState, err := trace.ParseTraceState(l.GetTraceState())
+		if err != nil {
+			return nil, err
+		}
+		sd.links = append(sd.links, sdktrace.Link{
+			SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID:    linkTraceID,
+				SpanID:     linkSpanID,
+				TraceFlags: trace.FlagsSampled,
+				TraceState: linkTraceState,
+				Remote:     isRemote(l.GetFlags()),
+			}),
+			Attributes:            Attributes(l.GetAttributes()),
+			DroppedAttributeCount: int(l.GetDroppedAttributesCount()),
+		})
+	}
+	return sd, nil
+}
+
+// ResourceSpans returns the OTLP representation of spans, grouped by resource
+// and instrumentation scope.
+func ResourceSpans(spans []sdktrace.ReadOnlySpan) []*tracepb.ResourceSpans {
+	var out []*tracepb.ResourceSpans
+	resources := make(map[attribute.Distinct]*tracepb.ResourceSpans)
+	scopes := make(map[attribute.Distinct]map[scopeKey]*tracepb.ScopeSpans)
+	for _, sd := range spans {
+		if sd == nil {
+			continue
+		}
+
+		res := sd.Resource()
+		resKey := res.Equivalent()
+		rs, ok := resources[resKey]
+		if !ok {
+			rs = &tracepb.ResourceSpans{Resource: Resource(res), SchemaUrl: res.SchemaURL()}
+			resources[resKey] = rs
+			scopes[resKey] = make(map[scopeKey]*tracepb.ScopeSpans)
+			out = append(out, rs)
+		}
+
+		scope := sd.InstrumentationScope()
+		key := scopeKey{
+			name:      scope.Name,
+			version:   scope.Version,
+			schemaURL: scope.SchemaURL,
+			attrs:     scope.Attributes.Equivalent(),
+		}
+		ss, ok := scopes[resKey][key]
+		if !ok {
+			ss = &tracepb.ScopeSpans{Scope: InstrumentationScope(scope), SchemaUrl: scope.SchemaURL}
+			scopes[resKey][key] = ss
+			rs.ScopeSpans = append(rs.ScopeSpans, ss)
+		}
+		ss.Spans = append(ss.Spans, span(sd))
+	}
+	return out
+}
+
+func span(sd sdktrace.ReadOnlySpan) *tracepb.Span {
+	sc := sd.SpanContext()
+	tid, sid := sc.TraceID(), sc.SpanID()
+	s := &tracepb.Span{
+		TraceId:                tid[:],
+		SpanId:                 sid[:],
+		TraceState:             sc.TraceState().String(),
+		Name:                   sd.Name(),
+		Kind:                   protoSpanKind(sd.SpanKind()),
+		StartTimeUnixNano:      unixNano(sd.StartTime()),
+		EndTimeUnixNano:        unixNano(sd.EndTime()),
+		Attributes:             KeyValues(sd.Attributes()),
+		DroppedAttributesCount: clampUint32(sd.DroppedAttributes()),
+		DroppedEventsCount:     clampUint32(sd.DroppedEvents()),
+		DroppedLinksCount:      clampUint32(sd.DroppedLinks()),
+		Status:                 protoStatus(sd.Status()),
+		Flags:                  flags(sd.Parent()),
+	}
+	if psid := sd.Parent().SpanID(); psid.IsValid() {
+		s.ParentSpanId = psid[:]
+	}
+	for _, e := range sd.Events() {
+		s.Events = append(s.Events, &tracepb.Span_Event{
+			TimeUnixNano:           unixNano(e.Time),
+			Name:                   e.Name,
+			Attributes:             KeyValues(e.Attributes),
+			DroppedAttributesCount: clampUint32(e.DroppedAttributeCount),
+		})
+	}
+	for _, l := range sd.Links() {
+		ltid, lsid := l.SpanContext.TraceID(), l.SpanContext.SpanID()
+		s.Links = append(s.Links, &tracepb.Span_Link{
+			TraceId:                ltid[:],
+			SpanId:                 lsid[:],
+			TraceState:             l.SpanContext.TraceState().String(),
+			Attributes:             KeyValues(l.Attributes),
+			DroppedAttributesCount: clampUint32(l.DroppedAttributeCount),
+			Flags:                  flags(l.SpanContext),
+		})
+	}
+	return s
+}
+
+// flags returns the OTLP span flags of the parent or linked span context sc.
+func flags(sc trace.SpanContext) uint32 {
+	f := uint32(sc.TraceFlags()) | uint32(tracepb.SpanFlags_SPAN_FLAGS_CONTEXT_HAS_IS_REMOTE_MASK)
+	if sc.IsRemote() {
+		f |= uint32(tracepb.SpanFlags_SPAN_FLAGS_CONTEXT_IS_REMOTE_MASK)
+	}
+	return f
+}
+
+func clampUint32(n int) uint32 {
+	return uint32(max(0, n)) // nolint:gosec // Overflow checked.
+}
+
+func protoSpanKind(kind trace.SpanKind) tracepb.Span_SpanKind {
+	switch kind {
+	case trace.SpanKindInternal:
+		return tracepb.Span_SPAN_KIND_INTERNAL
+	case trace.SpanKindServer:
+		return tracepb.Span_SPAN_KIND_SERVER
+	case trace.SpanKindClient:
+		return tracepb.Span_SPAN_KIND_CLIENT
+	case trace.SpanKindProducer:
+		return tracepb.Span_SPAN_KIND_PRODUCER
+	case trace.SpanKindConsumer:
+		return tracepb.Span_SPAN_KIND_CONSUMER
+	default:
+		return tracepb.Span_SPAN_KIND_UNSPECIFIED
+	}
+}
+
+func protoStatus(s sdktrace.Status) *tracepb.Status {
+	switch s.Code {
+	case codes.Ok:
+		return &tracepb.Status{Code: tracepb.Status_STATUS_CODE_OK}
+	case codes.Error:
+		return &tracepb.Status{Code: tracepb.Status_STATUS_CODE_ERROR, Message: s.Description}
+	default:
+		return &tracepb.Status{Code: tracepb.Status_STATUS_CODE_UNSET}
+	}
+}
+
+func toTraceID(b []byte) (trace.TraceID, error) {
+	var id trace.TraceID
+	if len(b) != len(id) {
+		return id, fmt.Errorf("%w: trace ID of %d bytes", errInvalidID, len(b))
+	}
+	copy(id[:], b)
+	return id, nil
+}
+
+func toSpanID(b []byte) (trace.SpanID, error) {
+	var id trace.SpanID
+	if len(b) != len(id) {
+		return id, fmt.Errorf("%w: span ID of %d bytes", errInvalidID, len(b))
+	}
+	copy(id[:], b)
+	return id, nil
+}
+
+func isRemote(flags uint32) bool {
+	return flags&uint32(tracepb.SpanFlags_SPAN_FLAGS_CONTEXT_IS_REMOTE_MASK) != 0
+}
+
+func spanKind(kind tracepb.Span_SpanKind) trace.SpanKind {
+	switch kind {
+	case tracepb.Span_SPAN_KIND_INTERNAL:
+		return trace.SpanKindInternal
+	case tracepb.Span_SPAN_KIND_SERVER:
+		return trace.SpanKindServer
+	case tracepb.Span_SPAN_KIND_CLIENT:
+		return trace.SpanKindClient
+	case tracepb.Span_SPAN_KIND_PRODUCER:
+		return trace.SpanKindProducer
+	case tracepb.Span_SPAN_KIND_CONSUMER:
+		return trace.SpanKindConsumer
+	default:
+		return trace.SpanKindUnspecified
+	}
+}
+
+func status(s *tracepb.Status) sdktrace.Status {
+	switch s.GetCode() {
+	case tracepb.Status_STATUS_CODE_OK:
+		return sdktrace.Status{Code: codes.Ok}
+	case tracepb.Status_STATUS_CODE_ERROR:
+		return sdktrace.Status{Code: codes.Error, Description: s.GetMessage()}
+	default:
+		return sdktrace.Status{Code: codes.Unset}
+	}
+}
+
+func timeFromUnixNano(ns uint64) time.Time {
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(ns)) // nolint:gosec // Timestamps before 2262 do not overflow.
+}
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoexport // import "go.opentelemetry.io/contrib/exporters/autoexport"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+	"go.opentelemetry.io/otel/sdk/log"
+)
+
+// LogOption applies an autoexport configuration option.
+type LogOption = option[log.Exporter]
+
+// WithFallbackLogExporter sets the fallback exporter to use when no exporter
+// is configured through the OTEL_LOGS_EXPORTER environment variable.
+func WithFallbackLogExporter(logExporterFactory func(ctx context.Context) (log.Exporter, error)) LogOption {
+	return withFallbackFactory[log.Exporter](logExporterFactory)
+}
+
+// NewLogExporter returns a configured [go.opentelemetry.io/otel/sdk/log.Exporter]
+// defined using the environment variables described below.
+//
+// OTEL_LOGS_EXPORTER defines the logs exporter; supported values:
+//   - "none" - "no operation" exporter
+//   - "otlp" (default) - OTLP exporter; see [go.opentelemetry.io/otel/exporters/otlp/otlplog]
+//   - "console" - Standard output exporter; see [go.opentelemetry.io/otel/exporters/stdout/stdoutlog]
+//   - "otlp/file" - OTLP JSON file exporter writing to the file at
+//     OTEL_EXPORTER_OTLP_LOGS_FILE_PATH
+//
+// OTEL_LOGS_EXPORTER may list multiple comma-separated exporters, e.g.
+// "otlp,console". The returned exporter then exports to all of them.
+// Duplicates are ignored, and so is "none" if it is listed with other
+// exporters.
+//
+// OTEL_EXPORTER_OTLP_LOGS_PROTOCOL, or OTEL_EXPORTER_OTLP_PROTOCOL if it is
+// unset, defines OTLP exporter's transport protocol; supported values:
+//   - "grpc" (or "grpc/protobuf") - protobuf-encoded data using gRPC wire format over HTTP/2 connection;
+//     see: [go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc]
+//   - "http/protobuf" (default) -  protobuf-encoded data over HTTP connection;
+//     see: [go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp]
+//
+// The "otlp/file" exporter writes a line of OTLP protobuf JSON per export.
+// OTEL_EXPORTER_OTLP_FILE_MAX_SIZE defines the size, in bytes, at which the
+// file is rotated: it is renamed with a numeric suffix, e.g. "logs.jsonl.1",
+// and a new file is created. Files are not rotated by default.
+// OTEL_EXPORTER_OTLP_FILE_COMPRESSION set to "gzip" compresses the file, an
+// existing file is then rotated instead of being appended to.
+//
+// An error is returned if an environment value is set to an unhandled value.
+//
+// Use [RegisterLogExporter] to handle more values of OTEL_LOGS_EXPORTER.
+//
+// Use [WithFallbackLogExporter] option to change the returned exporter
+// when OTEL_LOGS_EXPORTER is unset or empty.
+//
+// Use [IsNoneLogExporter] to check if the returned exporter is a "no operation" exporter.
+func NewLogExporter(ctx context.Context, opts ...LogOption) (log.Exporter, error) {
+	return logsSignal.create(ctx, opts...)
+}
+
+// RegisterLogExporter sets the log.Exporter factory to be used when the
+// OTEL_LOGS_EXPORTER environment variable contains the exporter name. This
+// will panic if name has already been registered.
+func RegisterLogExporter(name string, factory func(context.Context) (log.Exporter, error)) {
+	must(logsSignal.registry.store(name, factory))
+}
+
+var logsSignal = newSignal[log.Exporter]("OTEL_LOGS_EXPORTER", newCompositeLogExporter, nil, nil)
+
+func init() {
+	RegisterLogExporter("otlp", func(ctx context.Context) (log.Exporter, error) {
+		proto, err := otlpProtocol(otelExporterOTLPLogsProtoEnvKey)
+		if err != nil {
+			return nil, err
+		}
+
+		switch proto {
+		case otlpProtocolGRPC:
+			return otlploggrpc.New(ctx)
+		default: // otlpProtocolHTTPProtobuf
+			return otlploghttp.New(ctx)
+		}
+	})
+	RegisterLogExporter("console", func(ctx context.Context) (log.Exporter, error) {
+		return stdoutlog.New()
+	})
+	RegisterLogExporter("none", func(ctx context.Context) (log.Exporter, error) {
+		return noopLogExporter{}, nil
+	})
+	RegisterLogExporter("otlp/file", func(ctx context.Context) (log.Exporter, error) {
+		return newFileLogExporter()
+	})
+}
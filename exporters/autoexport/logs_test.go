@@ -0,0 +1,115 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoexport // import "go.opentelemetry.io/contrib/exporters/autoexport"
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+	"go.opentelemetry.io/otel/sdk/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogExporterNone(t *testing.T) {
+	t.Setenv("OTEL_LOGS_EXPORTER", "none")
+	got, err := NewLogExporter(context.Background())
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, got.Shutdown(context.Background()))
+	})
+	assert.True(t, IsNoneLogExporter(got))
+}
+
+func TestLogExporterConsole(t *testing.T) {
+	t.Setenv("OTEL_LOGS_EXPORTER", "console")
+	got, err := NewLogExporter(context.Background())
+	assert.NoError(t, err)
+	assert.IsType(t, &stdoutlog.Exporter{}, got)
+}
+
+func TestLogExporterOTLP(t *testing.T) {
+	t.Setenv("OTEL_LOGS_EXPORTER", "otlp")
+
+	for _, tc := range []struct {
+		protocol string
+		exporter log.Exporter
+	}{
+		{"http/protobuf", &otlploghttp.Exporter{}},
+		{"", &otlploghttp.Exporter{}},
+		{"grpc", &otlploggrpc.Exporter{}},
+		{"grpc/protobuf", &otlploggrpc.Exporter{}},
+	} {
+		t.Run(fmt.Sprintf("protocol=%q", tc.protocol), func(t *testing.T) {
+			t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", tc.protocol)
+
+			got, err := NewLogExporter(context.Background())
+			assert.NoError(t, err)
+			t.Cleanup(func() {
+				assert.NoError(t, got.Shutdown(context.Background()))
+			})
+			assert.IsType(t, tc.exporter, got)
+		})
+	}
+}
+
+func TestLogExporterOTLPSignalProtocol(t *testing.T) {
+	t.Setenv("OTEL_LOGS_EXPORTER", "otlp")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "http/protobuf")
+	t.Setenv("OTEL_EXPORTER_OTLP_LOGS_PROTOCOL", "grpc")
+
+	got, err := NewLogExporter(context.Background())
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, got.Shutdown(context.Background()))
+	})
+	assert.IsType(t, &otlploggrpc.Exporter{}, got)
+}
+
+func TestLogExporterOTLPOverInvalidProtocol(t *testing.T) {
+	t.Setenv("OTEL_LOGS_EXPORTER", "otlp")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "invalid-protocol")
+
+	_, err := NewLogExporter(context.Background())
+	assert.Error(t, err)
+}
+
+func TestLogExporterFallback(t *testing.T) {
+	fallback := noopLogExporter{}
+	got, err := NewLogExporter(context.Background(), WithFallbackLogExporter(func(context.Context) (log.Exporter, error) {
+		return fallback, nil
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, fallback, got)
+}
+
+func TestLogExporterList(t *testing.T) {
+	t.Setenv("OTEL_LOGS_EXPORTER", "otlp,console")
+	got, err := NewLogExporter(context.Background())
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, got.Shutdown(context.Background()))
+	})
+	require.IsType(t, compositeLogExporter{}, got)
+	exporters := got.(compositeLogExporter)
+	require.Len(t, exporters, 2)
+	assert.IsType(t, &otlploghttp.Exporter{}, exporters[0])
+	assert.IsType(t, &stdoutlog.Exporter{}, exporters[1])
+}
@@ -16,27 +16,51 @@ package autoexport // import "go.opentelemetry.io/contrib/exporters/autoexport"
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"net"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
-
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
-	promexporter "go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
 	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 )
 
 // MetricOption applies an autoexport configuration option.
 type MetricOption = option[metric.Reader]
 
+// metricConfig is the configuration of the metric readers set with the
+// MetricOptions that only apply to metrics.
+type metricConfig struct {
+	// prometheusMux and prometheusDefaultRegistry configure the
+	// "prometheus" metric reader.
+	prometheusMux             *http.ServeMux
+	prometheusDefaultRegistry bool
+}
+
+// metricOptionFunc is a MetricOption setting a metricConfig field. It does
+// not change the configuration common to all signals.
+type metricOptionFunc func(cfg *metricConfig)
+
+func (metricOptionFunc) apply(*config[metric.Reader]) {}
+
+// withMetricConfig returns opts followed by the options passing their
+// metricConfig to the factories.
+func withMetricConfig(opts []MetricOption) []MetricOption {
+	var cfg metricConfig
+	for _, opt := range opts {
+		if fn, ok := opt.(metricOptionFunc); ok {
+			fn(&cfg)
+		}
+	}
+	return append(opts[:len(opts):len(opts)], withFactory("prometheus", cfg.newPrometheusReader))
+}
+
 // WithFallbackMetricReader sets the fallback exporter to use when no exporter
 // is configured through the OTEL_METRICS_EXPORTER environment variable.
 func WithFallbackMetricReader(metricReaderFactory func(ctx context.Context) (metric.Reader, error)) MetricOption {
@@ -51,17 +75,59 @@ func WithFallbackMetricReader(metricReaderFactory func(ctx context.Context) (met
 //   - "otlp" (default) - OTLP exporter; see [go.opentelemetry.io/otel/exporters/otlp/otlpmetric]
 //   - "prometheus" - Prometheus exporter + HTTP server; see [go.opentelemetry.io/otel/exporters/prometheus]
 //   - "console" - Standard output exporter; see [go.opentelemetry.io/otel/exporters/stdout/stdoutmetric]
+//   - "otlp/file" - OTLP JSON file exporter writing to the file at
+//     OTEL_EXPORTER_OTLP_METRICS_FILE_PATH; see [ReplayMetrics]
+//
+// An error is returned if OTEL_METRICS_EXPORTER lists multiple exporters, use
+// [NewMetricReaders] to support lists of exporters.
 //
-// OTEL_EXPORTER_OTLP_PROTOCOL defines OTLP exporter's transport protocol;
-// supported values:
-//   - "grpc" - protobuf-encoded data using gRPC wire format over HTTP/2 connection;
+// OTEL_EXPORTER_OTLP_METRICS_PROTOCOL, or OTEL_EXPORTER_OTLP_PROTOCOL if it is
+// unset, defines OTLP exporter's transport protocol; supported values:
+//   - "grpc" (or "grpc/protobuf") - protobuf-encoded data using gRPC wire format over HTTP/2 connection;
 //     see: [go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc]
 //   - "http/protobuf" (default) -  protobuf-encoded data over HTTP connection;
 //     see: [go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp]
 //
+// OTEL_METRIC_EXPORT_INTERVAL and OTEL_METRIC_EXPORT_TIMEOUT define the
+// interval and timeout, in milliseconds, of the periodic readers of the
+// "otlp", "otlp/file" and "console" exporters.
+//
+// OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE defines the temporality
+// of the OTLP exporters; supported values: "cumulative" (default), "delta" and
+// "lowmemory". OTEL_EXPORTER_OTLP_METRICS_DEFAULT_HISTOGRAM_AGGREGATION defines
+// the default aggregation of histograms of the OTLP exporters; supported
+// values: "explicit_bucket_histogram" (default) and
+// "base2_exponential_bucket_histogram". Invalid values of these environment
+// variables are reported with [go.opentelemetry.io/otel.Handle] and the
+// defaults are used.
+//
 // OTEL_EXPORTER_PROMETHEUS_HOST (defaulting to "localhost") and
 // OTEL_EXPORTER_PROMETHEUS_PORT (defaulting to 9464) define the host and port for the
 // Prometheus exporter's HTTP server.
+// OTEL_EXPORTER_PROMETHEUS_PATH (defaulting to "/metrics") defines the path of
+// the metrics endpoint. OTEL_EXPORTER_PROMETHEUS_TLS_CERT_FILE and
+// OTEL_EXPORTER_PROMETHEUS_TLS_KEY_FILE define the certificate and key files
+// of the server; they must be set together and enable HTTPS. Use
+// [WithPrometheusServeMux] to serve the metrics endpoint on an existing
+// [net/http.ServeMux] instead, and [WithPrometheusDefaultRegistry] to also
+// serve the metrics of the default Prometheus registry. Use
+// [PrometheusServerAddr] to get the address of the server.
+//
+// The "otlp/file" exporter writes a line of OTLP protobuf JSON per export.
+// OTEL_EXPORTER_OTLP_FILE_MAX_SIZE defines the size, in bytes, at which the
+// file is rotated: it is renamed with a numeric suffix, e.g. "metrics.jsonl.1",
+// and a new file is created. Files are not rotated by default.
+// OTEL_EXPORTER_OTLP_FILE_COMPRESSION set to "gzip" compresses the file, an
+// existing file is then rotated instead of being appended to.
+//
+// Use [WithMetricReaderMeterProvider] to record metrics about the exports
+// of the periodic readers: the otel.sdk.exporter.metric_data_point.exported
+// and otel.sdk.exporter.metric_data_point.failed counters and the
+// otel.sdk.exporter.operation.duration histogram, with the "exporter" and
+// "signal" attributes, and the "error.type" attribute for failed exports.
+// The data points of these metrics are not counted, so the MeterProvider may
+// use the returned reader. The "prometheus" reader and the readers registered with
+// [RegisterMetricReader] are not instrumented.
 //
 // An error is returned if an environment value is set to an unhandled value.
 //
@@ -72,7 +138,23 @@ func WithFallbackMetricReader(metricReaderFactory func(ctx context.Context) (met
 //
 // Use [IsNoneMetricReader] to check if the retured exporter is a "no operation" exporter.
 func NewMetricReader(ctx context.Context, opts ...MetricOption) (metric.Reader, error) {
-	return metricsSignal.create(ctx, opts...)
+	return metricsSignal.create(ctx, withMetricConfig(opts)...)
+}
+
+// NewMetricReaders returns a [go.opentelemetry.io/otel/sdk/metric.Reader] for
+// each of the comma-separated exporters listed in the OTEL_METRICS_EXPORTER
+// environment variable, e.g. "otlp,prometheus". Duplicates are ignored, and
+// so is "none" if it is listed with other exporters. Each reader is meant to
+// be registered with the MeterProvider using
+// [go.opentelemetry.io/otel/sdk/metric.WithReader].
+//
+// See [NewMetricReader] for the supported exporters and environment
+// variables.
+//
+// If a reader cannot be created, the readers created before it are shut down
+// and an error is returned.
+func NewMetricReaders(ctx context.Context, opts ...MetricOption) ([]metric.Reader, error) {
+	return metricsSignal.createAll(ctx, withMetricConfig(opts)...)
 }
 
 // RegisterMetricReader sets the MetricReader factory to be used when the
@@ -82,30 +164,45 @@ func RegisterMetricReader(name string, factory func(context.Context) (metric.Rea
 	must(metricsSignal.registry.store(name, factory))
 }
 
-var metricsSignal = newSignal[metric.Reader]("OTEL_METRICS_EXPORTER")
+var metricsSignal = newSignal[metric.Reader]("OTEL_METRICS_EXPORTER", nil, nil, nil)
 
 func init() {
 	RegisterMetricReader("otlp", func(ctx context.Context) (metric.Reader, error) {
-		proto := os.Getenv(otelExporterOTLPProtoEnvKey)
-		if proto == "" {
-			proto = "http/protobuf"
+		proto, err := otlpProtocol(otelExporterOTLPMetricsProtoEnvKey)
+		if err != nil {
+			return nil, err
 		}
 
+		temporality := otlpMetricsTemporalitySelector()
+		aggregation := otlpMetricsAggregationSelector()
+
 		switch proto {
-		case "grpc":
-			r, err := otlpmetricgrpc.New(ctx)
+		case otlpProtocolGRPC:
+			var opts []otlpmetricgrpc.Option
+			if temporality != nil {
+				opts = append(opts, otlpmetricgrpc.WithTemporalitySelector(temporality))
+			}
+			if aggregation != nil {
+				opts = append(opts, otlpmetricgrpc.WithAggregationSelector(aggregation))
+			}
+			r, err := otlpmetricgrpc.New(ctx, opts...)
 			if err != nil {
 				return nil, err
 			}
-			return metric.NewPeriodicReader(r), nil
-		case "http/protobuf":
-			r, err := otlpmetrichttp.New(ctx)
+			return newPeriodicReader(ctx, r), nil
+		default: // otlpProtocolHTTPProtobuf
+			var opts []otlpmetrichttp.Option
+			if temporality != nil {
+				opts = append(opts, otlpmetrichttp.WithTemporalitySelector(temporality))
+			}
+			if aggregation != nil {
+				opts = append(opts, otlpmetrichttp.WithAggregationSelector(aggregation))
+			}
+			r, err := otlpmetrichttp.New(ctx, opts...)
 			if err != nil {
 				return nil, err
 			}
-			return metric.NewPeriodicReader(r), nil
-		default:
-			return nil, errInvalidOTLPProtocol
+			return newPeriodicReader(ctx, r), nil
 		}
 	})
 	RegisterMetricReader("console", func(ctx context.Context) (metric.Reader, error) {
@@ -113,65 +210,26 @@ func init() {
 		if err != nil {
 			return nil, err
 		}
-		return metric.NewPeriodicReader(r), nil
+		return newPeriodicReader(ctx, r), nil
 	})
 	RegisterMetricReader("none", func(ctx context.Context) (metric.Reader, error) {
 		return newNoopMetricReader(), nil
 	})
-	RegisterMetricReader("prometheus", func(ctx context.Context) (metric.Reader, error) {
-		// create an isolated registry instead of using the global registry --
-		// the user might not want to mix OTel with non-OTel metrics
-		reg := prometheus.NewRegistry()
-
-		reader, err := promexporter.New(promexporter.WithRegisterer(reg))
+	RegisterMetricReader("otlp/file", func(ctx context.Context) (metric.Reader, error) {
+		r, err := newFileMetricExporter()
 		if err != nil {
 			return nil, err
 		}
-
-		mux := http.NewServeMux()
-		mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg}))
-		server := http.Server{
-			// Timeouts are necessary to make a server resilent to attacks, but ListenAndServe doesn't set any.
-			// We use values from this example: https://blog.cloudflare.com/exposing-go-on-the-internet/#:~:text=There%20are%20three%20main%20timeouts
-			ReadTimeout:  5 * time.Second,
-			WriteTimeout: 10 * time.Second,
-			IdleTimeout:  120 * time.Second,
-			Handler:      mux,
-		}
-
-		// environment variable names and defaults specified at https://opentelemetry.io/docs/specs/otel/configuration/sdk-environment-variables/#prometheus-exporter
-		host := getenv("OTEL_EXPORTER_PROMETHEUS_HOST", "localhost")
-		port := getenv("OTEL_EXPORTER_PROMETHEUS_PORT", "9464")
-		addr := host + ":" + port
-		lis, err := net.Listen("tcp", addr)
-		if err != nil {
-			return nil, errors.Join(
-				fmt.Errorf("binding address %s for Prometheus exporter: %w", addr, err),
-				reader.Shutdown(ctx),
-			)
-		}
-
-		go func() {
-			if err := server.Serve(lis); err != nil && err != http.ErrServerClosed {
-				otel.Handle(fmt.Errorf("the Prometheus HTTP server exited unexpectedly: %w", err))
-			}
-		}()
-
-		return readerWithServer{lis.Addr(), reader, &server}, nil
+		return newPeriodicReader(ctx, r), nil
 	})
+	RegisterMetricReader("prometheus", metricConfig{}.newPrometheusReader)
 }
 
-type readerWithServer struct {
-	addr net.Addr
-	metric.Reader
-	server *http.Server
-}
-
-func (rws readerWithServer) Shutdown(ctx context.Context) error {
-	return errors.Join(
-		rws.Reader.Shutdown(ctx),
-		rws.server.Shutdown(ctx),
-	)
+// newPeriodicReader returns a periodic reader exporting with exp, configured
+// with the environment variables and instrumented with the exporter metrics
+// of ctx, if any.
+func newPeriodicReader(ctx context.Context, exp metric.Exporter) metric.Reader {
+	return metric.NewPeriodicReader(instrumentMetricExporter(ctx, exp), periodicReaderOptions()...)
 }
 
 func getenv(key, fallback string) string {
@@ -181,3 +239,111 @@ func getenv(key, fallback string) string {
 	}
 	return result
 }
+
+// Environment variables of the periodic readers and of the OTLP exporter, see
+// https://opentelemetry.io/docs/specs/otel/configuration/sdk-environment-variables/#periodic-exporting-metricreader
+const (
+	otelMetricExportIntervalEnvKey                     = "OTEL_METRIC_EXPORT_INTERVAL"
+	otelMetricExportTimeoutEnvKey                      = "OTEL_METRIC_EXPORT_TIMEOUT"
+	otelExporterOTLPMetricsTemporalityPreferenceEnvKey = "OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE"
+	otelExporterOTLPMetricsHistogramAggregationEnvKey  = "OTEL_EXPORTER_OTLP_METRICS_DEFAULT_HISTOGRAM_AGGREGATION"
+)
+
+// periodicReaderOptions returns the periodic reader options defined by the
+// OTEL_METRIC_EXPORT_INTERVAL and OTEL_METRIC_EXPORT_TIMEOUT environment
+// variables. Invalid values are reported with otel.Handle and ignored.
+func periodicReaderOptions() []metric.PeriodicReaderOption {
+	var opts []metric.PeriodicReaderOption
+	if d, ok := envMilliseconds(otelMetricExportIntervalEnvKey); ok {
+		opts = append(opts, metric.WithInterval(d))
+	}
+	if d, ok := envMilliseconds(otelMetricExportTimeoutEnvKey); ok {
+		opts = append(opts, metric.WithTimeout(d))
+	}
+	return opts
+}
+
+// envMilliseconds returns the positive duration in milliseconds of the
+// environment variable key. Invalid values are reported with otel.Handle.
+func envMilliseconds(key string) (time.Duration, bool) {
+	v := os.Getenv(key)
+	if v == "" {
+		return 0, false
+	}
+	ms, err := strconv.Atoi(v)
+	if err != nil {
+		otel.Handle(fmt.Errorf("invalid %s value %q, using the default: %w", key, v, err))
+		return 0, false
+	}
+	if ms <= 0 {
+		otel.Handle(fmt.Errorf("invalid %s value %q, using the default: must be positive", key, v))
+		return 0, false
+	}
+	return time.Duration(ms) * time.Millisecond, true
+}
+
+// otlpMetricsTemporalitySelector returns the TemporalitySelector defined by
+// the OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE environment variable,
+// or nil if it is unset. Invalid values are reported with otel.Handle and
+// ignored.
+func otlpMetricsTemporalitySelector() metric.TemporalitySelector {
+	v := os.Getenv(otelExporterOTLPMetricsTemporalityPreferenceEnvKey)
+	switch strings.ToLower(v) {
+	case "":
+		return nil
+	case "cumulative":
+		return metric.DefaultTemporalitySelector
+	case "delta":
+		return deltaTemporality
+	case "lowmemory":
+		return lowMemoryTemporality
+	default:
+		otel.Handle(fmt.Errorf("unsupported %s value %q, using the default", otelExporterOTLPMetricsTemporalityPreferenceEnvKey, v))
+		return nil
+	}
+}
+
+func deltaTemporality(k metric.InstrumentKind) metricdata.Temporality {
+	switch k {
+	case metric.InstrumentKindCounter, metric.InstrumentKindHistogram, metric.InstrumentKindObservableCounter:
+		return metricdata.DeltaTemporality
+	default:
+		return metricdata.CumulativeTemporality
+	}
+}
+
+func lowMemoryTemporality(k metric.InstrumentKind) metricdata.Temporality {
+	switch k {
+	case metric.InstrumentKindCounter, metric.InstrumentKindHistogram:
+		return metricdata.DeltaTemporality
+	default:
+		return metricdata.CumulativeTemporality
+	}
+}
+
+// otlpMetricsAggregationSelector returns the AggregationSelector defined by
+// the OTEL_EXPORTER_OTLP_METRICS_DEFAULT_HISTOGRAM_AGGREGATION environment
+// variable, or nil if it is unset. Invalid values are reported with
+// otel.Handle and ignored.
+func otlpMetricsAggregationSelector() metric.AggregationSelector {
+	v := os.Getenv(otelExporterOTLPMetricsHistogramAggregationEnvKey)
+	switch strings.ToLower(v) {
+	case "":
+		return nil
+	case "explicit_bucket_histogram":
+		return metric.DefaultAggregationSelector
+	case "base2_exponential_bucket_histogram":
+		return base2ExponentialHistogramAggregation
+	default:
+		otel.Handle(fmt.Errorf("unsupported %s value %q, using the default", otelExporterOTLPMetricsHistogramAggregationEnvKey, v))
+		return nil
+	}
+}
+
+func base2ExponentialHistogramAggregation(k metric.InstrumentKind) metric.Aggregation {
+	if k == metric.InstrumentKindHistogram {
+		// Default values of the specification.
+		return metric.AggregationBase2ExponentialHistogram{MaxSize: 160, MaxScale: 20}
+	}
+	return metric.DefaultAggregationSelector(k)
+}
@@ -22,11 +22,14 @@ import (
 	"reflect"
 	"runtime/debug"
 	"testing"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 )
 
@@ -61,6 +64,7 @@ func TestMetricExporterOTLP(t *testing.T) {
 		{"http/protobuf", "*otlpmetrichttp.Exporter"},
 		{"", "*otlpmetrichttp.Exporter"},
 		{"grpc", "*otlpmetricgrpc.Exporter"},
+		{"grpc/protobuf", "*otlpmetricgrpc.Exporter"},
 	} {
 		t.Run(fmt.Sprintf("protocol=%q", tc.protocol), func(t *testing.T) {
 			t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", tc.protocol)
@@ -79,6 +83,20 @@ func TestMetricExporterOTLP(t *testing.T) {
 	}
 }
 
+func TestMetricExporterOTLPSignalProtocol(t *testing.T) {
+	t.Setenv("OTEL_METRICS_EXPORTER", "otlp")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "grpc")
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_PROTOCOL", "http/protobuf")
+
+	got, err := NewMetricReader(context.Background())
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, got.Shutdown(context.Background()))
+	})
+	exporterType := reflect.Indirect(reflect.ValueOf(got)).FieldByName("exporter").Elem().Type()
+	assert.Equal(t, "*otlpmetrichttp.Exporter", exporterType.String())
+}
+
 func TestMetricExporterOTLPOverInvalidProtocol(t *testing.T) {
 	t.Setenv("OTEL_METRICS_EXPORTER", "otlp")
 	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "invalid-protocol")
@@ -96,6 +114,17 @@ func assertNoOtelHandleErrors(t *testing.T) {
 	}))
 }
 
+func recordOtelHandleErrors(t *testing.T) *[]error {
+	h := otel.GetErrorHandler()
+	t.Cleanup(func() { otel.SetErrorHandler(h) })
+
+	var handled []error
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(cause error) {
+		handled = append(handled, cause)
+	}))
+	return &handled
+}
+
 func TestMetricExporterPrometheus(t *testing.T) {
 	assertNoOtelHandleErrors(t)
 
@@ -130,3 +159,119 @@ func TestMetricExporterPrometheusInvalidPort(t *testing.T) {
 	_, err := NewMetricReader(context.Background())
 	assert.ErrorContains(t, err, "binding")
 }
+
+func TestMetricReadersList(t *testing.T) {
+	handled := recordOtelHandleErrors(t)
+
+	t.Setenv("OTEL_METRICS_EXPORTER", "console,none,prometheus,console")
+	t.Setenv("OTEL_EXPORTER_PROMETHEUS_PORT", "0")
+
+	got, err := NewMetricReaders(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.IsType(t, &metric.PeriodicReader{}, got[0])
+	assert.IsType(t, readerWithServer{}, got[1])
+	for _, r := range got {
+		assert.NoError(t, r.Shutdown(context.Background()))
+	}
+
+	_, err = NewMetricReader(context.Background())
+	assert.ErrorIs(t, err, errMultipleExporters)
+
+	require.Len(t, *handled, 2)
+	assert.ErrorContains(t, (*handled)[0], `OTEL_METRICS_EXPORTER: ignoring the "none" exporter`)
+}
+
+func TestMetricExporterPeriodicReaderEnv(t *testing.T) {
+	handled := recordOtelHandleErrors(t)
+
+	for _, exporter := range []string{"otlp", "console"} {
+		t.Run(exporter, func(t *testing.T) {
+			t.Setenv("OTEL_METRICS_EXPORTER", exporter)
+			t.Setenv("OTEL_METRIC_EXPORT_INTERVAL", "1500")
+			t.Setenv("OTEL_METRIC_EXPORT_TIMEOUT", "250")
+
+			got, err := NewMetricReader(context.Background())
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				assert.NoError(t, got.Shutdown(context.Background()))
+			})
+
+			// Implementation detail hack. This may break when bumping the SDK as it uses unexported API.
+			reader := reflect.Indirect(reflect.ValueOf(got))
+			assert.Equal(t, 1500*time.Millisecond, time.Duration(reader.FieldByName("interval").Int()))
+			assert.Equal(t, 250*time.Millisecond, time.Duration(reader.FieldByName("timeout").Int()))
+		})
+	}
+	assert.Empty(t, *handled)
+}
+
+func TestPeriodicReaderOptionsInvalid(t *testing.T) {
+	handled := recordOtelHandleErrors(t)
+	t.Setenv("OTEL_METRIC_EXPORT_INTERVAL", "1m")
+	t.Setenv("OTEL_METRIC_EXPORT_TIMEOUT", "-1")
+
+	assert.Empty(t, periodicReaderOptions())
+	require.Len(t, *handled, 2)
+	assert.ErrorContains(t, (*handled)[0], `invalid OTEL_METRIC_EXPORT_INTERVAL value "1m"`)
+	assert.ErrorContains(t, (*handled)[1], `invalid OTEL_METRIC_EXPORT_TIMEOUT value "-1", using the default: must be positive`)
+}
+
+func TestOTLPMetricsTemporalitySelector(t *testing.T) {
+	kinds := []metric.InstrumentKind{
+		metric.InstrumentKindCounter,
+		metric.InstrumentKindUpDownCounter,
+		metric.InstrumentKindHistogram,
+		metric.InstrumentKindGauge,
+		metric.InstrumentKindObservableCounter,
+		metric.InstrumentKindObservableUpDownCounter,
+		metric.InstrumentKindObservableGauge,
+	}
+	c, d := metricdata.CumulativeTemporality, metricdata.DeltaTemporality
+	for _, tc := range []struct {
+		value string
+		want  []metricdata.Temporality
+	}{
+		{"cumulative", []metricdata.Temporality{c, c, c, c, c, c, c}},
+		{"Delta", []metricdata.Temporality{d, c, d, c, d, c, c}},
+		{"lowmemory", []metricdata.Temporality{d, c, d, c, c, c, c}},
+	} {
+		t.Run(tc.value, func(t *testing.T) {
+			t.Setenv("OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE", tc.value)
+			selector := otlpMetricsTemporalitySelector()
+			require.NotNil(t, selector)
+			var got []metricdata.Temporality
+			for _, k := range kinds {
+				got = append(got, selector(k))
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	handled := recordOtelHandleErrors(t)
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE", "")
+	assert.Nil(t, otlpMetricsTemporalitySelector())
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE", "sometimes")
+	assert.Nil(t, otlpMetricsTemporalitySelector())
+	require.Len(t, *handled, 1)
+	assert.ErrorContains(t, (*handled)[0], `unsupported OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE value "sometimes"`)
+}
+
+func TestOTLPMetricsAggregationSelector(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_DEFAULT_HISTOGRAM_AGGREGATION", "base2_exponential_bucket_histogram")
+	selector := otlpMetricsAggregationSelector()
+	require.NotNil(t, selector)
+	assert.Equal(t, metric.AggregationBase2ExponentialHistogram{MaxSize: 160, MaxScale: 20}, selector(metric.InstrumentKindHistogram))
+	assert.Equal(t, metric.AggregationSum{}, selector(metric.InstrumentKindCounter))
+
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_DEFAULT_HISTOGRAM_AGGREGATION", "explicit_bucket_histogram")
+	selector = otlpMetricsAggregationSelector()
+	require.NotNil(t, selector)
+	assert.IsType(t, metric.AggregationExplicitBucketHistogram{}, selector(metric.InstrumentKindHistogram))
+
+	handled := recordOtelHandleErrors(t)
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_DEFAULT_HISTOGRAM_AGGREGATION", "linear")
+	assert.Nil(t, otlpMetricsAggregationSelector())
+	require.Len(t, *handled, 1)
+	assert.ErrorContains(t, (*handled)[0], `unsupported OTEL_EXPORTER_OTLP_METRICS_DEFAULT_HISTOGRAM_AGGREGATION value "linear"`)
+}
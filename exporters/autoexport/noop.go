@@ -17,6 +17,7 @@ package autoexport // import "go.opentelemetry.io/contrib/exporters/autoexport"
 import (
 	"context"
 
+	"go.opentelemetry.io/otel/sdk/log"
 	"go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/trace"
 )
@@ -57,3 +58,30 @@ func IsNoneMetricReader(e metric.Reader) bool {
 	_, ok := e.(noopMetricReader)
 	return ok
 }
+
+// noopLogExporter is an implementation of log.Exporter that performs no operations.
+type noopLogExporter struct{}
+
+var _ log.Exporter = noopLogExporter{}
+
+// Export is part of log.Exporter interface.
+func (e noopLogExporter) Export(ctx context.Context, records []log.Record) error {
+	return nil
+}
+
+// Shutdown is part of log.Exporter interface.
+func (e noopLogExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// ForceFlush is part of log.Exporter interface.
+func (e noopLogExporter) ForceFlush(ctx context.Context) error {
+	return nil
+}
+
+// IsNoneLogExporter returns true for the exporter returned by [NewLogExporter]
+// when OTEL_LOGS_EXPORTER environment variable is set to "none".
+func IsNoneLogExporter(e log.Exporter) bool {
+	_, ok := e.(noopLogExporter)
+	return ok
+}
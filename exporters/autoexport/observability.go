@@ -0,0 +1,216 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoexport // import "go.opentelemetry.io/contrib/exporters/autoexport"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// WithSpanExporterMeterProvider records metrics about the exports of the
+// span exporters created for the names listed in OTEL_TRACES_EXPORTER with
+// mp, see [NewSpanExporter].
+func WithSpanExporterMeterProvider(mp metric.MeterProvider) SpanOption {
+	return optionFunc[trace.SpanExporter](func(cfg *config[trace.SpanExporter]) {
+		cfg.meterProvider = mp
+	})
+}
+
+// WithMetricReaderMeterProvider records metrics about the exports of the
+// periodic metric readers created for the names listed in
+// OTEL_METRICS_EXPORTER with mp, see [NewMetricReader].
+func WithMetricReaderMeterProvider(mp metric.MeterProvider) MetricOption {
+	return optionFunc[sdkmetric.Reader](func(cfg *config[sdkmetric.Reader]) {
+		cfg.meterProvider = mp
+	})
+}
+
+// exporterMetrics records the exports of an exporter.
+type exporterMetrics struct {
+	exported metric.Int64Counter
+	failed   metric.Int64Counter
+	duration metric.Float64Histogram
+	attrs    attribute.Set
+}
+
+type exporterMetricsKey struct{}
+
+// withExporterMetrics returns a copy of ctx carrying the exporterMetrics of
+// the exporter name of the signal, recorded with mp. It is used by the
+// factories to instrument the exporters they create.
+func withExporterMetrics(ctx context.Context, mp metric.MeterProvider, signal, name, item string) context.Context {
+	meter := mp.Meter(meterName)
+	m := &exporterMetrics{
+		attrs: attribute.NewSet(
+			attribute.String("exporter", name),
+			attribute.String("signal", signal),
+		),
+	}
+	var err error
+	m.exported, err = meter.Int64Counter(
+		fmt.Sprintf("otel.sdk.exporter.%s.exported", item),
+		metric.WithDescription(fmt.Sprintf("Number of %ss successfully exported.", strings.ReplaceAll(item, "_", " "))),
+		metric.WithUnit(fmt.Sprintf("{%s}", item)),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	m.failed, err = meter.Int64Counter(
+		fmt.Sprintf("otel.sdk.exporter.%s.failed", item),
+		metric.WithDescription(fmt.Sprintf("Number of %ss that failed to be exported.", strings.ReplaceAll(item, "_", " "))),
+		metric.WithUnit(fmt.Sprintf("{%s}", item)),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	m.duration, err = meter.Float64Histogram(
+		"otel.sdk.exporter.operation.duration",
+		metric.WithDescription("Duration of the export operations."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	return context.WithValue(ctx, exporterMetricsKey{}, m)
+}
+
+func exporterMetricsFrom(ctx context.Context) *exporterMetrics {
+	m, _ := ctx.Value(exporterMetricsKey{}).(*exporterMetrics)
+	return m
+}
+
+// record records an export of n items that started at start and returned
+// err.
+func (m *exporterMetrics) record(ctx context.Context, start time.Time, n int64, err error) {
+	if err == nil {
+		m.exported.Add(ctx, n, metric.WithAttributeSet(m.attrs))
+		m.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributeSet(m.attrs))
+		return
+	}
+	m.failed.Add(ctx, n, metric.WithAttributeSet(m.attrs))
+	attrs := metric.WithAttributeSet(attribute.NewSet(append(m.attrs.ToSlice(), attribute.String("error.type", errorType(err)))...))
+	m.duration.Record(ctx, time.Since(start).Seconds(), attrs)
+}
+
+// errorType returns the low-cardinality error.type attribute value of err.
+func errorType(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	default:
+		return "_OTHER"
+	}
+}
+
+// instrumentSpanExporter wraps exp to record its exports with the
+// exporterMetrics of ctx, if any.
+func instrumentSpanExporter(ctx context.Context, exp trace.SpanExporter) trace.SpanExporter {
+	m := exporterMetricsFrom(ctx)
+	if m == nil || IsNoneSpanExporter(exp) {
+		return exp
+	}
+	if _, ok := exp.(*instrumentedSpanExporter); ok {
+		// Already instrumented by a nested call, e.g. of a factory calling
+		// NewSpanExporter.
+		return exp
+	}
+	return &instrumentedSpanExporter{SpanExporter: exp, metrics: m}
+}
+
+// instrumentedSpanExporter records the exports of a trace.SpanExporter.
+type instrumentedSpanExporter struct {
+	trace.SpanExporter
+	metrics *exporterMetrics
+}
+
+func (e *instrumentedSpanExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	start := time.Now()
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	e.metrics.record(ctx, start, int64(len(spans)), err)
+	return err
+}
+
+// instrumentMetricExporter wraps exp to record its exports with the
+// exporterMetrics of ctx, if any.
+func instrumentMetricExporter(ctx context.Context, exp sdkmetric.Exporter) sdkmetric.Exporter {
+	m := exporterMetricsFrom(ctx)
+	if m == nil {
+		return exp
+	}
+	if _, ok := exp.(*instrumentedMetricExporter); ok {
+		return exp
+	}
+	return &instrumentedMetricExporter{Exporter: exp, metrics: m}
+}
+
+// instrumentedMetricExporter records the exports of a metric.Exporter.
+type instrumentedMetricExporter struct {
+	sdkmetric.Exporter
+	metrics *exporterMetrics
+}
+
+func (e *instrumentedMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	start := time.Now()
+	err := e.Exporter.Export(ctx, rm)
+	e.metrics.record(ctx, start, dataPoints(rm), err)
+	return err
+}
+
+// dataPoints returns the number of data points of rm. The data points of the
+// metrics recorded by autoexport are not counted so that a MeterProvider
+// using the instrumented reader does not count its own measurements.
+func dataPoints(rm *metricdata.ResourceMetrics) int64 {
+	var n int
+	for _, sm := range rm.ScopeMetrics {
+		if sm.Scope.Name == meterName {
+			continue
+		}
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Gauge[int64]:
+				n += len(data.DataPoints)
+			case metricdata.Gauge[float64]:
+				n += len(data.DataPoints)
+			case metricdata.Sum[int64]:
+				n += len(data.DataPoints)
+			case metricdata.Sum[float64]:
+				n += len(data.DataPoints)
+			case metricdata.Histogram[int64]:
+				n += len(data.DataPoints)
+			case metricdata.Histogram[float64]:
+				n += len(data.DataPoints)
+			case metricdata.ExponentialHistogram[int64]:
+				n += len(data.DataPoints)
+			case metricdata.ExponentialHistogram[float64]:
+				n += len(data.DataPoints)
+			case metricdata.Summary:
+				n += len(data.DataPoints)
+			}
+		}
+	}
+	return int64(n)
+}
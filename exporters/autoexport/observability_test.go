@@ -0,0 +1,201 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoexport // import "go.opentelemetry.io/contrib/exporters/autoexport"
+
+import (
+	"context"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func collectMetrics(t *testing.T, r metric.Reader) map[string]metricdata.Metrics {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, r.Collect(context.Background(), &rm))
+	got := make(map[string]metricdata.Metrics)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			got[m.Name] = m
+		}
+	}
+	return got
+}
+
+func TestSpanExporterMeterProvider(t *testing.T) {
+	t.Setenv("OTEL_TRACES_EXPORTER", "otlp/file")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_FILE_PATH", filepath.Join(t.TempDir(), "traces.jsonl"))
+
+	reader := metric.NewManualReader()
+	mp := metric.NewMeterProvider(metric.WithReader(reader))
+	exp, err := NewSpanExporter(context.Background(), WithSpanExporterMeterProvider(mp))
+	require.NoError(t, err)
+
+	require.NoError(t, exp.ExportSpans(context.Background(), spanStubs("a", "b")))
+	require.NoError(t, exp.Shutdown(context.Background()))
+	assert.Error(t, exp.ExportSpans(context.Background(), spanStubs("c")))
+
+	attrs := attribute.NewSet(attribute.String("exporter", "otlp/file"), attribute.String("signal", "traces"))
+	got := collectMetrics(t, reader)
+	metricdatatest.AssertEqual(t, metricdata.Metrics{
+		Name:        "otel.sdk.exporter.span.exported",
+		Description: "Number of spans successfully exported.",
+		Unit:        "{span}",
+		Data: metricdata.Sum[int64]{
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+			DataPoints:  []metricdata.DataPoint[int64]{{Attributes: attrs, Value: 2}},
+		},
+	}, got["otel.sdk.exporter.span.exported"], metricdatatest.IgnoreTimestamp())
+	metricdatatest.AssertEqual(t, metricdata.Metrics{
+		Name:        "otel.sdk.exporter.span.failed",
+		Description: "Number of spans that failed to be exported.",
+		Unit:        "{span}",
+		Data: metricdata.Sum[int64]{
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+			DataPoints:  []metricdata.DataPoint[int64]{{Attributes: attrs, Value: 1}},
+		},
+	}, got["otel.sdk.exporter.span.failed"], metricdatatest.IgnoreTimestamp())
+
+	duration, ok := got["otel.sdk.exporter.operation.duration"].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, duration.DataPoints, 2)
+	var withError bool
+	for _, dp := range duration.DataPoints {
+		assert.Equal(t, uint64(1), dp.Count)
+		if v, ok := dp.Attributes.Value("error.type"); ok {
+			withError = true
+			assert.Equal(t, "_OTHER", v.AsString())
+		}
+	}
+	assert.True(t, withError, "missing error.type attribute")
+}
+
+func TestSpanExporterMeterProviderNone(t *testing.T) {
+	t.Setenv("OTEL_TRACES_EXPORTER", "none")
+
+	mp := metric.NewMeterProvider()
+	exp, err := NewSpanExporter(context.Background(), WithSpanExporterMeterProvider(mp))
+	require.NoError(t, err)
+	assert.True(t, IsNoneSpanExporter(exp))
+}
+
+func TestMetricReaderMeterProvider(t *testing.T) {
+	t.Setenv("OTEL_METRICS_EXPORTER", "otlp/file")
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_FILE_PATH", filepath.Join(t.TempDir(), "metrics.jsonl"))
+
+	selfReader := metric.NewManualReader()
+	selfMP := metric.NewMeterProvider(metric.WithReader(selfReader))
+	r, err := NewMetricReader(context.Background(), WithMetricReaderMeterProvider(selfMP))
+	require.NoError(t, err)
+
+	mp := metric.NewMeterProvider(metric.WithReader(r))
+	counter, err := mp.Meter("scope").Int64Counter("counter")
+	require.NoError(t, err)
+	counter.Add(context.Background(), 1, otelmetric.WithAttributes(attribute.Int("n", 1)))
+	counter.Add(context.Background(), 1, otelmetric.WithAttributes(attribute.Int("n", 2)))
+	require.NoError(t, mp.ForceFlush(context.Background()))
+	require.NoError(t, mp.Shutdown(context.Background()))
+
+	attrs := attribute.NewSet(attribute.String("exporter", "otlp/file"), attribute.String("signal", "metrics"))
+	exported := collectMetrics(t, selfReader)["otel.sdk.exporter.metric_data_point.exported"]
+	metricdatatest.AssertAggregationsEqual(t, metricdata.Sum[int64]{
+		Temporality: metricdata.CumulativeTemporality,
+		IsMonotonic: true,
+		// Exported by ForceFlush and Shutdown.
+		DataPoints: []metricdata.DataPoint[int64]{{Attributes: attrs, Value: 4}},
+	}, exported.Data, metricdatatest.IgnoreTimestamp())
+}
+
+// globalMeterProviderSet reports whether a test already set the global
+// MeterProvider, which delegates to the first one set only.
+var globalMeterProviderSet atomic.Bool
+
+func TestMetricReaderMeterProviderSelfFed(t *testing.T) {
+	if globalMeterProviderSet.Swap(true) {
+		t.Skip("the global MeterProvider already delegates to the one set by a previous run")
+	}
+	path := filepath.Join(t.TempDir(), "metrics.jsonl")
+	t.Setenv("OTEL_METRICS_EXPORTER", "otlp/file")
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_FILE_PATH", path)
+
+	global := otel.GetMeterProvider()
+	t.Cleanup(func() { otel.SetMeterProvider(global) })
+
+	// The reader feeds the MeterProvider recording its exports.
+	r, err := NewMetricReader(context.Background(), WithMetricReaderMeterProvider(otel.GetMeterProvider()))
+	require.NoError(t, err)
+	mp := metric.NewMeterProvider(metric.WithReader(r))
+	otel.SetMeterProvider(mp)
+
+	counter, err := mp.Meter("scope").Int64Counter("counter")
+	require.NoError(t, err)
+	counter.Add(context.Background(), 1)
+	require.NoError(t, mp.ForceFlush(context.Background()))
+	require.NoError(t, mp.ForceFlush(context.Background()))
+	require.NoError(t, mp.Shutdown(context.Background()))
+
+	got := &recordingMetricExporter{}
+	require.NoError(t, ReplayMetrics(context.Background(), path, got))
+	require.Len(t, got.got, 3)
+
+	// The exports are recorded after the data points are collected, the
+	// first export does not contain them.
+	var last int64
+	for _, rm := range got.got[1:] {
+		var exported int64
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				if m.Name != "otel.sdk.exporter.metric_data_point.exported" {
+					continue
+				}
+				sum, ok := m.Data.(metricdata.Sum[int64])
+				require.True(t, ok)
+				require.Len(t, sum.DataPoints, 1)
+				exported = sum.DataPoints[0].Value
+			}
+		}
+		assert.Greater(t, exported, last)
+		last = exported
+	}
+}
+
+func TestDataPointsIgnoresOwnMetrics(t *testing.T) {
+	sum := metricdata.Sum[int64]{DataPoints: []metricdata.DataPoint[int64]{{Value: 1}, {Value: 2}}}
+	rm := &metricdata.ResourceMetrics{
+		ScopeMetrics: []metricdata.ScopeMetrics{
+			{
+				Scope:   instrumentation.Scope{Name: "scope"},
+				Metrics: []metricdata.Metrics{{Name: "sum", Data: sum}, {Name: "gauge", Data: metricdata.Gauge[float64]{DataPoints: []metricdata.DataPoint[float64]{{Value: 1}}}}},
+			},
+			{
+				Scope:   instrumentation.Scope{Name: "go.opentelemetry.io/contrib/exporters/autoexport"},
+				Metrics: []metricdata.Metrics{{Name: "otel.sdk.exporter.metric_data_point.exported", Data: sum}},
+			},
+		},
+	}
+	assert.Equal(t, int64(3), dataPoints(rm))
+}
@@ -0,0 +1,215 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoexport // import "go.opentelemetry.io/contrib/exporters/autoexport"
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"go.opentelemetry.io/otel"
+	promexporter "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/sdk/metric"
+)
+
+// Environment variables of the "prometheus" metric reader. The host and port
+// are specified at https://opentelemetry.io/docs/specs/otel/configuration/sdk-environment-variables/#prometheus-exporter
+const (
+	otelExporterPrometheusHostEnvKey        = "OTEL_EXPORTER_PROMETHEUS_HOST"
+	otelExporterPrometheusPortEnvKey        = "OTEL_EXPORTER_PROMETHEUS_PORT"
+	otelExporterPrometheusPathEnvKey        = "OTEL_EXPORTER_PROMETHEUS_PATH"
+	otelExporterPrometheusTLSCertFileEnvKey = "OTEL_EXPORTER_PROMETHEUS_TLS_CERT_FILE"
+	otelExporterPrometheusTLSKeyFileEnvKey  = "OTEL_EXPORTER_PROMETHEUS_TLS_KEY_FILE"
+)
+
+// WithPrometheusServeMux registers the metrics endpoint of the "prometheus"
+// metric reader on mux instead of starting an HTTP server. The
+// OTEL_EXPORTER_PROMETHEUS_HOST, OTEL_EXPORTER_PROMETHEUS_PORT and TLS
+// environment variables are then ignored. An error is returned if the path
+// of the endpoint is already registered on mux.
+//
+// A ServeMux cannot unregister a handler: the endpoint stays registered on
+// mux after the reader is shut down, serving no OpenTelemetry metrics, and
+// another "prometheus" reader cannot be created with the same mux and path.
+func WithPrometheusServeMux(mux *http.ServeMux) MetricOption {
+	return metricOptionFunc(func(cfg *metricConfig) {
+		cfg.prometheusMux = mux
+	})
+}
+
+// WithPrometheusDefaultRegistry serves the metrics of the default Prometheus
+// registry, [github.com/prometheus/client_golang/prometheus.DefaultGatherer],
+// with the metrics of the "prometheus" metric reader.
+func WithPrometheusDefaultRegistry() MetricOption {
+	return metricOptionFunc(func(cfg *metricConfig) {
+		cfg.prometheusDefaultRegistry = true
+	})
+}
+
+// PrometheusServerAddr returns the address the HTTP server of the
+// "prometheus" metric reader r listens on, or nil if r is not such a reader.
+// It can be used to discover the port chosen when
+// OTEL_EXPORTER_PROMETHEUS_PORT is 0.
+func PrometheusServerAddr(r metric.Reader) net.Addr {
+	rws, ok := r.(readerWithServer)
+	if !ok {
+		return nil
+	}
+	return rws.addr
+}
+
+// newPrometheusReader returns the "prometheus" metric reader configured by
+// cfg and the environment variables.
+func (cfg metricConfig) newPrometheusReader(ctx context.Context) (metric.Reader, error) {
+	// create an isolated registry instead of using the global registry --
+	// the user might not want to mix OTel with non-OTel metrics
+	reg := prometheus.NewRegistry()
+	var gatherer prometheus.Gatherer = reg
+	if cfg.prometheusDefaultRegistry {
+		gatherer = prometheus.Gatherers{reg, prometheus.DefaultGatherer}
+	}
+
+	endpoint := getenv(otelExporterPrometheusPathEnvKey, "/metrics")
+	if !validPrometheusPath(endpoint) {
+		return nil, fmt.Errorf("invalid %s value %q: must be a clean path starting with \"/\", without spaces or braces", otelExporterPrometheusPathEnvKey, endpoint)
+	}
+
+	reader, err := promexporter.New(promexporter.WithRegisterer(reg))
+	if err != nil {
+		return nil, err
+	}
+	handler := promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{Registry: reg})
+
+	if cfg.prometheusMux != nil {
+		if registered(cfg.prometheusMux, endpoint) {
+			return nil, errors.Join(
+				fmt.Errorf("registering the Prometheus exporter handler: %q is already registered", endpoint),
+				reader.Shutdown(ctx),
+			)
+		}
+		cfg.prometheusMux.Handle(endpoint, handler)
+		return reader, nil
+	}
+
+	tlsConfig, err := prometheusTLSConfig()
+	if err != nil {
+		return nil, errors.Join(err, reader.Shutdown(ctx))
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(endpoint, handler)
+	server := http.Server{
+		// Timeouts are necessary to make a server resilent to attacks, but ListenAndServe doesn't set any.
+		// We use values from this example: https://blog.cloudflare.com/exposing-go-on-the-internet/#:~:text=There%20are%20three%20main%20timeouts
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  120 * time.Second,
+		Handler:      mux,
+		TLSConfig:    tlsConfig,
+	}
+
+	host := getenv(otelExporterPrometheusHostEnvKey, "localhost")
+	port := getenv(otelExporterPrometheusPortEnvKey, "9464")
+	addr := net.JoinHostPort(host, port)
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("binding address %s for Prometheus exporter: %w", addr, err),
+			reader.Shutdown(ctx),
+		)
+	}
+
+	go func() {
+		var err error
+		if tlsConfig != nil {
+			// The certificate is set in the TLS configuration.
+			err = server.ServeTLS(lis, "", "")
+		} else {
+			err = server.Serve(lis)
+		}
+		if err != nil && err != http.ErrServerClosed {
+			otel.Handle(fmt.Errorf("the Prometheus HTTP server exited unexpectedly: %w", err))
+		}
+	}()
+
+	return readerWithServer{lis.Addr(), reader, &server}, nil
+}
+
+// validPrometheusPath returns whether p can be registered as is on a
+// ServeMux: an absolute and clean path, with an optional trailing slash, that
+// holds no method, host or wildcard of a ServeMux pattern.
+func validPrometheusPath(p string) bool {
+	if p == "" || p[0] != '/' || strings.ContainsAny(p, " \t{}") {
+		return false
+	}
+	clean := path.Clean(p)
+	if p != "/" && strings.HasSuffix(p, "/") {
+		clean += "/"
+	}
+	return clean == p
+}
+
+// registered returns whether the path p is already registered on mux, in which
+// case mux.Handle would panic.
+func registered(mux *http.ServeMux, p string) bool {
+	_, pattern := mux.Handler(&http.Request{Method: http.MethodGet, URL: &url.URL{Path: p}})
+	return pattern == p
+}
+
+// prometheusTLSConfig returns the TLS configuration of the Prometheus server
+// defined by the OTEL_EXPORTER_PROMETHEUS_TLS_CERT_FILE and
+// OTEL_EXPORTER_PROMETHEUS_TLS_KEY_FILE environment variables, or nil if they
+// are unset.
+func prometheusTLSConfig() (*tls.Config, error) {
+	certFile := getenv(otelExporterPrometheusTLSCertFileEnvKey, "")
+	keyFile := getenv(otelExporterPrometheusTLSKeyFileEnvKey, "")
+	if certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("%s and %s must be set together", otelExporterPrometheusTLSCertFileEnvKey, otelExporterPrometheusTLSKeyFileEnvKey)
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading the Prometheus exporter TLS certificate: %w", err)
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+type readerWithServer struct {
+	addr net.Addr
+	metric.Reader
+	server *http.Server
+}
+
+func (rws readerWithServer) Shutdown(ctx context.Context) error {
+	return errors.Join(
+		rws.Reader.Shutdown(ctx),
+		rws.server.Shutdown(ctx),
+	)
+}
@@ -0,0 +1,251 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoexport // import "go.opentelemetry.io/contrib/exporters/autoexport"
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.opentelemetry.io/otel/sdk/metric"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getBody(t *testing.T, client *http.Client, url string) (int, string) {
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestMetricExporterPrometheusPath(t *testing.T) {
+	assertNoOtelHandleErrors(t)
+
+	t.Setenv("OTEL_METRICS_EXPORTER", "prometheus")
+	t.Setenv("OTEL_EXPORTER_PROMETHEUS_PORT", "0")
+	t.Setenv("OTEL_EXPORTER_PROMETHEUS_PATH", "/custom")
+
+	r, err := NewMetricReader(context.Background())
+	require.NoError(t, err)
+	mp := metric.NewMeterProvider(metric.WithReader(r))
+	t.Cleanup(func() { assert.NoError(t, mp.Shutdown(context.Background())) })
+
+	addr := PrometheusServerAddr(r)
+	require.NotNil(t, addr)
+	code, body := getBody(t, http.DefaultClient, fmt.Sprintf("http://%s/custom", addr))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, "# HELP")
+	code, _ = getBody(t, http.DefaultClient, fmt.Sprintf("http://%s/metrics", addr))
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestMetricExporterPrometheusInvalidPath(t *testing.T) {
+	t.Setenv("OTEL_METRICS_EXPORTER", "prometheus")
+
+	for _, path := range []string{"metrics", "/a b", "/{name}", "/a/../metrics", "//metrics"} {
+		t.Run(path, func(t *testing.T) {
+			t.Setenv("OTEL_EXPORTER_PROMETHEUS_PATH", path)
+
+			_, err := NewMetricReader(context.Background(), WithPrometheusServeMux(http.NewServeMux()))
+			assert.ErrorContains(t, err, fmt.Sprintf("invalid OTEL_EXPORTER_PROMETHEUS_PATH value %q", path))
+		})
+	}
+}
+
+func TestMetricExporterPrometheusIPv6Host(t *testing.T) {
+	lis, err := net.Listen("tcp", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 loopback unavailable: %v", err)
+	}
+	require.NoError(t, lis.Close())
+	assertNoOtelHandleErrors(t)
+
+	t.Setenv("OTEL_METRICS_EXPORTER", "prometheus")
+	t.Setenv("OTEL_EXPORTER_PROMETHEUS_HOST", "::1")
+	t.Setenv("OTEL_EXPORTER_PROMETHEUS_PORT", "0")
+
+	r, err := NewMetricReader(context.Background())
+	require.NoError(t, err)
+	mp := metric.NewMeterProvider(metric.WithReader(r))
+	t.Cleanup(func() { assert.NoError(t, mp.Shutdown(context.Background())) })
+
+	addr := PrometheusServerAddr(r)
+	require.NotNil(t, addr)
+	code, _ := getBody(t, http.DefaultClient, fmt.Sprintf("http://%s/metrics", addr))
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestMetricExporterPrometheusServeMux(t *testing.T) {
+	t.Setenv("OTEL_METRICS_EXPORTER", "prometheus")
+	// Ignored as no server is started.
+	t.Setenv("OTEL_EXPORTER_PROMETHEUS_PORT", "invalid-port")
+
+	mux := http.NewServeMux()
+	r, err := NewMetricReader(context.Background(), WithPrometheusServeMux(mux))
+	require.NoError(t, err)
+	assert.Nil(t, PrometheusServerAddr(r))
+	mp := metric.NewMeterProvider(metric.WithReader(r))
+	t.Cleanup(func() { assert.NoError(t, mp.Shutdown(context.Background())) })
+
+	counter, err := mp.Meter("scope").Int64Counter("requests")
+	require.NoError(t, err)
+	counter.Add(context.Background(), 1)
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	code, body := getBody(t, srv.Client(), srv.URL+"/metrics")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, "requests_total")
+	assert.NotContains(t, body, "go_goroutines")
+}
+
+func TestMetricExporterPrometheusServeMuxDuplicatePath(t *testing.T) {
+	t.Setenv("OTEL_METRICS_EXPORTER", "prometheus")
+
+	mux := http.NewServeMux()
+	r, err := NewMetricReader(context.Background(), WithPrometheusServeMux(mux))
+	require.NoError(t, err)
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	// The endpoint stays registered after the reader is shut down.
+	_, err = NewMetricReader(context.Background(), WithPrometheusServeMux(mux))
+	assert.ErrorContains(t, err, `registering the Prometheus exporter handler: "/metrics" is already registered`)
+
+	// A handler registered by the application is not replaced either.
+	t.Setenv("OTEL_EXPORTER_PROMETHEUS_PATH", "/custom/")
+	mux.HandleFunc("/custom/", func(http.ResponseWriter, *http.Request) {})
+	_, err = NewMetricReader(context.Background(), WithPrometheusServeMux(mux))
+	assert.ErrorContains(t, err, `registering the Prometheus exporter handler: "/custom/" is already registered`)
+}
+
+func TestMetricExporterPrometheusDefaultRegistry(t *testing.T) {
+	t.Setenv("OTEL_METRICS_EXPORTER", "prometheus")
+
+	c := prometheus.NewCounter(prometheus.CounterOpts{Name: "autoexport_test_default_registry_total"})
+	require.NoError(t, prometheus.Register(c))
+	t.Cleanup(func() { prometheus.Unregister(c) })
+
+	mux := http.NewServeMux()
+	r, err := NewMetricReader(context.Background(), WithPrometheusServeMux(mux), WithPrometheusDefaultRegistry())
+	require.NoError(t, err)
+	mp := metric.NewMeterProvider(metric.WithReader(r))
+	t.Cleanup(func() { assert.NoError(t, mp.Shutdown(context.Background())) })
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	_, body := getBody(t, srv.Client(), srv.URL+"/metrics")
+	assert.Contains(t, body, "autoexport_test_default_registry_total")
+	assert.Contains(t, body, "target_info")
+}
+
+// writeCertificate writes a self-signed certificate for 127.0.0.1 and its
+// key to dir and returns their paths.
+func writeCertificate(t *testing.T, dir string) (certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "autoexport"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func TestMetricExporterPrometheusTLS(t *testing.T) {
+	assertNoOtelHandleErrors(t)
+
+	certFile, keyFile := writeCertificate(t, t.TempDir())
+	t.Setenv("OTEL_METRICS_EXPORTER", "prometheus")
+	t.Setenv("OTEL_EXPORTER_PROMETHEUS_HOST", "127.0.0.1")
+	t.Setenv("OTEL_EXPORTER_PROMETHEUS_PORT", "0")
+	t.Setenv("OTEL_EXPORTER_PROMETHEUS_TLS_CERT_FILE", certFile)
+	t.Setenv("OTEL_EXPORTER_PROMETHEUS_TLS_KEY_FILE", keyFile)
+
+	r, err := NewMetricReader(context.Background())
+	require.NoError(t, err)
+	mp := metric.NewMeterProvider(metric.WithReader(r))
+	t.Cleanup(func() { assert.NoError(t, mp.Shutdown(context.Background())) })
+
+	certPEM, err := os.ReadFile(certFile)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(certPEM))
+	transport := &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}}
+	t.Cleanup(transport.CloseIdleConnections)
+	client := &http.Client{Transport: transport}
+
+	code, body := getBody(t, client, fmt.Sprintf("https://%s/metrics", PrometheusServerAddr(r)))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, "# HELP")
+}
+
+func TestMetricExporterPrometheusInvalidTLS(t *testing.T) {
+	t.Setenv("OTEL_METRICS_EXPORTER", "prometheus")
+	t.Setenv("OTEL_EXPORTER_PROMETHEUS_PORT", "0")
+
+	certFile, keyFile := writeCertificate(t, t.TempDir())
+	for _, tc := range []struct {
+		name, certFile, keyFile, err string
+	}{
+		{"cert only", certFile, "", "must be set together"},
+		{"key only", "", keyFile, "must be set together"},
+		{"missing files", filepath.Join(t.TempDir(), "cert.pem"), keyFile, "loading the Prometheus exporter TLS certificate"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("OTEL_EXPORTER_PROMETHEUS_TLS_CERT_FILE", tc.certFile)
+			t.Setenv("OTEL_EXPORTER_PROMETHEUS_TLS_KEY_FILE", tc.keyFile)
+			_, err := NewMetricReader(context.Background())
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestPrometheusServerAddrOtherReader(t *testing.T) {
+	assert.Nil(t, PrometheusServerAddr(metric.NewManualReader()))
+}
@@ -0,0 +1,474 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoexport // import "go.opentelemetry.io/contrib/exporters/autoexport"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"go.opentelemetry.io/contrib/exporters/autoexport/internal/transform"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/sdk/trace"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// Environment variables of the on-disk retry queue.
+const (
+	otelExporterQueueDirEnvKey     = "OTEL_EXPORTER_QUEUE_DIR"
+	otelExporterQueueMaxSizeEnvKey = "OTEL_EXPORTER_QUEUE_MAX_SIZE"
+	otelExporterQueueMaxAgeEnvKey  = "OTEL_EXPORTER_QUEUE_MAX_AGE"
+)
+
+const (
+	defaultQueueMaxSize       = 64 << 20 // 64 MiB
+	defaultQueueMaxAge        = 24 * time.Hour
+	defaultQueueRetryInterval = 5 * time.Second
+
+	// queueBatchExt is the extension of the files of the queued batches.
+	queueBatchExt = ".pb"
+
+	meterName = "go.opentelemetry.io/contrib/exporters/autoexport"
+)
+
+// Reasons of the dropped spans of the queue.
+const (
+	queueDropMaxSize = "max_size"
+	queueDropMaxAge  = "max_age"
+	queueDropInvalid = "invalid"
+)
+
+// queuedBatch is a batch of spans stored in a file of the queue directory.
+type queuedBatch struct {
+	path    string
+	size    int64
+	spans   int64
+	created time.Time
+}
+
+// queueSpanExporter is a trace.SpanExporter that stores the batches its
+// exporter fails to export in a directory, and exports them in order once
+// the exporter succeeds again.
+type queueSpanExporter struct {
+	exporter trace.SpanExporter
+	dir      string
+	maxSize  int64
+	maxAge   time.Duration
+	attrs    attribute.Set
+
+	// mu serializes the exports to keep the batches in order. It is held
+	// for the export of a single batch at a time.
+	mu      sync.Mutex
+	batches []queuedBatch
+	size    int64
+	next    uint64
+
+	queued       atomic.Int64
+	dropped      metric.Int64Counter
+	registration metric.Registration
+
+	// retryCtx is the context of the exports of the background goroutine.
+	// It is canceled if the shutdown context is done before the export in
+	// progress returns.
+	retryCtx     context.Context
+	cancelRetry  context.CancelFunc
+	stopOnce     sync.Once
+	shutdownOnce sync.Once
+	wake         chan struct{}
+	stop         chan struct{}
+	done         chan struct{}
+}
+
+var _ trace.SpanExporter = (*queueSpanExporter)(nil)
+
+// withQueueFromEnv wraps the exporter created for the exporter name with an
+// on-disk retry queue if the OTEL_EXPORTER_QUEUE_DIR environment variable is
+// set. Each exporter has its own queue, stored in a subdirectory named after
+// the exporter, and records its metrics with mp, if not nil. exporter is shut
+// down if the queue cannot be created.
+func withQueueFromEnv(ctx context.Context, mp metric.MeterProvider, name string, exporter trace.SpanExporter) (trace.SpanExporter, error) {
+	dir := os.Getenv(otelExporterQueueDirEnvKey)
+	if dir == "" || IsNoneSpanExporter(exporter) {
+		return exporter, nil
+	}
+
+	maxSize := int64(defaultQueueMaxSize)
+	if v := os.Getenv(otelExporterQueueMaxSizeEnvKey); v != "" {
+		size, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || size <= 0 {
+			err = fmt.Errorf("invalid %s value %q: must be a positive number of bytes", otelExporterQueueMaxSizeEnvKey, v)
+			return nil, errors.Join(err, exporter.Shutdown(ctx))
+		}
+		maxSize = size
+	}
+	maxAge := defaultQueueMaxAge
+	if v := os.Getenv(otelExporterQueueMaxAgeEnvKey); v != "" {
+		ms, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || ms <= 0 {
+			err = fmt.Errorf("invalid %s value %q: must be a positive number of milliseconds", otelExporterQueueMaxAgeEnvKey, v)
+			return nil, errors.Join(err, exporter.Shutdown(ctx))
+		}
+		maxAge = time.Duration(ms) * time.Millisecond
+	}
+
+	dir = filepath.Join(dir, strings.ReplaceAll(name, "/", "_"))
+	q, err := newQueueSpanExporter(exporter, dir, maxSize, maxAge, defaultQueueRetryInterval, mp, name)
+	if err != nil {
+		return nil, errors.Join(err, exporter.Shutdown(ctx))
+	}
+	return q, nil
+}
+
+// newQueueSpanExporter returns a queueSpanExporter storing the batches in
+// dir. The batches left in dir by a previous exporter are exported first.
+// The queued batches are retried every retryInterval. The metrics of the
+// queue are recorded with mp, if not nil, with the "exporter" attribute set
+// to name.
+func newQueueSpanExporter(exporter trace.SpanExporter, dir string, maxSize int64, maxAge, retryInterval time.Duration, mp metric.MeterProvider, name string) (*queueSpanExporter, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	e := &queueSpanExporter{
+		exporter: exporter,
+		dir:      dir,
+		maxSize:  maxSize,
+		maxAge:   maxAge,
+		attrs:    attribute.NewSet(attribute.String("exporter", name)),
+		next:     1,
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if err := e.load(); err != nil {
+		return nil, err
+	}
+	e.retryCtx, e.cancelRetry = context.WithCancel(context.Background())
+
+	if mp == nil {
+		mp = noop.NewMeterProvider()
+	}
+	meter := mp.Meter(meterName)
+	var err error
+	e.dropped, err = meter.Int64Counter(
+		"otel.autoexport.queue.dropped",
+		metric.WithDescription("Number of spans dropped from the on-disk retry queue."),
+		metric.WithUnit("{span}"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	queued, err := meter.Int64ObservableGauge(
+		"otel.autoexport.queue.spans",
+		metric.WithDescription("Number of spans in the on-disk retry queue."),
+		metric.WithUnit("{span}"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	e.registration, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(queued, e.queued.Load(), metric.WithAttributeSet(e.attrs))
+		return nil
+	}, queued)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	e.evict(0)
+	go e.retry(retryInterval)
+	return e, nil
+}
+
+// load adds the batches stored in the directory to the queue.
+func (e *queueSpanExporter) load() error {
+	entries, err := os.ReadDir(e.dir)
+	if err != nil {
+		return err
+	}
+	// The entries are sorted by name, that is by sequence number.
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), queueBatchExt+".tmp") {
+			// Partially written batch.
+			if err := os.Remove(filepath.Join(e.dir, entry.Name())); err != nil {
+				return err
+			}
+			continue
+		}
+		seq, spans, ok := parseBatchName(entry.Name())
+		if !ok || entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		e.batches = append(e.batches, queuedBatch{
+			path:    filepath.Join(e.dir, entry.Name()),
+			size:    info.Size(),
+			spans:   spans,
+			created: info.ModTime(),
+		})
+		e.size += info.Size()
+		e.queued.Add(spans)
+		e.next = max(e.next, seq+1)
+	}
+	return nil
+}
+
+// batchName returns the file name of the batch with the sequence number seq
+// and the number of spans. Sequence numbers are zero-padded so that the
+// names sort in the queue order.
+func batchName(seq uint64, spans int) string {
+	return fmt.Sprintf("%020d-%d%s", seq, spans, queueBatchExt)
+}
+
+func parseBatchName(name string) (seq uint64, spans int64, ok bool) {
+	s, n, found := strings.Cut(strings.TrimSuffix(name, queueBatchExt), "-")
+	if !found || !strings.HasSuffix(name, queueBatchExt) {
+		return 0, 0, false
+	}
+	seq, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	spans, err = strconv.ParseInt(n, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return seq, spans, true
+}
+
+// ExportSpans exports spans if the queue is empty. Otherwise spans are
+// added to the queue, behind the queued batches, and exported by the
+// background goroutine. If the exporter fails, spans are added to the queue
+// and nil is returned.
+func (e *queueSpanExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.expire(ctx)
+	if len(e.batches) > 0 {
+		e.wakeRetry()
+		return e.enqueue(ctx, spans)
+	}
+	err := e.exporter.ExportSpans(ctx, spans)
+	if err == nil {
+		return nil
+	}
+	otel.Handle(fmt.Errorf("queuing %d spans after export failure: %w", len(spans), err))
+	return e.enqueue(ctx, spans)
+}
+
+// wakeRetry makes the background goroutine export the queued batches
+// without waiting for the retry interval.
+func (e *queueSpanExporter) wakeRetry() {
+	select {
+	case e.wake <- struct{}{}:
+	default:
+	}
+}
+
+// expire drops the expired batches at the front of the queue. The caller
+// must hold e.mu.
+func (e *queueSpanExporter) expire(ctx context.Context) {
+	for len(e.batches) > 0 && time.Since(e.batches[0].created) > e.maxAge {
+		e.remove(ctx, queueDropMaxAge)
+	}
+}
+
+// drain exports the queued batches in order. It stops at the first batch
+// that fails to be exported, or when ctx is done.
+func (e *queueSpanExporter) drain(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		more, err := e.exportQueued(ctx)
+		if err != nil || !more {
+			return err
+		}
+	}
+}
+
+// exportQueued exports the first queued batch, dropping the expired and
+// invalid batches before it. It returns whether batches are left to be
+// exported. e.mu is only held for the export of this batch so that
+// ExportSpans does not wait for the export of the whole queue.
+func (e *queueSpanExporter) exportQueued(ctx context.Context) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for len(e.batches) > 0 {
+		b := e.batches[0]
+		if time.Since(b.created) > e.maxAge {
+			e.remove(ctx, queueDropMaxAge)
+			continue
+		}
+		spans, err := readBatch(b.path)
+		if err != nil {
+			otel.Handle(fmt.Errorf("dropping invalid queued batch %s: %w", b.path, err))
+			e.remove(ctx, queueDropInvalid)
+			continue
+		}
+		if err := e.exporter.ExportSpans(ctx, spans); err != nil {
+			return false, err
+		}
+		e.remove(ctx, "")
+		return len(e.batches) > 0, nil
+	}
+	return false, nil
+}
+
+func readBatch(path string) ([]trace.ReadOnlySpan, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var data tracepb.TracesData
+	if err := proto.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+	return transform.Spans(data.GetResourceSpans())
+}
+
+// enqueue stores spans as the last batch of the queue, evicting the oldest
+// batches if the queue exceeds its maximum size. The caller must hold e.mu.
+func (e *queueSpanExporter) enqueue(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	data, err := proto.Marshal(&tracepb.TracesData{ResourceSpans: transform.ResourceSpans(spans)})
+	if err != nil {
+		return err
+	}
+	size := int64(len(data))
+	if size > e.maxSize {
+		e.dropped.Add(ctx, int64(len(spans)), metric.WithAttributeSet(e.dropAttrs(queueDropMaxSize)))
+		return fmt.Errorf("dropping %d spans: batch of %d bytes exceeds %s", len(spans), size, otelExporterQueueMaxSizeEnvKey)
+	}
+	e.evict(size)
+
+	// Write to a temporary file first so that no partial batch is loaded
+	// if the process exits while writing.
+	path := filepath.Join(e.dir, batchName(e.next, len(spans)))
+	tmp := path + ".tmp"
+	err = os.WriteFile(tmp, data, 0o600)
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	e.next++
+	e.batches = append(e.batches, queuedBatch{
+		path:    path,
+		size:    size,
+		spans:   int64(len(spans)),
+		created: time.Now(),
+	})
+	e.size += size
+	e.queued.Add(int64(len(spans)))
+	return nil
+}
+
+// evict drops the oldest batches until a batch of size bytes fits in the
+// queue. The caller must hold e.mu.
+func (e *queueSpanExporter) evict(size int64) {
+	for len(e.batches) > 0 && e.size+size > e.maxSize {
+		e.remove(context.Background(), queueDropMaxSize)
+	}
+}
+
+// remove removes the first batch of the queue. Its spans are counted as
+// dropped for the reason, unless it is empty. The caller must hold e.mu.
+func (e *queueSpanExporter) remove(ctx context.Context, reason string) {
+	b := e.batches[0]
+	e.batches = e.batches[1:]
+	e.size -= b.size
+	e.queued.Add(-b.spans)
+	if reason != "" {
+		e.dropped.Add(ctx, b.spans, metric.WithAttributeSet(e.dropAttrs(reason)))
+	}
+	if err := os.Remove(b.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		otel.Handle(err)
+	}
+}
+
+// dropAttrs returns the attributes of the spans dropped for the reason.
+func (e *queueSpanExporter) dropAttrs(reason string) attribute.Set {
+	return attribute.NewSet(append(e.attrs.ToSlice(), attribute.String("reason", reason))...)
+}
+
+// retry exports the queued batches every interval, and when ExportSpans
+// queues a batch behind them, until the exporter is shut down.
+func (e *queueSpanExporter) retry(interval time.Duration) {
+	defer close(e.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-e.stop:
+			return
+		case <-ticker.C:
+		case <-e.wake:
+		}
+		for more := true; more; {
+			select {
+			case <-e.stop:
+				return
+			default:
+			}
+			// Failures were reported when the batches were queued.
+			more, _ = e.exportQueued(e.retryCtx)
+		}
+	}
+}
+
+// Shutdown exports the queued batches and shuts down the exporter. The
+// batches that fail to be exported before ctx is done are kept in the
+// directory.
+func (e *queueSpanExporter) Shutdown(ctx context.Context) error {
+	e.stopOnce.Do(func() { close(e.stop) })
+	select {
+	case <-e.done:
+	case <-ctx.Done():
+		// Abort the export in progress, its batch is kept in the
+		// directory.
+		e.cancelRetry()
+		return ctx.Err()
+	}
+
+	var err error
+	e.shutdownOnce.Do(func() {
+		e.cancelRetry()
+		if drainErr := e.drain(ctx); drainErr != nil {
+			otel.Handle(fmt.Errorf("keeping %d queued spans in %s: %w", e.queued.Load(), e.dir, drainErr))
+		}
+		if e.registration != nil {
+			err = e.registration.Unregister()
+		}
+		err = errors.Join(err, e.exporter.Shutdown(ctx))
+	})
+	return err
+}
@@ -0,0 +1,445 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoexport // import "go.opentelemetry.io/contrib/exporters/autoexport"
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// otlpTraceServer is a local OTLP/HTTP traces endpoint that fails while
+// fail is true.
+type otlpTraceServer struct {
+	*httptest.Server
+	fail atomic.Bool
+
+	mu    sync.Mutex
+	names []string
+}
+
+func newOTLPTraceServer(t *testing.T) *otlpTraceServer {
+	s := &otlpTraceServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.fail.Load() {
+			// Not retried by the exporter.
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var req coltracepb.ExportTraceServiceRequest
+		if err := proto.Unmarshal(body, &req); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, rs := range req.GetResourceSpans() {
+			for _, ss := range rs.GetScopeSpans() {
+				for _, span := range ss.GetSpans() {
+					s.names = append(s.names, span.GetName())
+				}
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *otlpTraceServer) spanNames() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.names...)
+}
+
+func endSpan(tp *trace.TracerProvider, name string) {
+	_, span := tp.Tracer("scope").Start(context.Background(), name)
+	span.End()
+}
+
+func queuedFiles(t *testing.T, dir string) []string {
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+queueBatchExt))
+	require.NoError(t, err)
+	return matches
+}
+
+func TestSpanExporterQueue(t *testing.T) {
+	handled := recordOtelHandleErrors(t)
+	srv := newOTLPTraceServer(t)
+	dir := t.TempDir()
+	t.Setenv("OTEL_TRACES_EXPORTER", "otlp")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", srv.URL+"/v1/traces")
+	t.Setenv("OTEL_EXPORTER_QUEUE_DIR", dir)
+
+	exp, err := NewSpanExporter(context.Background())
+	require.NoError(t, err)
+	assert.IsType(t, &queueSpanExporter{}, exp)
+	tp := trace.NewTracerProvider(trace.WithSyncer(exp))
+	// Each exporter has its own queue.
+	dir = filepath.Join(dir, "otlp")
+
+	srv.fail.Store(true)
+	endSpan(tp, "a")
+	endSpan(tp, "b")
+	assert.Len(t, queuedFiles(t, dir), 2)
+	// "b" is queued behind "a" without being exported.
+	assert.Len(t, *handled, 1)
+	assert.Empty(t, srv.spanNames())
+
+	// "c" is queued behind "a" and "b", exported in the background.
+	srv.fail.Store(false)
+	endSpan(tp, "c")
+	assert.Eventually(t, func() bool {
+		return len(queuedFiles(t, dir)) == 0
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"a", "b", "c"}, srv.spanNames())
+
+	require.NoError(t, tp.Shutdown(context.Background()))
+}
+
+func TestSpanExporterQueuePersistence(t *testing.T) {
+	recordOtelHandleErrors(t)
+	srv := newOTLPTraceServer(t)
+	dir := t.TempDir()
+	t.Setenv("OTEL_TRACES_EXPORTER", "otlp")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", srv.URL+"/v1/traces")
+	t.Setenv("OTEL_EXPORTER_QUEUE_DIR", dir)
+
+	srv.fail.Store(true)
+	exp, err := NewSpanExporter(context.Background())
+	require.NoError(t, err)
+	tp := trace.NewTracerProvider(trace.WithSyncer(exp))
+	endSpan(tp, "a")
+	require.NoError(t, tp.Shutdown(context.Background()))
+	dir = filepath.Join(dir, "otlp")
+	require.Len(t, queuedFiles(t, dir), 1)
+
+	// The batches of the previous exporter are exported first.
+	srv.fail.Store(false)
+	exp, err = NewSpanExporter(context.Background())
+	require.NoError(t, err)
+	tp = trace.NewTracerProvider(trace.WithSyncer(exp))
+	endSpan(tp, "b")
+	require.NoError(t, tp.Shutdown(context.Background()))
+	assert.Equal(t, []string{"a", "b"}, srv.spanNames())
+	assert.Empty(t, queuedFiles(t, dir))
+}
+
+func TestSpanExporterQueueInvalid(t *testing.T) {
+	t.Setenv("OTEL_TRACES_EXPORTER", "console")
+	t.Setenv("OTEL_EXPORTER_QUEUE_DIR", t.TempDir())
+
+	for _, tc := range []struct {
+		key, value, err string
+	}{
+		{"OTEL_EXPORTER_QUEUE_MAX_SIZE", "1MB", `invalid OTEL_EXPORTER_QUEUE_MAX_SIZE value "1MB"`},
+		{"OTEL_EXPORTER_QUEUE_MAX_SIZE", "0", `invalid OTEL_EXPORTER_QUEUE_MAX_SIZE value "0"`},
+		{"OTEL_EXPORTER_QUEUE_MAX_AGE", "1h", `invalid OTEL_EXPORTER_QUEUE_MAX_AGE value "1h"`},
+	} {
+		t.Run(tc.key+"="+tc.value, func(t *testing.T) {
+			t.Setenv(tc.key, tc.value)
+			_, err := NewSpanExporter(context.Background())
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestSpanExporterQueueNone(t *testing.T) {
+	t.Setenv("OTEL_TRACES_EXPORTER", "none")
+	t.Setenv("OTEL_EXPORTER_QUEUE_DIR", t.TempDir())
+
+	got, err := NewSpanExporter(context.Background())
+	require.NoError(t, err)
+	assert.True(t, IsNoneSpanExporter(got))
+}
+
+func TestSpanExporterQueueMultipleExporters(t *testing.T) {
+	recordOtelHandleErrors(t)
+	srv := newOTLPTraceServer(t)
+	dir := t.TempDir()
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	t.Setenv("OTEL_TRACES_EXPORTER", "otlp,otlp/file")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", srv.URL+"/v1/traces")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_FILE_PATH", path)
+	t.Setenv("OTEL_EXPORTER_QUEUE_DIR", dir)
+
+	mp, reader := recordQueueMetrics()
+	exp, err := NewSpanExporter(context.Background(), WithSpanExporterMeterProvider(mp))
+	require.NoError(t, err)
+	tp := trace.NewTracerProvider(trace.WithSyncer(exp))
+
+	// Only the batch of the failing exporter is queued, the other exporter
+	// does not export it again.
+	srv.fail.Store(true)
+	endSpan(tp, "a")
+	assert.Len(t, queuedFiles(t, filepath.Join(dir, "otlp")), 1)
+	assert.Empty(t, queuedFiles(t, filepath.Join(dir, "otlp_file")))
+
+	metricdatatest.AssertAggregationsEqual(t, metricdata.Gauge[int64]{
+		DataPoints: []metricdata.DataPoint[int64]{
+			{Attributes: attribute.NewSet(attribute.String("exporter", "otlp")), Value: 1},
+			{Attributes: attribute.NewSet(attribute.String("exporter", "otlp/file")), Value: 0},
+		},
+	}, queueMetrics(t, reader)["otel.autoexport.queue.spans"].Data, metricdatatest.IgnoreTimestamp())
+
+	srv.fail.Store(false)
+	require.NoError(t, tp.Shutdown(context.Background()))
+	assert.Equal(t, []string{"a"}, srv.spanNames())
+	assert.Empty(t, queuedFiles(t, filepath.Join(dir, "otlp")))
+
+	got := tracetest.NewInMemoryExporter()
+	require.NoError(t, ReplaySpans(context.Background(), path, got))
+	assert.Len(t, got.GetSpans(), 1)
+}
+
+// failingSpanExporter fails to export while fail is true.
+type failingSpanExporter struct {
+	fail     bool
+	exported []string
+}
+
+func (e *failingSpanExporter) ExportSpans(_ context.Context, spans []trace.ReadOnlySpan) error {
+	if e.fail {
+		return errors.New("export failed")
+	}
+	for _, s := range spans {
+		e.exported = append(e.exported, s.Name())
+	}
+	return nil
+}
+
+func (e *failingSpanExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+// blockingSpanExporter blocks the exports until their context is done.
+type blockingSpanExporter struct{}
+
+func (blockingSpanExporter) ExportSpans(ctx context.Context, _ []trace.ReadOnlySpan) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (blockingSpanExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+func spanStubs(names ...string) []trace.ReadOnlySpan {
+	var stubs tracetest.SpanStubs
+	for _, name := range names {
+		stubs = append(stubs, tracetest.SpanStub{Name: name})
+	}
+	return stubs.Snapshots()
+}
+
+// recordQueueMetrics returns a MeterProvider recording the metrics of the
+// queue and its reader.
+func recordQueueMetrics() (*metric.MeterProvider, *metric.ManualReader) {
+	r := metric.NewManualReader()
+	return metric.NewMeterProvider(metric.WithReader(r)), r
+}
+
+func queueMetrics(t *testing.T, r *metric.ManualReader) map[string]metricdata.Metrics {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, r.Collect(context.Background(), &rm))
+	got := make(map[string]metricdata.Metrics)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			got[m.Name] = m
+		}
+	}
+	return got
+}
+
+func TestQueueSpanExporterMaxSize(t *testing.T) {
+	recordOtelHandleErrors(t)
+	mp, reader := recordQueueMetrics()
+	dir := t.TempDir()
+	stub := &failingSpanExporter{fail: true}
+
+	e, err := newQueueSpanExporter(stub, dir, defaultQueueMaxSize, time.Hour, time.Hour, mp, "test")
+	require.NoError(t, err)
+	require.NoError(t, e.ExportSpans(context.Background(), spanStubs("a")))
+	files := queuedFiles(t, dir)
+	require.Len(t, files, 1)
+	info, err := os.Stat(files[0])
+	require.NoError(t, err)
+	// Room for two batches of a single span.
+	e.maxSize = 2*info.Size() + 1
+
+	require.NoError(t, e.ExportSpans(context.Background(), spanStubs("b")))
+	require.NoError(t, e.ExportSpans(context.Background(), spanStubs("c")))
+	assert.Len(t, queuedFiles(t, dir), 2)
+
+	metrics := queueMetrics(t, reader)
+	metricdatatest.AssertEqual(t, metricdata.Metrics{
+		Name:        "otel.autoexport.queue.dropped",
+		Description: "Number of spans dropped from the on-disk retry queue.",
+		Unit:        "{span}",
+		Data: metricdata.Sum[int64]{
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+			DataPoints: []metricdata.DataPoint[int64]{
+				{Attributes: attribute.NewSet(attribute.String("exporter", "test"), attribute.String("reason", "max_size")), Value: 1},
+			},
+		},
+	}, metrics["otel.autoexport.queue.dropped"], metricdatatest.IgnoreTimestamp())
+	metricdatatest.AssertEqual(t, metricdata.Metrics{
+		Name:        "otel.autoexport.queue.spans",
+		Description: "Number of spans in the on-disk retry queue.",
+		Unit:        "{span}",
+		Data: metricdata.Gauge[int64]{
+			DataPoints: []metricdata.DataPoint[int64]{{Attributes: attribute.NewSet(attribute.String("exporter", "test")), Value: 2}},
+		},
+	}, metrics["otel.autoexport.queue.spans"], metricdatatest.IgnoreTimestamp())
+
+	// Batches that do not fit in the queue are dropped.
+	err = e.ExportSpans(context.Background(), spanStubs("d", "e", "f", "g", "h", "i"))
+	assert.ErrorContains(t, err, "dropping 6 spans")
+
+	stub.fail = false
+	require.NoError(t, e.Shutdown(context.Background()))
+	assert.Equal(t, []string{"b", "c"}, stub.exported)
+}
+
+func TestQueueSpanExporterMaxAge(t *testing.T) {
+	recordOtelHandleErrors(t)
+	mp, reader := recordQueueMetrics()
+	dir := t.TempDir()
+	stub := &failingSpanExporter{fail: true}
+
+	e, err := newQueueSpanExporter(stub, dir, defaultQueueMaxSize, time.Nanosecond, time.Hour, mp, "test")
+	require.NoError(t, err)
+	require.NoError(t, e.ExportSpans(context.Background(), spanStubs("a", "b")))
+	time.Sleep(time.Millisecond)
+
+	stub.fail = false
+	require.NoError(t, e.ExportSpans(context.Background(), spanStubs("c")))
+	assert.Equal(t, []string{"c"}, stub.exported)
+	assert.Empty(t, queuedFiles(t, dir))
+
+	dropped := queueMetrics(t, reader)["otel.autoexport.queue.dropped"]
+	metricdatatest.AssertAggregationsEqual(t, metricdata.Sum[int64]{
+		Temporality: metricdata.CumulativeTemporality,
+		IsMonotonic: true,
+		DataPoints: []metricdata.DataPoint[int64]{
+			{Attributes: attribute.NewSet(attribute.String("exporter", "test"), attribute.String("reason", "max_age")), Value: 2},
+		},
+	}, dropped.Data, metricdatatest.IgnoreTimestamp())
+	require.NoError(t, e.Shutdown(context.Background()))
+}
+
+func TestQueueSpanExporterRetry(t *testing.T) {
+	recordOtelHandleErrors(t)
+	stub := &failingSpanExporter{fail: true}
+
+	e, err := newQueueSpanExporter(stub, t.TempDir(), defaultQueueMaxSize, time.Hour, time.Millisecond, nil, "test")
+	require.NoError(t, err)
+	require.NoError(t, e.ExportSpans(context.Background(), spanStubs("a")))
+
+	e.mu.Lock()
+	stub.fail = false
+	e.mu.Unlock()
+	assert.Eventually(t, func() bool {
+		return e.queued.Load() == 0
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, e.Shutdown(context.Background()))
+	assert.Equal(t, []string{"a"}, stub.exported)
+}
+
+func TestQueueSpanExporterBacklog(t *testing.T) {
+	recordOtelHandleErrors(t)
+	stub := &failingSpanExporter{fail: true}
+
+	e, err := newQueueSpanExporter(stub, t.TempDir(), defaultQueueMaxSize, time.Hour, time.Hour, nil, "test")
+	require.NoError(t, err)
+	require.NoError(t, e.ExportSpans(context.Background(), spanStubs("a")))
+	require.NoError(t, e.ExportSpans(context.Background(), spanStubs("b")))
+
+	e.mu.Lock()
+	stub.fail = false
+	e.mu.Unlock()
+	// The backlog is exported in the background, one batch at a time, and
+	// the new batch is exported after it.
+	require.NoError(t, e.ExportSpans(context.Background(), spanStubs("c")))
+	assert.Eventually(t, func() bool {
+		return e.queued.Load() == 0
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, e.Shutdown(context.Background()))
+	assert.Equal(t, []string{"a", "b", "c"}, stub.exported)
+}
+
+func TestQueueSpanExporterShutdownContext(t *testing.T) {
+	handled := recordOtelHandleErrors(t)
+	dir := t.TempDir()
+
+	e, err := newQueueSpanExporter(blockingSpanExporter{}, dir, defaultQueueMaxSize, time.Hour, time.Millisecond, nil, "test")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.NoError(t, e.ExportSpans(ctx, spanStubs("a")))
+	// Let the background goroutine block on the export of the batch.
+	time.Sleep(10 * time.Millisecond)
+
+	// The export of the background goroutine is aborted once ctx is done,
+	// and the batch is kept.
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	shutdown := make(chan error)
+	go func() { shutdown <- e.Shutdown(ctx) }()
+	select {
+	case err := <-shutdown:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown does not honor its context")
+	}
+	assert.Len(t, queuedFiles(t, dir), 1)
+
+	// The shutdown can be retried.
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.NoError(t, e.Shutdown(ctx))
+	assert.Len(t, queuedFiles(t, dir), 1)
+	require.NotEmpty(t, *handled)
+	assert.ErrorIs(t, (*handled)[len(*handled)-1], context.DeadlineExceeded)
+}
@@ -18,10 +18,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"sync"
 )
 
-const otelExporterOTLPProtoEnvKey = "OTEL_EXPORTER_OTLP_PROTOCOL"
+const (
+	otelExporterOTLPProtoEnvKey        = "OTEL_EXPORTER_OTLP_PROTOCOL"
+	otelExporterOTLPTracesProtoEnvKey  = "OTEL_EXPORTER_OTLP_TRACES_PROTOCOL"
+	otelExporterOTLPMetricsProtoEnvKey = "OTEL_EXPORTER_OTLP_METRICS_PROTOCOL"
+	otelExporterOTLPLogsProtoEnvKey    = "OTEL_EXPORTER_OTLP_LOGS_PROTOCOL"
+)
+
+// OTLP protocols supported by the otlp exporters. The "grpc/protobuf" alias of
+// "grpc" is the value used by go.opentelemetry.io/contrib/config.
+const (
+	otlpProtocolGRPC         = "grpc"
+	otlpProtocolGRPCProtobuf = "grpc/protobuf"
+	otlpProtocolHTTPProtobuf = "http/protobuf"
+)
 
 // registry maintains a map of exporter names to exporter factories
 // func(context.Context) (T, error) that is safe for concurrent use by multiple
@@ -37,8 +51,14 @@ var (
 	errUnknownExporter = errors.New("unknown exporter")
 
 	// errInvalidOTLPProtocol is returned when an invalid protocol is used in
-	// the OTEL_EXPORTER_OTLP_PROTOCOL environment variable.
-	errInvalidOTLPProtocol = errors.New("invalid OTLP protocol - should be one of ['grpc', 'http/protobuf']")
+	// the OTEL_EXPORTER_OTLP_PROTOCOL or OTEL_EXPORTER_OTLP_*_PROTOCOL
+	// environment variables.
+	errInvalidOTLPProtocol = errors.New("invalid OTLP protocol - should be one of ['grpc', 'grpc/protobuf', 'http/protobuf']")
+
+	// errMultipleExporters is returned when multiple exporter names are used
+	// in the OTEL_*_EXPORTER environment variables and the created exporters
+	// cannot be combined.
+	errMultipleExporters = errors.New("multiple exporters are not supported")
 
 	// errDuplicateRegistration is returned when an duplicate registration is detected.
 	errDuplicateRegistration = errors.New("duplicate registration")
@@ -71,6 +91,29 @@ func (r *registry[T]) store(key string, factory func(context.Context) (T, error)
 	return nil
 }
 
+// otlpProtocol returns the OTLP protocol defined by the signal-specific
+// environment variable signalEnvKey, or by OTEL_EXPORTER_OTLP_PROTOCOL if it
+// is unset or empty. It defaults to "http/protobuf". The "grpc/protobuf" alias
+// is returned as "grpc". errInvalidOTLPProtocol, naming the environment
+// variable, is returned for unsupported protocols.
+func otlpProtocol(signalEnvKey string) (string, error) {
+	envKey := signalEnvKey
+	proto := os.Getenv(envKey)
+	if proto == "" {
+		envKey = otelExporterOTLPProtoEnvKey
+		proto = os.Getenv(envKey)
+	}
+
+	switch proto {
+	case "", otlpProtocolHTTPProtobuf:
+		return otlpProtocolHTTPProtobuf, nil
+	case otlpProtocolGRPC, otlpProtocolGRPCProtobuf:
+		return otlpProtocolGRPC, nil
+	default:
+		return "", fmt.Errorf("%w: %s=%q", errInvalidOTLPProtocol, envKey, proto)
+	}
+}
+
 func must(err error) {
 	if err != nil {
 		panic(err)
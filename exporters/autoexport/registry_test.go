@@ -102,3 +102,34 @@ func TestMust(t *testing.T) {
 	assert.Panics(t, func() { must(errors.New("test")) })
 	assert.NotPanics(t, func() { must(nil) })
 }
+
+func TestOTLPProtocol(t *testing.T) {
+	const envKey = otelExporterOTLPTracesProtoEnvKey
+	for _, tc := range []struct {
+		signal, general string
+		want            string
+		wantErr         string
+	}{
+		{want: otlpProtocolHTTPProtobuf},
+		{general: "grpc", want: otlpProtocolGRPC},
+		{general: "grpc/protobuf", want: otlpProtocolGRPC},
+		{signal: "grpc", general: "http/protobuf", want: otlpProtocolGRPC},
+		{signal: "http/protobuf", general: "grpc", want: otlpProtocolHTTPProtobuf},
+		{general: "http/json", wantErr: `OTEL_EXPORTER_OTLP_PROTOCOL="http/json"`},
+		{signal: "invalid", general: "grpc", wantErr: `OTEL_EXPORTER_OTLP_TRACES_PROTOCOL="invalid"`},
+	} {
+		t.Run(fmt.Sprintf("%s=%q,%s=%q", envKey, tc.signal, otelExporterOTLPProtoEnvKey, tc.general), func(t *testing.T) {
+			t.Setenv(envKey, tc.signal)
+			t.Setenv(otelExporterOTLPProtoEnvKey, tc.general)
+
+			got, err := otlpProtocol(envKey)
+			if tc.wantErr != "" {
+				assert.ErrorIs(t, err, errInvalidOTLPProtocol)
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoexport // import "go.opentelemetry.io/contrib/exporters/autoexport"
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"go.opentelemetry.io/contrib/exporters/autoexport/internal/transform"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/trace"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// ReplaySpans exports the spans of the file at path, written by the
+// "otlp/file" span exporter, with exporter. Each line of the file is
+// exported with a call to exporter.ExportSpans. Gzip-compressed files are
+// detected automatically.
+//
+// Rotated files are replayed by calling ReplaySpans with each of them, in the
+// increasing order of their numeric suffix, and then with the current file.
+//
+// Replaying stops at the first line that cannot be decoded or exported, the
+// returned error contains its line number. The exporter is not shut down.
+func ReplaySpans(ctx context.Context, path string, exporter trace.SpanExporter) error {
+	return replay(path, func(line []byte) error {
+		var data tracepb.TracesData
+		if err := protojson.Unmarshal(line, &data); err != nil {
+			return err
+		}
+		spans, err := transform.Spans(data.GetResourceSpans())
+		if err != nil {
+			return err
+		}
+		if len(spans) == 0 {
+			return nil
+		}
+		return exporter.ExportSpans(ctx, spans)
+	})
+}
+
+// ReplayMetrics exports the metrics of the file at path, written by the
+// "otlp/file" metric exporter, with exporter. Each line of the file is
+// exported with a call to exporter.Export. Gzip-compressed files are detected
+// automatically.
+//
+// The OTLP format does not distinguish integer from floating point
+// histograms, the replayed histograms have float64 values.
+//
+// Rotated files are replayed by calling ReplayMetrics with each of them, in
+// the increasing order of their numeric suffix, and then with the current
+// file.
+//
+// Replaying stops at the first line that cannot be decoded or exported, the
+// returned error contains its line number. The exporter is not shut down.
+func ReplayMetrics(ctx context.Context, path string, exporter metric.Exporter) error {
+	return replay(path, func(line []byte) error {
+		var data metricpb.MetricsData
+		if err := protojson.Unmarshal(line, &data); err != nil {
+			return err
+		}
+		for _, prm := range data.GetResourceMetrics() {
+			rm, err := transform.SDKResourceMetrics(prm)
+			if err != nil {
+				return err
+			}
+			if err := exporter.Export(ctx, rm); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// replay calls f with each non-empty line of the file at path.
+func replay(path string, f func(line []byte) error) (err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, file.Close()) }()
+
+	r := bufio.NewReader(file)
+	var compressed bool
+	if magic, _ := r.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r, compressed = bufio.NewReader(gz), true
+	}
+
+	for n := 1; ; n++ {
+		line, readErr := r.ReadBytes('\n')
+		if compressed && len(line) == 0 && errors.Is(readErr, io.ErrUnexpectedEOF) {
+			// The last gzip member is not terminated, the process writing
+			// the file exited after flushing its last line.
+			return nil
+		}
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return fmt.Errorf("%s:%d: %w", path, n, readErr)
+		}
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			if err := f(line); err != nil {
+				return fmt.Errorf("%s:%d: %w", path, n, err)
+			}
+		}
+		if readErr != nil {
+			return nil
+		}
+	}
+}
@@ -16,42 +16,172 @@ package autoexport // import "go.opentelemetry.io/contrib/exporters/autoexport"
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
 )
 
 type signal[T any] struct {
 	envKey   string
 	registry *registry[T]
+	// name and item are the signal name and the name of the exported items
+	// in the metrics recorded about the exporters.
+	name, item string
+	// compose combines the values created when the environment variable
+	// lists multiple exporters. If nil, only a single exporter is supported
+	// by create.
+	compose func([]T) T
+	// instrument wraps a created value to record its exports with the
+	// exporterMetrics of ctx, if any. If nil, the factories instrument the
+	// values they create, see newPeriodicReader.
+	instrument func(ctx context.Context, v T) T
+	// queue wraps a created value with the on-disk retry queue of the
+	// exporter name, recording its metrics with mp, if not nil. If nil, the
+	// values of the signal are not queued.
+	queue func(ctx context.Context, mp metric.MeterProvider, name string, v T) (T, error)
 }
 
-func newSignal[T any](envKey string) signal[T] {
+func newSignal[T any](envKey string, compose func([]T) T, instrument func(context.Context, T) T, queue func(context.Context, metric.MeterProvider, string, T) (T, error)) signal[T] {
+	name := strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(envKey, "OTEL_"), "_EXPORTER"))
 	return signal[T]{
 		envKey: envKey,
+		name:   name,
+		item:   signalItems[name],
 		registry: &registry[T]{
 			names: make(map[string]func(context.Context) (T, error)),
 		},
+		compose:    compose,
+		instrument: instrument,
+		queue:      queue,
 	}
 }
 
+// create returns the value created for the exporters listed in the
+// environment variable of s. Multiple values are combined with s.compose.
 func (s signal[T]) create(ctx context.Context, opts ...option[T]) (T, error) {
+	var zero T
+	values, err := s.createAll(ctx, opts...)
+	if err != nil {
+		return zero, err
+	}
+	if len(values) == 1 {
+		return values[0], nil
+	}
+	if s.compose == nil {
+		return zero, errors.Join(
+			fmt.Errorf("%w: %s", errMultipleExporters, s.envKey),
+			shutdown(ctx, values),
+		)
+	}
+	return s.compose(values), nil
+}
+
+// createAll returns a value for each of the exporters listed in the
+// environment variable of s.
+func (s signal[T]) createAll(ctx context.Context, opts ...option[T]) ([]T, error) {
 	var cfg config[T]
 	for _, opt := range opts {
 		opt.apply(&cfg)
 	}
 
-	expType := os.Getenv(s.envKey)
-	if expType == "" {
+	expTypes := s.exporterNames()
+	if len(expTypes) == 0 {
 		if cfg.fallbackFactory != nil {
-			return cfg.fallbackFactory(ctx)
+			v, err := cfg.fallbackFactory(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return []T{v}, nil
+		}
+		expTypes = []string{"otlp"}
+	}
+
+	values := make([]T, 0, len(expTypes))
+	for _, expType := range expTypes {
+		loadCtx := ctx
+		if cfg.meterProvider != nil {
+			loadCtx = withExporterMetrics(ctx, cfg.meterProvider, s.name, expType, s.item)
+		}
+		var v T
+		var err error
+		if factory, ok := cfg.factories[expType]; ok {
+			v, err = factory(loadCtx)
+		} else {
+			v, err = s.registry.load(loadCtx, expType)
+		}
+		if err != nil {
+			return nil, errors.Join(err, shutdown(ctx, values))
+		}
+		if s.instrument != nil {
+			v = s.instrument(loadCtx, v)
+		}
+		if s.queue != nil {
+			if v, err = s.queue(ctx, cfg.meterProvider, expType, v); err != nil {
+				return nil, errors.Join(err, shutdown(ctx, values))
+			}
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// exporterNames returns the comma-separated exporter names of the
+// environment variable of s, without duplicates. The "none" exporter is
+// ignored if it is listed with other exporters.
+func (s signal[T]) exporterNames() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(os.Getenv(s.envKey), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	if len(names) > 1 && seen["none"] {
+		otel.Handle(fmt.Errorf("%s: ignoring the \"none\" exporter listed with other exporters", s.envKey))
+		filtered := names[:0]
+		for _, name := range names {
+			if name != "none" {
+				filtered = append(filtered, name)
+			}
 		}
-		expType = "otlp"
+		names = filtered
 	}
+	return names
+}
 
-	return s.registry.load(ctx, expType)
+// shutdown shuts down the values that have a Shutdown method.
+func shutdown[T any](ctx context.Context, values []T) error {
+	var errs []error
+	for _, v := range values {
+		if s, ok := any(v).(interface{ Shutdown(context.Context) error }); ok {
+			errs = append(errs, s.Shutdown(ctx))
+		}
+	}
+	return errors.Join(errs...)
 }
 
 type config[T any] struct {
 	fallbackFactory func(ctx context.Context) (T, error)
+	// factories override the registered factories of the exporter names
+	// for a single call, see withFactory.
+	factories map[string]func(ctx context.Context) (T, error)
+	// meterProvider records the metrics about the exporters, if not nil.
+	meterProvider metric.MeterProvider
+}
+
+// signalItems are the names of the items exported by the signals, used in
+// the names of the metrics recorded about the exporters.
+var signalItems = map[string]string{
+	"traces":  "span",
+	"metrics": "metric_data_point",
 }
 
 type option[T any] interface {
@@ -70,3 +200,14 @@ func withFallbackFactory[T any](fallbackFactory func(ctx context.Context) (T, er
 		cfg.fallbackFactory = fallbackFactory
 	})
 }
+
+// withFactory overrides the registered factory of the exporter name with
+// factory. It is used to pass the signal-specific options to the factories.
+func withFactory[T any](name string, factory func(ctx context.Context) (T, error)) option[T] {
+	return optionFunc[T](func(cfg *config[T]) {
+		if cfg.factories == nil {
+			cfg.factories = make(map[string]func(context.Context) (T, error))
+		}
+		cfg.factories[name] = factory
+	})
+}
@@ -17,13 +17,15 @@ package autoexport // import "go.opentelemetry.io/contrib/exporters/autoexport"
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestOTLPExporterReturnedWhenNoEnvOrFallbackExporterConfigured(t *testing.T) {
-	ts := newSignal[*testType]("TEST_TYPE_KEY")
+	ts := newSignal[*testType]("TEST_TYPE_KEY", nil, nil, nil)
 	assert.NoError(t, ts.registry.store("otlp", factory("test-otlp-exporter")))
 	exp, err := ts.create(context.Background())
 	assert.NoError(t, err)
@@ -31,14 +33,14 @@ func TestOTLPExporterReturnedWhenNoEnvOrFallbackExporterConfigured(t *testing.T)
 }
 
 func TestFallbackExporterReturnedWhenNoEnvExporterConfigured(t *testing.T) {
-	ts := newSignal[*testType]("TEST_TYPE_KEY")
+	ts := newSignal[*testType]("TEST_TYPE_KEY", nil, nil, nil)
 	exp, err := ts.create(context.Background(), withFallbackFactory(factory("test-fallback-exporter")))
 	assert.NoError(t, err)
 	assert.Equal(t, exp.string, "test-fallback-exporter")
 }
 
 func TestFallbackExporterFactoryErrorReturnedWhenNoEnvExporterConfiguredAndFallbackFactoryReturnsAnError(t *testing.T) {
-	ts := newSignal[*testType]("TEST_TYPE_KEY")
+	ts := newSignal[*testType]("TEST_TYPE_KEY", nil, nil, nil)
 
 	expectedErr := errors.New("error expected to return")
 	errFactory := func(ctx context.Context) (*testType, error) {
@@ -51,7 +53,7 @@ func TestFallbackExporterFactoryErrorReturnedWhenNoEnvExporterConfiguredAndFallb
 
 func TestEnvExporterIsPreferredOverFallbackExporter(t *testing.T) {
 	envVariable := "TEST_TYPE_KEY"
-	ts := newSignal[*testType](envVariable)
+	ts := newSignal[*testType](envVariable, nil, nil, nil)
 
 	expName := "test-env-exporter-name"
 	t.Setenv(envVariable, expName)
@@ -61,3 +63,79 @@ func TestEnvExporterIsPreferredOverFallbackExporter(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, exp.string, "test-env-exporter")
 }
+
+func TestExporterListIsComposed(t *testing.T) {
+	envVariable := "TEST_TYPE_KEY"
+	compose := func(values []*testType) *testType {
+		var names []string
+		for _, v := range values {
+			names = append(names, v.string)
+		}
+		return &testType{strings.Join(names, "+")}
+	}
+	handled := recordOtelHandleErrors(t)
+	ts := newSignal[*testType](envVariable, compose, nil, nil)
+	assert.NoError(t, ts.registry.store("first", factory("first")))
+	assert.NoError(t, ts.registry.store("second", factory("second")))
+	assert.NoError(t, ts.registry.store("none", factory("none")))
+
+	for _, tc := range []struct {
+		env, want string
+	}{
+		{"first", "first"},
+		{" first , second ", "first+second"},
+		{"first,second,first,,", "first+second"},
+		{"none", "none"},
+		{"none,none", "none"},
+		{"first,none,second", "first+second"},
+	} {
+		t.Run(tc.env, func(t *testing.T) {
+			t.Setenv(envVariable, tc.env)
+			exp, err := ts.create(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, exp.string)
+		})
+	}
+	assert.Len(t, *handled, 1, "ignored none exporter not reported")
+}
+
+type shutdownTestType struct {
+	shutdown bool
+}
+
+func (s *shutdownTestType) Shutdown(context.Context) error {
+	s.shutdown = true
+	return nil
+}
+
+func TestExporterListWithoutCompose(t *testing.T) {
+	envVariable := "TEST_TYPE_KEY"
+	ts := newSignal[*shutdownTestType](envVariable, nil, nil, nil)
+	var created []*shutdownTestType
+	f := func(context.Context) (*shutdownTestType, error) {
+		s := &shutdownTestType{}
+		created = append(created, s)
+		return s, nil
+	}
+	assert.NoError(t, ts.registry.store("first", f))
+	assert.NoError(t, ts.registry.store("second", f))
+
+	t.Setenv(envVariable, "first,second")
+	all, err := ts.createAll(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	created = nil
+	_, err = ts.create(context.Background())
+	assert.ErrorIs(t, err, errMultipleExporters)
+	require.Len(t, created, 2)
+	assert.True(t, created[0].shutdown)
+	assert.True(t, created[1].shutdown)
+
+	created = nil
+	t.Setenv(envVariable, "first,unknown")
+	_, err = ts.createAll(context.Background())
+	assert.ErrorIs(t, err, errUnknownExporter)
+	require.Len(t, created, 1)
+	assert.True(t, created[0].shutdown)
+}
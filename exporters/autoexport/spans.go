@@ -16,7 +16,6 @@ package autoexport // import "go.opentelemetry.io/contrib/exporters/autoexport"
 
 import (
 	"context"
-	"os"
 
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
@@ -45,14 +44,58 @@ func WithFallbackSpanExporter(spanExporterFactory func(ctx context.Context) (tra
 //   - "none" - "no operation" exporter
 //   - "otlp" (default) - OTLP exporter; see [go.opentelemetry.io/otel/exporters/otlp/otlptrace]
 //   - "console" - Standard output exporter; see [go.opentelemetry.io/otel/exporters/stdout/stdouttrace]
+//   - "otlp/file" - OTLP JSON file exporter writing to the file at
+//     OTEL_EXPORTER_OTLP_TRACES_FILE_PATH; see [ReplaySpans]
 //
-// OTEL_EXPORTER_OTLP_PROTOCOL defines OTLP exporter's transport protocol;
-// supported values:
-//   - "grpc" - protobuf-encoded data using gRPC wire format over HTTP/2 connection;
+// OTEL_TRACES_EXPORTER may list multiple comma-separated exporters, e.g.
+// "otlp,console". The returned exporter then exports to all of them.
+// Duplicates are ignored, and so is "none" if it is listed with other
+// exporters.
+//
+// OTEL_EXPORTER_OTLP_TRACES_PROTOCOL, or OTEL_EXPORTER_OTLP_PROTOCOL if it is
+// unset, defines OTLP exporter's transport protocol; supported values:
+//   - "grpc" (or "grpc/protobuf") - protobuf-encoded data using gRPC wire format over HTTP/2 connection;
 //     see: [go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc]
 //   - "http/protobuf" (default) -  protobuf-encoded data over HTTP connection;
 //     see: [go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp]
 //
+// The "otlp/file" exporter writes a line of OTLP protobuf JSON per export.
+// OTEL_EXPORTER_OTLP_FILE_MAX_SIZE defines the size, in bytes, at which the
+// file is rotated: it is renamed with a numeric suffix, e.g. "traces.jsonl.1",
+// and a new file is created. Files are not rotated by default.
+// OTEL_EXPORTER_OTLP_FILE_COMPRESSION set to "gzip" compresses the file, an
+// existing file is then rotated instead of being appended to.
+//
+// OTEL_EXPORTER_QUEUE_DIR enables an on-disk retry queue for each exporter,
+// in a subdirectory named after the exporter of the directory it defines,
+// e.g. "otlp" or "otlp_file". The batches that an exporter fails to export
+// are stored in its directory and exported, in order, before its next batches
+// once the export succeeds again; they are retried every 5 seconds and when
+// the exporter is shut down, until the shutdown context is done.
+// The batches left in the directory by a previous process are exported too.
+// While the queue is not empty, the next batches are queued behind it and
+// exported in the background, one batch at a time: an export waits for at
+// most one queued batch to be exported, but its spans are only sent once the
+// batches queued before them are.
+// The failures are reported with otel.Handle and ExportSpans returns nil.
+// The queue is bounded by:
+//   - OTEL_EXPORTER_QUEUE_MAX_SIZE - the size of the directory, in bytes
+//     (default: 67108864, 64 MiB); the oldest batches are dropped when it is
+//     exceeded
+//   - OTEL_EXPORTER_QUEUE_MAX_AGE - the age of the batches, in milliseconds
+//     (default: 86400000, 24 hours); older batches are dropped
+//
+// With [WithSpanExporterMeterProvider], the queues record the
+// otel.autoexport.queue.spans gauge, the number of queued spans, and the
+// otel.autoexport.queue.dropped counter, the number of dropped spans by
+// reason, with the "exporter" attribute.
+//
+// Use [WithSpanExporterMeterProvider] to record metrics about the exports
+// of the exporters: the otel.sdk.exporter.span.exported and
+// otel.sdk.exporter.span.failed counters and the
+// otel.sdk.exporter.operation.duration histogram, with the "exporter" and
+// "signal" attributes, and the "error.type" attribute for failed exports.
+//
 // An error is returned if an environment value is set to an unhandled value.
 //
 // Use [RegisterSpanExporter] to handle more values of OTEL_TRACES_EXPORTER.
@@ -72,22 +115,20 @@ func RegisterSpanExporter(name string, factory func(context.Context) (trace.Span
 	must(tracesSignal.registry.store(name, factory))
 }
 
-var tracesSignal = newSignal[trace.SpanExporter]("OTEL_TRACES_EXPORTER")
+var tracesSignal = newSignal[trace.SpanExporter]("OTEL_TRACES_EXPORTER", newCompositeSpanExporter, instrumentSpanExporter, withQueueFromEnv)
 
 func init() {
 	RegisterSpanExporter("otlp", func(ctx context.Context) (trace.SpanExporter, error) {
-		proto := os.Getenv(otelExporterOTLPProtoEnvKey)
-		if proto == "" {
-			proto = "http/protobuf"
+		proto, err := otlpProtocol(otelExporterOTLPTracesProtoEnvKey)
+		if err != nil {
+			return nil, err
 		}
 
 		switch proto {
-		case "grpc":
+		case otlpProtocolGRPC:
 			return otlptracegrpc.New(ctx)
-		case "http/protobuf":
+		default: // otlpProtocolHTTPProtobuf
 			return otlptracehttp.New(ctx)
-		default:
-			return nil, errInvalidOTLPProtocol
 		}
 	})
 	RegisterSpanExporter("console", func(ctx context.Context) (trace.SpanExporter, error) {
@@ -96,4 +137,7 @@ func init() {
 	RegisterSpanExporter("none", func(ctx context.Context) (trace.SpanExporter, error) {
 		return noopSpanExporter{}, nil
 	})
+	RegisterSpanExporter("otlp/file", func(ctx context.Context) (trace.SpanExporter, error) {
+		return newFileSpanExporter(ctx)
+	})
 }
@@ -24,6 +24,7 @@ import (
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSpanExporterNone(t *testing.T) {
@@ -52,6 +53,7 @@ func TestSpanExporterOTLP(t *testing.T) {
 		{"http/protobuf", "*otlptracehttp.client"},
 		{"", "*otlptracehttp.client"},
 		{"grpc", "*otlptracegrpc.client"},
+		{"grpc/protobuf", "*otlptracegrpc.client"},
 	} {
 		t.Run(fmt.Sprintf("protocol=%q", tc.protocol), func(t *testing.T) {
 			t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", tc.protocol)
@@ -70,10 +72,44 @@ func TestSpanExporterOTLP(t *testing.T) {
 	}
 }
 
+func TestSpanExporterOTLPSignalProtocol(t *testing.T) {
+	t.Setenv("OTEL_TRACES_EXPORTER", "otlp")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "http/protobuf")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "grpc")
+
+	got, err := NewSpanExporter(context.Background())
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, got.Shutdown(context.Background()))
+	})
+	clientType := reflect.Indirect(reflect.ValueOf(got)).FieldByName("client").Elem().Type()
+	assert.Equal(t, "*otlptracegrpc.client", clientType.String())
+}
+
 func TestSpanExporterOTLPOverInvalidProtocol(t *testing.T) {
 	t.Setenv("OTEL_TRACES_EXPORTER", "otlp")
 	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "invalid-protocol")
 
 	_, err := NewSpanExporter(context.Background())
 	assert.Error(t, err)
+
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "grpc")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "invalid-protocol")
+	_, err = NewSpanExporter(context.Background())
+	assert.ErrorIs(t, err, errInvalidOTLPProtocol)
+	assert.ErrorContains(t, err, "OTEL_EXPORTER_OTLP_TRACES_PROTOCOL")
+}
+
+func TestSpanExporterList(t *testing.T) {
+	t.Setenv("OTEL_TRACES_EXPORTER", "console,otlp")
+	got, err := NewSpanExporter(context.Background())
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, got.Shutdown(context.Background()))
+	})
+	require.IsType(t, compositeSpanExporter{}, got)
+	exporters := got.(compositeSpanExporter)
+	require.Len(t, exporters, 2)
+	assert.IsType(t, &stdouttrace.Exporter{}, exporters[0])
+	assert.IsType(t, &otlptrace.Exporter{}, exporters[1])
 }
@@ -44,20 +44,24 @@ func newBucket(capacity uint) *bucket {
 }
 
 // add adds a span to the bucket, if nextTime has been reached.
-func (b *bucket) add(s sdktrace.ReadOnlySpan) {
+//
+// It returns whether the span was added, and the span it replaced, if any.
+func (b *bucket) add(s sdktrace.ReadOnlySpan) (added bool, evicted sdktrace.ReadOnlySpan) {
 	if s.EndTime().Before(b.nextTime) {
-		return
+		return false, nil
 	}
 	if len(b.buffer) == 0 {
-		return
+		return false, nil
 	}
 	b.nextTime = s.EndTime().Add(samplePeriod)
+	evicted = b.buffer[b.nextIndex]
 	b.buffer[b.nextIndex] = s
 	b.nextIndex++
 	if b.nextIndex == len(b.buffer) {
 		b.nextIndex = 0
 		b.overflow = true
 	}
+	return true, evicted
 }
 
 // len returns the number of spans in the bucket.
@@ -0,0 +1,180 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zpages // import "go.opentelemetry.io/contrib/zpages"
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// defaultMaxSpanNames is the default maximum number of span names for
+	// which samples are stored.
+	defaultMaxSpanNames = 1000
+	// defaultStuckSpanScanInterval is the default interval between two scans
+	// of the active spans for stuck spans.
+	defaultStuckSpanScanInterval = 10 * time.Second
+)
+
+// config represents the configuration options available for the SpanProcessor.
+type config struct {
+	boundaries            *boundaries
+	latencySampleCapacity uint
+	errorSampleCapacity   uint
+	maxSpanNames          int
+	maxActiveSpans        int
+
+	stuckSpanThreshold      time.Duration
+	stuckSpanNameThresholds map[string]time.Duration
+	stuckSpanScanInterval   time.Duration
+	startStack              bool
+	meterProvider           metric.MeterProvider
+}
+
+// SpanProcessorOption configures a SpanProcessor.
+type SpanProcessorOption interface {
+	apply(*config)
+}
+
+type optionFunc func(*config)
+
+func (o optionFunc) apply(c *config) {
+	o(c)
+}
+
+// newConfig creates a new config struct and applies opts to it.
+func newConfig(opts ...SpanProcessorOption) *config {
+	c := &config{
+		boundaries:            defaultBoundaries,
+		latencySampleCapacity: defaultBucketCapacity,
+		errorSampleCapacity:   defaultBucketCapacity,
+		maxSpanNames:          defaultMaxSpanNames,
+		stuckSpanScanInterval: defaultStuckSpanScanInterval,
+		meterProvider:         otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt.apply(c)
+	}
+	return c
+}
+
+// WithLatencyBoundaries sets the boundaries of the latency buckets the
+// successful spans are sampled in. The first bucket holds the spans with a
+// latency lower than the smallest boundary, and the last one the spans with a
+// latency greater than or equal to the largest boundary.
+//
+// By default the boundaries are 10µs, 100µs, 1ms, 10ms, 100ms, 1s, 10s and
+// 100s.
+func WithLatencyBoundaries(boundaries ...time.Duration) SpanProcessorOption {
+	return optionFunc(func(c *config) {
+		c.boundaries = newBoundaries(append([]time.Duration(nil), boundaries...))
+	})
+}
+
+// WithLatencySampleCapacity sets the number of spans sampled in every latency
+// bucket of a span name. The default is 10. Negative values are ignored.
+func WithLatencySampleCapacity(n int) SpanProcessorOption {
+	return optionFunc(func(c *config) {
+		if n >= 0 {
+			c.latencySampleCapacity = uint(n)
+		}
+	})
+}
+
+// WithErrorSampleCapacity sets the number of error spans sampled for a span
+// name. The default is 10. Negative values are ignored.
+func WithErrorSampleCapacity(n int) SpanProcessorOption {
+	return optionFunc(func(c *config) {
+		if n >= 0 {
+			c.errorSampleCapacity = uint(n)
+		}
+	})
+}
+
+// WithMaxSpanNames sets the maximum number of span names for which samples
+// are stored. When a span of a new name ends while the maximum is reached,
+// the samples of the least recently ended span name are dropped. The default
+// is 1000. If n is less than or equal to zero, the number of span names is
+// not limited.
+func WithMaxSpanNames(n int) SpanProcessorOption {
+	return optionFunc(func(c *config) {
+		c.maxSpanNames = n
+	})
+}
+
+// WithMaxActiveSpans sets the maximum number of active spans tracked. Spans
+// started while the maximum is reached are not listed as active, but are
+// still sampled when they end. If n is less than or equal to zero, the
+// default, the number of active spans is not limited.
+func WithMaxActiveSpans(n int) SpanProcessorOption {
+	return optionFunc(func(c *config) {
+		c.maxActiveSpans = n
+	})
+}
+
+// WithStuckSpanThreshold enables the detection of stuck spans: the active
+// spans are periodically scanned, and the ones open longer than threshold are
+// listed by the handler returned by NewStuckSpansHandler and counted by the
+// zpages.spans.stuck metric. Use WithStuckSpanNameThreshold to set the
+// threshold of a span name. The scans are stopped by the Shutdown method of
+// the SpanProcessor.
+func WithStuckSpanThreshold(threshold time.Duration) SpanProcessorOption {
+	return optionFunc(func(c *config) {
+		c.stuckSpanThreshold = threshold
+	})
+}
+
+// WithStuckSpanNameThreshold enables the detection of stuck spans, see
+// WithStuckSpanThreshold, and sets the threshold of the spans named name. If
+// threshold is less than or equal to zero, the spans named name are never
+// considered stuck.
+func WithStuckSpanNameThreshold(name string, threshold time.Duration) SpanProcessorOption {
+	return optionFunc(func(c *config) {
+		if c.stuckSpanNameThresholds == nil {
+			c.stuckSpanNameThresholds = make(map[string]time.Duration)
+		}
+		c.stuckSpanNameThresholds[name] = threshold
+	})
+}
+
+// WithStuckSpanScanInterval sets the interval between two scans of the active
+// spans for stuck spans. The default is 10 seconds. Values less than or equal
+// to zero are ignored.
+func WithStuckSpanScanInterval(interval time.Duration) SpanProcessorOption {
+	return optionFunc(func(c *config) {
+		if interval > 0 {
+			c.stuckSpanScanInterval = interval
+		}
+	})
+}
+
+// WithStartStack captures the stack trace of the goroutine starting every
+// active span, displayed with the stuck spans. Capturing the stack trace
+// adds an overhead to the start of every span.
+func WithStartStack() SpanProcessorOption {
+	return optionFunc(func(c *config) {
+		c.startStack = true
+	})
+}
+
+// WithMeterProvider sets the MeterProvider used to record the
+// zpages.spans.stuck metric. By default the global MeterProvider is used.
+func WithMeterProvider(mp metric.MeterProvider) SpanProcessorOption {
+	return optionFunc(func(c *config) {
+		c.meterProvider = mp
+	})
+}
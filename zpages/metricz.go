@@ -0,0 +1,343 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zpages // import "go.opentelemetry.io/contrib/zpages"
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// instrumentNameQueryField is the header for the instrument name filter.
+const instrumentNameQueryField = "zinstrument"
+
+// MetricReader is an sdkmetric.Reader that collects the metrics displayed by
+// the metricz handler. It uses the cumulative temporality so that the
+// current value of every instrument is displayed.
+type MetricReader struct {
+	*sdkmetric.ManualReader
+}
+
+var _ sdkmetric.Reader = (*MetricReader)(nil)
+
+// NewMetricReader returns a new MetricReader. It needs to be registered with
+// a MeterProvider, using sdkmetric.WithReader, to collect its metrics.
+func NewMetricReader() *MetricReader {
+	return &MetricReader{ManualReader: sdkmetric.NewManualReader()}
+}
+
+// metriczData contains data for the metricz template.
+type metriczData struct {
+	Filter   string
+	Error    string
+	Resource string
+	Scopes   []metricScopeData
+}
+
+type metricScopeData struct {
+	Name    string
+	Version string
+	Metrics []metricData
+}
+
+type metricData struct {
+	Name        string
+	Description string
+	Unit        string
+	Type        string
+	Points      []metricPointData
+}
+
+// metricPointData contains a data point of a metric. Only one of Value,
+// Histogram and ExponentialHistogram is set.
+type metricPointData struct {
+	Attributes           string
+	Value                string
+	Histogram            *histogramData
+	ExponentialHistogram *exponentialHistogramData
+}
+
+type histogramData struct {
+	Count   uint64
+	Sum     string
+	Min     string
+	Max     string
+	Buckets []histogramBucket
+}
+
+type exponentialHistogramData struct {
+	histogramData
+	Scale         int32
+	Base          string
+	ZeroCount     uint64
+	ZeroThreshold float64
+}
+
+// histogramBucket is a bucket of a histogram bar chart.
+type histogramBucket struct {
+	Bounds string
+	Count  uint64
+	// Width is the bar width, in percents of the largest bucket count.
+	Width float64
+}
+
+var _ http.Handler = (*metriczHandler)(nil)
+
+type metriczHandler struct {
+	r *MetricReader
+}
+
+// NewMetriczHandler returns an http.Handler that can be used to serve HTTP
+// requests for metric zpages, displaying the metrics collected by r.
+func NewMetriczHandler(r *MetricReader) http.Handler {
+	return &metriczHandler{r: r}
+}
+
+// ServeHTTP implements the http.Handler and is capable of serving "metricz"
+// HTTP requests.
+//
+// Only the instruments whose name contains the zinstrument query parameter
+// are displayed.
+func (mh *metriczHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := r.ParseForm(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := headerTemplate.Execute(w, headerData{Title: "Metrics"}); err != nil {
+		log.Printf("zpages: executing template: %v", err)
+	}
+	if err := metriczTemplate.Execute(w, mh.getMetriczData(r)); err != nil {
+		log.Printf("zpages: executing template: %v", err)
+	}
+	if err := footerTemplate.Execute(w, nil); err != nil {
+		log.Printf("zpages: executing template: %v", err)
+	}
+}
+
+func (mh *metriczHandler) getMetriczData(r *http.Request) metriczData {
+	data := metriczData{Filter: r.Form.Get(instrumentNameQueryField)}
+	var rm metricdata.ResourceMetrics
+	if err := mh.r.Collect(r.Context(), &rm); err != nil {
+		data.Error = err.Error()
+		return data
+	}
+	if rm.Resource != nil {
+		data.Resource = formatAttributeSet(*rm.Resource.Set())
+	}
+
+	for _, sm := range rm.ScopeMetrics {
+		scope := metricScopeData{Name: sm.Scope.Name, Version: sm.Scope.Version}
+		for _, m := range sm.Metrics {
+			if !strings.Contains(m.Name, data.Filter) {
+				continue
+			}
+			scope.Metrics = append(scope.Metrics, newMetricData(m))
+		}
+		if len(scope.Metrics) == 0 {
+			continue
+		}
+		sort.Slice(scope.Metrics, func(i, j int) bool {
+			return scope.Metrics[i].Name < scope.Metrics[j].Name
+		})
+		data.Scopes = append(data.Scopes, scope)
+	}
+	sort.Slice(data.Scopes, func(i, j int) bool {
+		if data.Scopes[i].Name != data.Scopes[j].Name {
+			return data.Scopes[i].Name < data.Scopes[j].Name
+		}
+		return data.Scopes[i].Version < data.Scopes[j].Version
+	})
+	return data
+}
+
+func newMetricData(m metricdata.Metrics) metricData {
+	out := metricData{Name: m.Name, Description: m.Description, Unit: m.Unit}
+	switch data := m.Data.(type) {
+	case metricdata.Gauge[int64]:
+		out.Type = "Gauge"
+		out.Points = valuePoints(data.DataPoints)
+	case metricdata.Gauge[float64]:
+		out.Type = "Gauge"
+		out.Points = valuePoints(data.DataPoints)
+	case metricdata.Sum[int64]:
+		out.Type = sumType(data.IsMonotonic)
+		out.Points = valuePoints(data.DataPoints)
+	case metricdata.Sum[float64]:
+		out.Type = sumType(data.IsMonotonic)
+		out.Points = valuePoints(data.DataPoints)
+	case metricdata.Histogram[int64]:
+		out.Type = "Histogram"
+		out.Points = histogramPoints(data.DataPoints)
+	case metricdata.Histogram[float64]:
+		out.Type = "Histogram"
+		out.Points = histogramPoints(data.DataPoints)
+	case metricdata.ExponentialHistogram[int64]:
+		out.Type = "Exponential Histogram"
+		out.Points = exponentialHistogramPoints(data.DataPoints)
+	case metricdata.ExponentialHistogram[float64]:
+		out.Type = "Exponential Histogram"
+		out.Points = exponentialHistogramPoints(data.DataPoints)
+	case metricdata.Summary:
+		out.Type = "Summary"
+		out.Points = summaryPoints(data.DataPoints)
+	default:
+		out.Type = fmt.Sprintf("%T", m.Data)
+	}
+	sort.Slice(out.Points, func(i, j int) bool {
+		return out.Points[i].Attributes < out.Points[j].Attributes
+	})
+	return out
+}
+
+func sumType(monotonic bool) string {
+	if monotonic {
+		return "Sum (monotonic)"
+	}
+	return "Sum"
+}
+
+func valuePoints[N int64 | float64](dps []metricdata.DataPoint[N]) []metricPointData {
+	out := make([]metricPointData, 0, len(dps))
+	for _, dp := range dps {
+		out = append(out, metricPointData{
+			Attributes: formatAttributeSet(dp.Attributes),
+			Value:      fmt.Sprint(dp.Value),
+		})
+	}
+	return out
+}
+
+func histogramPoints[N int64 | float64](dps []metricdata.HistogramDataPoint[N]) []metricPointData {
+	out := make([]metricPointData, 0, len(dps))
+	for _, dp := range dps {
+		h := newHistogramData(dp.Count, dp.Sum, dp.Min, dp.Max)
+		for i, count := range dp.BucketCounts {
+			lower, upper := "-∞", "+∞"
+			if i > 0 && i <= len(dp.Bounds) {
+				lower = fmt.Sprint(dp.Bounds[i-1])
+			}
+			if i < len(dp.Bounds) {
+				upper = fmt.Sprint(dp.Bounds[i])
+			}
+			h.Buckets = append(h.Buckets, histogramBucket{Bounds: fmt.Sprintf("(%s, %s]", lower, upper), Count: count})
+		}
+		setWidths(h.Buckets)
+		out = append(out, metricPointData{Attributes: formatAttributeSet(dp.Attributes), Histogram: &h})
+	}
+	return out
+}
+
+func exponentialHistogramPoints[N int64 | float64](dps []metricdata.ExponentialHistogramDataPoint[N]) []metricPointData {
+	out := make([]metricPointData, 0, len(dps))
+	for _, dp := range dps {
+		h := exponentialHistogramData{
+			histogramData: newHistogramData(dp.Count, dp.Sum, dp.Min, dp.Max),
+			Scale:         dp.Scale,
+			Base:          fmt.Sprintf("%.6g", math.Exp2(math.Exp2(-float64(dp.Scale)))),
+			ZeroCount:     dp.ZeroCount,
+			ZeroThreshold: dp.ZeroThreshold,
+		}
+		// The negative buckets are displayed first, from the lowest values.
+		for i := len(dp.NegativeBucket.Counts) - 1; i >= 0; i-- {
+			lower, upper := exponentialBucketBounds(dp.Scale, dp.NegativeBucket.Offset+int32(i))
+			h.Buckets = append(h.Buckets, histogramBucket{
+				Bounds: fmt.Sprintf("[-%.6g, -%.6g)", upper, lower),
+				Count:  dp.NegativeBucket.Counts[i],
+			})
+		}
+		h.Buckets = append(h.Buckets, histogramBucket{
+			Bounds: fmt.Sprintf("[-%g, %g]", dp.ZeroThreshold, dp.ZeroThreshold),
+			Count:  dp.ZeroCount,
+		})
+		for i, count := range dp.PositiveBucket.Counts {
+			lower, upper := exponentialBucketBounds(dp.Scale, dp.PositiveBucket.Offset+int32(i))
+			h.Buckets = append(h.Buckets, histogramBucket{
+				Bounds: fmt.Sprintf("(%.6g, %.6g]", lower, upper),
+				Count:  count,
+			})
+		}
+		setWidths(h.Buckets)
+		out = append(out, metricPointData{Attributes: formatAttributeSet(dp.Attributes), ExponentialHistogram: &h})
+	}
+	return out
+}
+
+// exponentialBucketBounds returns the bounds of the absolute values of the
+// exponential histogram bucket at index with the given scale.
+func exponentialBucketBounds(scale, index int32) (lower, upper float64) {
+	factor := math.Exp2(-float64(scale))
+	return math.Exp2(float64(index) * factor), math.Exp2(float64(index+1) * factor)
+}
+
+func summaryPoints(dps []metricdata.SummaryDataPoint) []metricPointData {
+	out := make([]metricPointData, 0, len(dps))
+	for _, dp := range dps {
+		quantiles := make([]string, 0, len(dp.QuantileValues))
+		for _, q := range dp.QuantileValues {
+			quantiles = append(quantiles, fmt.Sprintf("p%g=%g", q.Quantile*100, q.Value))
+		}
+		out = append(out, metricPointData{
+			Attributes: formatAttributeSet(dp.Attributes),
+			Value:      fmt.Sprintf("count=%d sum=%g %s", dp.Count, dp.Sum, strings.Join(quantiles, " ")),
+		})
+	}
+	return out
+}
+
+func newHistogramData[N int64 | float64](count uint64, sum N, min, max metricdata.Extrema[N]) histogramData {
+	h := histogramData{Count: count, Sum: fmt.Sprint(sum)}
+	if v, ok := min.Value(); ok {
+		h.Min = fmt.Sprint(v)
+	}
+	if v, ok := max.Value(); ok {
+		h.Max = fmt.Sprint(v)
+	}
+	return h
+}
+
+// setWidths sets the bar widths of buckets relative to the largest count.
+func setWidths(buckets []histogramBucket) {
+	var largest uint64
+	for _, b := range buckets {
+		if b.Count > largest {
+			largest = b.Count
+		}
+	}
+	if largest == 0 {
+		return
+	}
+	for i := range buckets {
+		buckets[i].Width = math.Round(float64(buckets[i].Count)/float64(largest)*10000) / 100
+	}
+}
+
+// formatAttributeSet returns the attributes of set as "{k1=v1, k2=v2}".
+func formatAttributeSet(set attribute.Set) string {
+	s := make([]string, 0, set.Len())
+	for iter := set.Iter(); iter.Next(); {
+		kv := iter.Attribute()
+		s = append(s, fmt.Sprintf("%s=%v", kv.Key, kv.Value.Emit()))
+	}
+	return "{" + strings.Join(s, ", ") + "}"
+}
@@ -0,0 +1,164 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zpages
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func newTestMeterProvider(t *testing.T) (*MetricReader, metric.Meter) {
+	r := NewMetricReader()
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(r),
+		sdkmetric.WithResource(resource.NewSchemaless(attribute.String("service.name", "test"))),
+		sdkmetric.WithView(sdkmetric.NewView(
+			sdkmetric.Instrument{Name: "exponential"},
+			sdkmetric.Stream{Aggregation: sdkmetric.AggregationBase2ExponentialHistogram{MaxSize: 160, MaxScale: 1}},
+		)),
+	)
+	t.Cleanup(func() { assert.NoError(t, mp.Shutdown(context.Background())) })
+	return r, mp.Meter("scope", metric.WithInstrumentationVersion("v1"))
+}
+
+func getMetriczData(t *testing.T, r *MetricReader, query string) metriczData {
+	req := httptest.NewRequest(http.MethodGet, "/metricz?"+query, nil)
+	require.NoError(t, req.ParseForm())
+	return (&metriczHandler{r: r}).getMetriczData(req)
+}
+
+func TestMetricz(t *testing.T) {
+	r, meter := newTestMeterProvider(t)
+	ctx := context.Background()
+
+	counter, err := meter.Int64Counter("counter", metric.WithDescription("A counter"), metric.WithUnit("{request}"))
+	require.NoError(t, err)
+	counter.Add(ctx, 2, metric.WithAttributes(attribute.String("b", "2"), attribute.String("a", "1")))
+	counter.Add(ctx, 1)
+	upDown, err := meter.Float64UpDownCounter("updown")
+	require.NoError(t, err)
+	upDown.Add(ctx, -1.5)
+	histogram, err := meter.Float64Histogram("histogram", metric.WithExplicitBucketBoundaries(1, 10))
+	require.NoError(t, err)
+	histogram.Record(ctx, 0.5)
+	histogram.Record(ctx, 5)
+	histogram.Record(ctx, 7)
+	exponential, err := meter.Int64Histogram("exponential")
+	require.NoError(t, err)
+	exponential.Record(ctx, 0)
+	exponential.Record(ctx, 3)
+	exponential.Record(ctx, -3)
+
+	data := getMetriczData(t, r, "")
+	assert.Empty(t, data.Error)
+	assert.Equal(t, "{service.name=test}", data.Resource)
+	require.Len(t, data.Scopes, 1)
+	assert.Equal(t, "scope", data.Scopes[0].Name)
+	assert.Equal(t, "v1", data.Scopes[0].Version)
+	metrics := data.Scopes[0].Metrics
+	require.Len(t, metrics, 4)
+
+	assert.Equal(t, metricData{
+		Name:        "counter",
+		Description: "A counter",
+		Unit:        "{request}",
+		Type:        "Sum (monotonic)",
+		Points: []metricPointData{
+			{Attributes: "{a=1, b=2}", Value: "2"},
+			{Attributes: "{}", Value: "1"},
+		},
+	}, metrics[0])
+
+	assert.Equal(t, "exponential", metrics[1].Name)
+	assert.Equal(t, "Exponential Histogram", metrics[1].Type)
+	require.Len(t, metrics[1].Points, 1)
+	exp := metrics[1].Points[0].ExponentialHistogram
+	require.NotNil(t, exp)
+	assert.Equal(t, int32(1), exp.Scale)
+	assert.Equal(t, "1.41421", exp.Base)
+	assert.Equal(t, uint64(1), exp.ZeroCount)
+	assert.Equal(t, uint64(3), exp.Count)
+	assert.Equal(t, "-3", exp.Min)
+	assert.Equal(t, "3", exp.Max)
+	assert.Equal(t, []histogramBucket{
+		{Bounds: "[-4, -2.82843)", Count: 1, Width: 100},
+		{Bounds: "[-0, 0]", Count: 1, Width: 100},
+		{Bounds: "(2.82843, 4]", Count: 1, Width: 100},
+	}, exp.Buckets)
+
+	assert.Equal(t, "histogram", metrics[2].Name)
+	require.Len(t, metrics[2].Points, 1)
+	h := metrics[2].Points[0].Histogram
+	require.NotNil(t, h)
+	assert.Equal(t, histogramData{
+		Count: 3,
+		Sum:   "12.5",
+		Min:   "0.5",
+		Max:   "7",
+		Buckets: []histogramBucket{
+			{Bounds: "(-∞, 1]", Count: 1, Width: 50},
+			{Bounds: "(1, 10]", Count: 2, Width: 100},
+			{Bounds: "(10, +∞]", Count: 0, Width: 0},
+		},
+	}, *h)
+
+	assert.Equal(t, metricData{
+		Name:   "updown",
+		Type:   "Sum",
+		Points: []metricPointData{{Attributes: "{}", Value: "-1.5"}},
+	}, metrics[3])
+
+	rec := httptest.NewRecorder()
+	NewMetriczHandler(r).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metricz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, "<h3>counter</h3>")
+	assert.Contains(t, body, "Scale: 1, Base: 1.41421, Zero Count: 1")
+	assert.Contains(t, body, "width: 50%")
+}
+
+func TestMetriczFilter(t *testing.T) {
+	r, meter := newTestMeterProvider(t)
+	for _, name := range []string{"http.server.duration", "http.client.duration", "db.client.duration"} {
+		counter, err := meter.Int64Counter(name)
+		require.NoError(t, err)
+		counter.Add(context.Background(), 1)
+	}
+
+	data := getMetriczData(t, r, "zinstrument=http.")
+	require.Len(t, data.Scopes, 1)
+	require.Len(t, data.Scopes[0].Metrics, 2)
+	assert.Equal(t, "http.client.duration", data.Scopes[0].Metrics[0].Name)
+	assert.Equal(t, "http.server.duration", data.Scopes[0].Metrics[1].Name)
+
+	rec := httptest.NewRecorder()
+	NewMetriczHandler(r).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metricz?zinstrument=rpc", nil))
+	assert.Contains(t, rec.Body.String(), `No metrics matching "rpc".`)
+}
+
+func TestMetriczNotRegistered(t *testing.T) {
+	data := getMetriczData(t, NewMetricReader(), "")
+	assert.Equal(t, sdkmetric.ErrReaderNotRegistered.Error(), data.Error)
+}
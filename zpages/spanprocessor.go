@@ -16,8 +16,10 @@
 package zpages // import "go.opentelemetry.io/contrib/zpages"
 
 import (
+	"container/list"
 	"context"
 	"sync"
+	"sync/atomic"
 
 	"go.opentelemetry.io/otel/codes"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -36,46 +38,114 @@ type perMethodSummary struct {
 // SpanProcessor is an sdktrace.SpanProcessor implementation that exposes zpages functionality for opentelemetry-go.
 //
 // It tracks all active spans, and stores samples of spans based on latency for non errored spans,
-// and samples for errored spans.
+// and samples for errored spans. The number of samples, of span names and of active spans it keeps
+// are set with the SpanProcessorOptions passed to NewSpanProcessor.
 type SpanProcessor struct {
+	cfg *config
+
 	// Cannot keep track of the active Spans per name because the Span interface,
 	// allows the name to be changed, and that will leak memory.
 	activeSpansStore sync.Map
-	spanSampleStores sync.Map
+	// activeSpanCount is the number of spans in activeSpansStore.
+	activeSpanCount atomic.Int64
+	// untrackedActiveSpans is the number of spans not added to
+	// activeSpansStore because cfg.maxActiveSpans was reached.
+	untrackedActiveSpans atomic.Uint64
+	// startStacks holds the start stack of the spans of activeSpansStore,
+	// if cfg.startStack is set.
+	startStacks sync.Map
+
+	spanSampleStoresMu sync.Mutex // protects spanSampleStores and spanNames.
+	spanSampleStores   map[string]*list.Element
+	// spanNames holds the *sampleStore of spanSampleStores, the most
+	// recently used first.
+	spanNames *list.List
+	// evictedSpanNames is the number of sampleStore evicted because
+	// cfg.maxSpanNames was reached.
+	evictedSpanNames atomic.Uint64
+
+	// traces indexes the spans of activeSpansStore and spanSampleStores by
+	// trace ID.
+	traces traceIndex
+
+	// watchdog detects the stuck spans, it is nil if not enabled.
+	watchdog *watchdog
 }
 
 // NewSpanProcessor returns a new SpanProcessor.
-func NewSpanProcessor() *SpanProcessor {
-	return &SpanProcessor{}
+func NewSpanProcessor(opts ...SpanProcessorOption) *SpanProcessor {
+	ssm := &SpanProcessor{
+		cfg:              newConfig(opts...),
+		spanSampleStores: make(map[string]*list.Element),
+		spanNames:        list.New(),
+	}
+	ssm.watchdog = newWatchdog(ssm)
+	return ssm
 }
 
 // OnStart adds span as active and reports it with zpages.
 func (ssm *SpanProcessor) OnStart(_ context.Context, span sdktrace.ReadWriteSpan) {
 	sc := span.SpanContext()
-	if sc.IsValid() {
-		ssm.activeSpansStore.Store(spanKey(sc), span)
+	if !sc.IsValid() {
+		return
+	}
+	if n := ssm.activeSpanCount.Add(1); ssm.cfg.maxActiveSpans > 0 && n > int64(ssm.cfg.maxActiveSpans) {
+		ssm.activeSpanCount.Add(-1)
+		ssm.untrackedActiveSpans.Add(1)
+		return
 	}
+	if ssm.cfg.startStack {
+		ssm.startStacks.Store(spanKey(sc), startStack())
+	}
+	ssm.activeSpansStore.Store(spanKey(sc), span)
+	ssm.traces.add(span)
 }
 
 // OnEnd processes all spans and reports them with zpages.
 func (ssm *SpanProcessor) OnEnd(span sdktrace.ReadOnlySpan) {
 	sc := span.SpanContext()
 	if sc.IsValid() {
-		ssm.activeSpansStore.Delete(spanKey(sc))
+		if _, ok := ssm.activeSpansStore.LoadAndDelete(spanKey(sc)); ok {
+			ssm.activeSpanCount.Add(-1)
+		}
+		ssm.startStacks.Delete(spanKey(sc))
 	}
 
-	name := span.Name()
-	value, ok := ssm.spanSampleStores.Load(name)
-	if !ok {
-		value, _ = ssm.spanSampleStores.LoadOrStore(name, newSampleStore(defaultBucketCapacity, defaultBucketCapacity))
+	ssm.sampleStore(span.Name()).sampleSpan(span, &ssm.traces)
+}
+
+// sampleStore returns the sampleStore for the given name, creating it if it
+// doesn't exist. The least recently used sampleStore is evicted if the
+// maximum number of span names is exceeded.
+func (ssm *SpanProcessor) sampleStore(name string) *sampleStore {
+	ssm.spanSampleStoresMu.Lock()
+	if e, ok := ssm.spanSampleStores[name]; ok {
+		ssm.spanNames.MoveToFront(e)
+		ssm.spanSampleStoresMu.Unlock()
+		return e.Value.(*sampleStore)
+	}
+	s := newSampleStore(name, ssm.cfg.boundaries, ssm.cfg.latencySampleCapacity, ssm.cfg.errorSampleCapacity)
+	ssm.spanSampleStores[name] = ssm.spanNames.PushFront(s)
+	var evicted *sampleStore
+	if ssm.cfg.maxSpanNames > 0 && ssm.spanNames.Len() > ssm.cfg.maxSpanNames {
+		evicted = ssm.spanNames.Remove(ssm.spanNames.Back()).(*sampleStore)
+		delete(ssm.spanSampleStores, evicted.name)
+		ssm.evictedSpanNames.Add(1)
 	}
-	value.(*sampleStore).sampleSpan(span)
+	ssm.spanSampleStoresMu.Unlock()
+
+	if evicted != nil {
+		evicted.evict(&ssm.traces)
+	}
+	return s
 }
 
-// Shutdown does nothing.
+// Shutdown stops the detection of stuck spans, if enabled.
 func (ssm *SpanProcessor) Shutdown(context.Context) error {
-	// Do nothing
-	return nil
+	if ssm.watchdog == nil {
+		return nil
+	}
+	return ssm.watchdog.shutdown()
 }
 
 // ForceFlush does nothing.
@@ -88,19 +158,27 @@ func (ssm *SpanProcessor) ForceFlush(context.Context) error {
 //
 // It returns nil if it doesn't exist.
 func (ssm *SpanProcessor) spanStoreForName(name string) *sampleStore {
-	if value, ok := ssm.spanSampleStores.Load(name); ok {
-		return value.(*sampleStore)
+	ssm.spanSampleStoresMu.Lock()
+	defer ssm.spanSampleStoresMu.Unlock()
+	if e, ok := ssm.spanSampleStores[name]; ok {
+		return e.Value.(*sampleStore)
 	}
 	return nil
 }
 
 // spansPerMethod returns a summary of what spans are being stored for each span name.
 func (ssm *SpanProcessor) spansPerMethod() map[string]*perMethodSummary {
-	out := make(map[string]*perMethodSummary)
-	ssm.spanSampleStores.Range(func(name, s interface{}) bool {
-		out[name.(string)] = s.(*sampleStore).perMethodSummary()
-		return true
-	})
+	ssm.spanSampleStoresMu.Lock()
+	stores := make([]*sampleStore, 0, ssm.spanNames.Len())
+	for e := ssm.spanNames.Front(); e != nil; e = e.Next() {
+		stores = append(stores, e.Value.(*sampleStore))
+	}
+	ssm.spanSampleStoresMu.Unlock()
+
+	out := make(map[string]*perMethodSummary, len(stores))
+	for _, s := range stores {
+		out[s.name] = s.perMethodSummary()
+	}
 	ssm.activeSpansStore.Range(func(_, sp interface{}) bool {
 		span := sp.(sdktrace.ReadOnlySpan)
 		if pms, ok := out[span.Name()]; ok {
@@ -113,6 +191,24 @@ func (ssm *SpanProcessor) spansPerMethod() map[string]*perMethodSummary {
 	return out
 }
 
+// limits returns the limits of the SpanProcessor and how often they were
+// reached.
+func (ssm *SpanProcessor) limits() limitsData {
+	ssm.spanSampleStoresMu.Lock()
+	spanNames := ssm.spanNames.Len()
+	ssm.spanSampleStoresMu.Unlock()
+	return limitsData{
+		SpanNames:             spanNames,
+		MaxSpanNames:          ssm.cfg.maxSpanNames,
+		EvictedSpanNames:      ssm.evictedSpanNames.Load(),
+		ActiveSpans:           ssm.activeSpanCount.Load(),
+		MaxActiveSpans:        ssm.cfg.maxActiveSpans,
+		UntrackedActiveSpans:  ssm.untrackedActiveSpans.Load(),
+		LatencySampleCapacity: ssm.cfg.latencySampleCapacity,
+		ErrorSampleCapacity:   ssm.cfg.errorSampleCapacity,
+	}
+}
+
 // activeSpans returns the active spans for the given name.
 func (ssm *SpanProcessor) activeSpans(name string) []sdktrace.ReadOnlySpan {
 	var out []sdktrace.ReadOnlySpan
@@ -135,6 +231,11 @@ func (ssm *SpanProcessor) errorSpans(name string) []sdktrace.ReadOnlySpan {
 	return s.errorSpans()
 }
 
+// traceSpans returns the active and sampled spans of the trace id.
+func (ssm *SpanProcessor) traceSpans(id trace.TraceID) []sdktrace.ReadOnlySpan {
+	return ssm.traces.trace(id)
+}
+
 // spansByLatency returns a sample of successful spans.
 //
 // minLatency is the minimum latency of spans to be returned.
@@ -152,16 +253,23 @@ func (ssm *SpanProcessor) spansByLatency(name string, latencyBucketIndex int) []
 // It contains sample of spans for error requests (status code is codes.Error);
 // and a sample of spans for successful requests, bucketed by latency.
 type sampleStore struct {
+	name       string
+	boundaries *boundaries
 	sync.Mutex // protects everything below.
 	latency    []*bucket
 	errors     *bucket
+	// evicted is true once the sampleStore is no longer used by the
+	// SpanProcessor.
+	evicted bool
 }
 
 // newSampleStore creates a sampleStore.
-func newSampleStore(latencyBucketSize uint, errorBucketSize uint) *sampleStore {
+func newSampleStore(name string, boundaries *boundaries, latencyBucketSize uint, errorBucketSize uint) *sampleStore {
 	s := &sampleStore{
-		latency: make([]*bucket, defaultBoundaries.numBuckets()),
-		errors:  newBucket(errorBucketSize),
+		name:       name,
+		boundaries: boundaries,
+		latency:    make([]*bucket, boundaries.numBuckets()),
+		errors:     newBucket(errorBucketSize),
 	}
 	for i := range s.latency {
 		s.latency[i] = newBucket(latencyBucketSize)
@@ -196,22 +304,55 @@ func (ss *sampleStore) errorSpans() []sdktrace.ReadOnlySpan {
 }
 
 // sampleSpan removes adds to the corresponding latency or error bucket.
-func (ss *sampleStore) sampleSpan(span sdktrace.ReadOnlySpan) {
+//
+// The span is kept in traces if it is sampled, and the span it replaces in its
+// bucket is removed from traces.
+func (ss *sampleStore) sampleSpan(span sdktrace.ReadOnlySpan, traces *traceIndex) {
 	code := span.Status().Code
 
 	ss.Lock()
 	defer ss.Unlock()
-	if code == codes.Error {
-		ss.errors.add(span)
+	if ss.evicted {
+		// Evicted by a concurrent call, the span is dropped.
+		traces.remove(span)
 		return
 	}
+	b := ss.errors
+	if code != codes.Error {
+		latency := span.EndTime().Sub(span.StartTime())
+		// In case of time skew or wrong time, sample as 0 latency.
+		if latency < 0 {
+			latency = 0
+		}
+		b = ss.latency[ss.boundaries.getBucketIndex(latency)]
+	}
+
+	// The index is updated while holding the lock so that a span evicted by
+	// a concurrent call is not added back.
+	added, evicted := b.add(span)
+	if evicted != nil {
+		traces.remove(evicted)
+	}
+	if added {
+		traces.add(span)
+	} else {
+		traces.remove(span)
+	}
+}
 
-	latency := span.EndTime().Sub(span.StartTime())
-	// In case of time skew or wrong time, sample as 0 latency.
-	if latency < 0 {
-		latency = 0
+// evict marks the sampleStore as evicted and removes its spans from traces.
+func (ss *sampleStore) evict(traces *traceIndex) {
+	ss.Lock()
+	defer ss.Unlock()
+	ss.evicted = true
+	for _, b := range ss.latency {
+		for _, span := range b.spans() {
+			traces.remove(span)
+		}
+	}
+	for _, span := range ss.errors.spans() {
+		traces.remove(span)
 	}
-	ss.latency[defaultBoundaries.getBucketIndex(latency)].add(span)
 }
 
 func spanKey(sc trace.SpanContext) [24]byte {
@@ -16,8 +16,11 @@ package zpages
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"sort"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -208,3 +211,135 @@ func createActiveSpans(tracer trace.Tracer, spanName string, numSpans int) []tra
 	}
 	return spans
 }
+
+func TestSpanProcessorTraceSpans(t *testing.T) {
+	zsp := NewSpanProcessor()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(zsp)).Tracer("test")
+
+	ctx, root := tracer.Start(context.Background(), "root")
+	traceID := root.SpanContext().TraceID()
+	require.Len(t, zsp.traceSpans(traceID), 1)
+
+	// Only one span per bucket is sampled every samplePeriod.
+	start := time.Now()
+	_, sampled := tracer.Start(ctx, "span", trace.WithTimestamp(start))
+	sampled.End(trace.WithTimestamp(start))
+	_, dropped := tracer.Start(ctx, "span", trace.WithTimestamp(start))
+	dropped.End(trace.WithTimestamp(start))
+	spans := zsp.traceSpans(traceID)
+	require.Len(t, spans, 2)
+	ids := []trace.SpanID{spans[0].SpanContext().SpanID(), spans[1].SpanContext().SpanID()}
+	assert.ElementsMatch(t, []trace.SpanID{root.SpanContext().SpanID(), sampled.SpanContext().SpanID()}, ids)
+
+	// Evicted samples are removed from the index.
+	for i := 1; i <= defaultBucketCapacity; i++ {
+		ts := start.Add(time.Duration(i) * samplePeriod)
+		_, s := tracer.Start(context.Background(), "span", trace.WithTimestamp(ts))
+		s.End(trace.WithTimestamp(ts))
+	}
+	spans = zsp.traceSpans(traceID)
+	require.Len(t, spans, 1)
+	assert.Equal(t, root.SpanContext().SpanID(), spans[0].SpanContext().SpanID())
+
+	root.End()
+	assert.Len(t, zsp.traceSpans(traceID), 1, "ended span sampled")
+}
+
+func endSpan(tracer trace.Tracer, name string, start time.Time, latency time.Duration, code codes.Code) trace.Span {
+	_, span := tracer.Start(context.Background(), name, trace.WithTimestamp(start))
+	span.SetStatus(code, "")
+	span.End(trace.WithTimestamp(start.Add(latency)))
+	return span
+}
+
+func TestSpanProcessorLatencyBoundaries(t *testing.T) {
+	zsp := NewSpanProcessor(WithLatencyBoundaries(time.Second))
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(zsp)).Tracer("test")
+
+	start := time.Now()
+	endSpan(tracer, "span", start, 2*time.Second, codes.Ok)
+	assert.Equal(t, []int{0, 1}, zsp.spansPerMethod()["span"].latencySpans)
+	assert.Len(t, zsp.spansByLatency("span", 1), 1)
+
+	th := &tracezHandler{sp: zsp}
+	assert.Equal(t, []string{">0s", ">1s"}, th.getSummaryTableData().LatencyBucketNames)
+}
+
+func TestSpanProcessorSampleCapacity(t *testing.T) {
+	zsp := NewSpanProcessor(WithLatencySampleCapacity(1), WithErrorSampleCapacity(0))
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(zsp)).Tracer("test")
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		ts := start.Add(time.Duration(i) * samplePeriod)
+		endSpan(tracer, "span", ts, 0, codes.Ok)
+		endSpan(tracer, "span", ts, 0, codes.Error)
+	}
+	assert.Len(t, zsp.spansByLatency("span", 0), 1)
+	assert.Len(t, zsp.errorSpans("span"), 0)
+
+	limits := zsp.limits()
+	assert.Equal(t, uint(1), limits.LatencySampleCapacity)
+	assert.Equal(t, uint(0), limits.ErrorSampleCapacity)
+}
+
+func TestSpanProcessorMaxSpanNames(t *testing.T) {
+	zsp := NewSpanProcessor(WithMaxSpanNames(2))
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(zsp)).Tracer("test")
+
+	start := time.Now()
+	endSpan(tracer, "a", start, 0, codes.Ok)
+	b := endSpan(tracer, "b", start, 0, codes.Error)
+	endSpan(tracer, "a", start.Add(samplePeriod), 0, codes.Ok)
+	require.Len(t, zsp.traceSpans(b.SpanContext().TraceID()), 1)
+
+	// "b" is the least recently used span name.
+	endSpan(tracer, "c", start, 0, codes.Ok)
+	spansPM := zsp.spansPerMethod()
+	assert.Len(t, spansPM, 2)
+	assert.Contains(t, spansPM, "a")
+	assert.Contains(t, spansPM, "c")
+	assert.Nil(t, zsp.errorSpans("b"))
+	assert.Len(t, zsp.traceSpans(b.SpanContext().TraceID()), 0, "evicted samples indexed")
+
+	limits := zsp.limits()
+	assert.Equal(t, 2, limits.SpanNames)
+	assert.Equal(t, 2, limits.MaxSpanNames)
+	assert.Equal(t, uint64(1), limits.EvictedSpanNames)
+
+	rec := httptest.NewRecorder()
+	NewTracezHandler(zsp).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tracez", nil))
+	assert.Contains(t, rec.Body.String(), "Span names: 2 (max 2, 1 evicted)")
+}
+
+func TestSpanProcessorUnlimitedSpanNames(t *testing.T) {
+	zsp := NewSpanProcessor(WithMaxSpanNames(0))
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(zsp)).Tracer("test")
+	for i := 0; i < defaultMaxSpanNames+1; i++ {
+		endSpan(tracer, strconv.Itoa(i), time.Now(), 0, codes.Ok)
+	}
+	assert.Len(t, zsp.spansPerMethod(), defaultMaxSpanNames+1)
+	assert.Equal(t, uint64(0), zsp.limits().EvictedSpanNames)
+}
+
+func TestSpanProcessorMaxActiveSpans(t *testing.T) {
+	zsp := NewSpanProcessor(WithMaxActiveSpans(1))
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(zsp)).Tracer("test")
+
+	_, tracked := tracer.Start(context.Background(), "span")
+	_, untracked := tracer.Start(context.Background(), "span")
+	assert.Len(t, zsp.activeSpans("span"), 1)
+	assert.Len(t, zsp.traceSpans(untracked.SpanContext().TraceID()), 0)
+	limits := zsp.limits()
+	assert.Equal(t, int64(1), limits.ActiveSpans)
+	assert.Equal(t, uint64(1), limits.UntrackedActiveSpans)
+
+	untracked.End()
+	tracked.End()
+	assert.Len(t, zsp.activeSpans("span"), 0)
+	assert.Equal(t, int64(0), zsp.limits().ActiveSpans)
+
+	_, s := tracer.Start(context.Background(), "span")
+	defer s.End()
+	assert.Len(t, zsp.activeSpans("span"), 1)
+}
@@ -0,0 +1,288 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zpages // import "go.opentelemetry.io/contrib/zpages"
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// instrumentationName is the name of the meter of the zpages metrics.
+	instrumentationName = "go.opentelemetry.io/contrib/zpages"
+	// maxStackDepth is the maximum number of frames of a start stack.
+	maxStackDepth = 32
+)
+
+// stuckSpan is an active span open longer than its threshold.
+type stuckSpan struct {
+	span      sdktrace.ReadOnlySpan
+	open      time.Duration
+	threshold time.Duration
+	stack     []uintptr
+}
+
+// watchdog periodically scans the active spans of a SpanProcessor for stuck
+// spans.
+type watchdog struct {
+	ssm          *SpanProcessor
+	stop         chan struct{}
+	done         chan struct{}
+	shutdownOnce sync.Once
+	registration metric.Registration
+
+	sync.Mutex // protects everything below.
+	lastScan   time.Time
+	stuck      []stuckSpan
+}
+
+// newWatchdog returns a started watchdog scanning the active spans of ssm,
+// or nil if the detection of stuck spans is not enabled.
+func newWatchdog(ssm *SpanProcessor) *watchdog {
+	if ssm.cfg.stuckSpanThreshold <= 0 && len(ssm.cfg.stuckSpanNameThresholds) == 0 {
+		return nil
+	}
+	w := &watchdog{
+		ssm:  ssm,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	meter := ssm.cfg.meterProvider.Meter(instrumentationName, metric.WithInstrumentationVersion(Version()))
+	gauge, err := meter.Int64ObservableGauge(
+		"zpages.spans.stuck",
+		metric.WithDescription("Number of active spans open longer than their stuck span threshold."),
+		metric.WithUnit("{span}"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	} else {
+		w.registration, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+			w.Lock()
+			defer w.Unlock()
+			o.ObserveInt64(gauge, int64(len(w.stuck)))
+			return nil
+		}, gauge)
+		if err != nil {
+			otel.Handle(err)
+		}
+	}
+
+	go w.run(ssm.cfg.stuckSpanScanInterval)
+	return w
+}
+
+func (w *watchdog) run(interval time.Duration) {
+	defer close(w.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case now := <-ticker.C:
+			w.scan(now)
+		}
+	}
+}
+
+// scan replaces the stuck spans with the active spans open longer than their
+// threshold at now.
+func (w *watchdog) scan(now time.Time) {
+	var stuck []stuckSpan
+	w.ssm.activeSpansStore.Range(func(key, sp interface{}) bool {
+		span := sp.(sdktrace.ReadOnlySpan)
+		threshold := w.threshold(span.Name())
+		if threshold <= 0 {
+			return true
+		}
+		if open := now.Sub(span.StartTime()); open > threshold {
+			s := stuckSpan{span: span, open: open, threshold: threshold}
+			if stack, ok := w.ssm.startStacks.Load(key); ok {
+				s.stack = stack.([]uintptr)
+			}
+			stuck = append(stuck, s)
+		}
+		return true
+	})
+	sort.Slice(stuck, func(i, j int) bool {
+		return stuck[i].open > stuck[j].open
+	})
+
+	w.Lock()
+	defer w.Unlock()
+	w.lastScan = now
+	w.stuck = stuck
+}
+
+// threshold returns the stuck span threshold of the spans named name.
+func (w *watchdog) threshold(name string) time.Duration {
+	if t, ok := w.ssm.cfg.stuckSpanNameThresholds[name]; ok {
+		return t
+	}
+	return w.ssm.cfg.stuckSpanThreshold
+}
+
+// stuckSpans returns the stuck spans found by the last scan, and its time.
+func (w *watchdog) stuckSpans() ([]stuckSpan, time.Time) {
+	w.Lock()
+	defer w.Unlock()
+	return append([]stuckSpan(nil), w.stuck...), w.lastScan
+}
+
+// shutdown stops the scans and unregisters the metric callback.
+func (w *watchdog) shutdown() error {
+	var err error
+	w.shutdownOnce.Do(func() {
+		close(w.stop)
+		<-w.done
+		if w.registration != nil {
+			err = w.registration.Unregister()
+		}
+	})
+	return err
+}
+
+// startStack returns the program counters of the calling goroutine, without
+// the frames of the SDK and of the SpanProcessor.
+func startStack() []uintptr {
+	pc := make([]uintptr, maxStackDepth)
+	// Skip runtime.Callers, startStack and SpanProcessor.OnStart.
+	n := runtime.Callers(3, pc)
+	return pc[:n]
+}
+
+// formatStack returns the frames of stack, one function and one location per
+// line, skipping the frames of the trace SDK.
+func formatStack(stack []uintptr) string {
+	var b strings.Builder
+	frames := runtime.CallersFrames(stack)
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, "go.opentelemetry.io/otel/sdk/trace.") {
+			fmt.Fprintf(&b, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+		}
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+// stuckSpansData contains data for the stuck spans template.
+type stuckSpansData struct {
+	Enabled        bool
+	LastScan       string
+	ScanInterval   time.Duration
+	Threshold      time.Duration
+	NameThresholds []nameThreshold
+	TracesEndpoint string
+	Rows           []stuckSpanRow
+}
+
+type nameThreshold struct {
+	Name      string
+	Threshold time.Duration
+}
+
+type stuckSpanRow struct {
+	Name      string
+	TraceID   string
+	SpanID    string
+	Start     string
+	Open      time.Duration
+	Threshold time.Duration
+	Stack     string
+}
+
+var _ http.Handler = (*stuckSpansHandler)(nil)
+
+type stuckSpansHandler struct {
+	sp *SpanProcessor
+}
+
+// NewStuckSpansHandler returns an http.Handler that can be used to serve HTTP
+// requests listing the stuck spans of sp, the active spans open longer than
+// their threshold. The detection of stuck spans is enabled with the
+// WithStuckSpanThreshold and WithStuckSpanNameThreshold options.
+func NewStuckSpansHandler(sp *SpanProcessor) http.Handler {
+	return &stuckSpansHandler{sp: sp}
+}
+
+// ServeHTTP implements the http.Handler and is capable of serving stuck spans
+// HTTP requests.
+func (sh *stuckSpansHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := headerTemplate.Execute(w, headerData{Title: "Stuck Spans"}); err != nil {
+		log.Printf("zpages: executing template: %v", err)
+	}
+	if err := stuckSpansTemplate.Execute(w, sh.getStuckSpansData()); err != nil {
+		log.Printf("zpages: executing template: %v", err)
+	}
+	if err := footerTemplate.Execute(w, nil); err != nil {
+		log.Printf("zpages: executing template: %v", err)
+	}
+}
+
+func (sh *stuckSpansHandler) getStuckSpansData() stuckSpansData {
+	wd := sh.sp.watchdog
+	if wd == nil {
+		return stuckSpansData{}
+	}
+	cfg := sh.sp.cfg
+	data := stuckSpansData{
+		Enabled:        true,
+		ScanInterval:   cfg.stuckSpanScanInterval,
+		Threshold:      cfg.stuckSpanThreshold,
+		TracesEndpoint: "tracez",
+	}
+	for name, t := range cfg.stuckSpanNameThresholds {
+		data.NameThresholds = append(data.NameThresholds, nameThreshold{Name: name, Threshold: t})
+	}
+	sort.Slice(data.NameThresholds, func(i, j int) bool {
+		return data.NameThresholds[i].Name < data.NameThresholds[j].Name
+	})
+
+	stuck, lastScan := wd.stuckSpans()
+	if !lastScan.IsZero() {
+		data.LastScan = lastScan.Format(time.RFC3339)
+	}
+	for _, s := range stuck {
+		row := stuckSpanRow{
+			Name:      s.span.Name(),
+			TraceID:   s.span.SpanContext().TraceID().String(),
+			SpanID:    s.span.SpanContext().SpanID().String(),
+			Start:     s.span.StartTime().Format(time.RFC3339Nano),
+			Open:      s.open.Round(time.Millisecond),
+			Threshold: s.threshold,
+		}
+		if s.stack != nil {
+			row.Stack = formatStack(s.stack)
+		}
+		data.Rows = append(data.Rows, row)
+	}
+	return data
+}
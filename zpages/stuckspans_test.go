@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zpages
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func stuckSpansGauge(t *testing.T, r sdkmetric.Reader) (int64, bool) {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, r.Collect(context.Background(), &rm))
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == "zpages.spans.stuck" {
+				dps := m.Data.(metricdata.Gauge[int64]).DataPoints
+				require.Len(t, dps, 1)
+				return dps[0].Value, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func TestStuckSpans(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	zsp := NewSpanProcessor(
+		WithStuckSpanThreshold(time.Hour),
+		WithStuckSpanNameThreshold("never", 0),
+		WithStuckSpanNameThreshold("fast", time.Minute),
+		WithStuckSpanScanInterval(time.Hour),
+		WithStartStack(),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	t.Cleanup(func() { assert.NoError(t, zsp.Shutdown(context.Background())) })
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(zsp)).Tracer("test")
+
+	now := time.Now()
+	start := func(name string, open time.Duration) trace.Span {
+		_, s := tracer.Start(context.Background(), name, trace.WithTimestamp(now.Add(-open)))
+		return s
+	}
+	stuck := start("stuck", 2*time.Hour)
+	defer start("never", 2*time.Hour).End()
+	fast := start("fast", 2*time.Minute)
+	defer fast.End()
+	defer start("recent", time.Minute).End()
+
+	_, ok := stuckSpansGauge(t, reader)
+	assert.True(t, ok)
+	zsp.watchdog.scan(now)
+	spans, lastScan := zsp.watchdog.stuckSpans()
+	assert.Equal(t, now, lastScan)
+	require.Len(t, spans, 2)
+	assert.Equal(t, stuck.SpanContext(), spans[0].span.SpanContext())
+	assert.Equal(t, 2*time.Hour, spans[0].open)
+	assert.Equal(t, time.Hour, spans[0].threshold)
+	assert.Equal(t, fast.SpanContext(), spans[1].span.SpanContext())
+	assert.Equal(t, time.Minute, spans[1].threshold)
+	n, _ := stuckSpansGauge(t, reader)
+	assert.Equal(t, int64(2), n)
+
+	data := (&stuckSpansHandler{sp: zsp}).getStuckSpansData()
+	assert.True(t, data.Enabled)
+	assert.Equal(t, []nameThreshold{{"fast", time.Minute}, {"never", 0}}, data.NameThresholds)
+	require.Len(t, data.Rows, 2)
+	assert.Equal(t, "stuck", data.Rows[0].Name)
+	assert.Contains(t, data.Rows[0].Stack, "zpages.TestStuckSpans")
+	assert.NotContains(t, data.Rows[0].Stack, "go.opentelemetry.io/otel/sdk/trace.")
+	assert.NotContains(t, data.Rows[0].Stack, "OnStart")
+
+	rec := httptest.NewRecorder()
+	NewStuckSpansHandler(zsp).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stuckspans", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, "Active spans open longer than 1h0m0s, or 1m0s for <b>fast</b>, or never for <b>never</b>.")
+	assert.Contains(t, body, "2 Stuck Spans")
+	assert.Contains(t, body, `<a href="tracez?trace_id=`+stuck.SpanContext().TraceID().String()+`">`)
+
+	stuck.End()
+	zsp.watchdog.scan(now)
+	spans, _ = zsp.watchdog.stuckSpans()
+	require.Len(t, spans, 1)
+	_, ok = zsp.startStacks.Load(spanKey(stuck.SpanContext()))
+	assert.False(t, ok, "start stack of ended span")
+
+	require.NoError(t, zsp.Shutdown(context.Background()))
+	_, ok = stuckSpansGauge(t, reader)
+	assert.False(t, ok, "metric reported after shutdown")
+}
+
+func TestStuckSpansScanInterval(t *testing.T) {
+	zsp := NewSpanProcessor(WithStuckSpanThreshold(time.Millisecond), WithStuckSpanScanInterval(10*time.Millisecond))
+	t.Cleanup(func() { assert.NoError(t, zsp.Shutdown(context.Background())) })
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(zsp)).Tracer("test")
+	_, span := tracer.Start(context.Background(), "span")
+	defer span.End()
+
+	assert.Eventually(t, func() bool {
+		spans, _ := zsp.watchdog.stuckSpans()
+		return len(spans) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	data := (&stuckSpansHandler{sp: zsp}).getStuckSpansData()
+	require.Len(t, data.Rows, 1)
+	assert.Empty(t, data.Rows[0].Stack)
+}
+
+func TestStuckSpansDisabled(t *testing.T) {
+	zsp := NewSpanProcessor()
+	assert.Nil(t, zsp.watchdog)
+	assert.NoError(t, zsp.Shutdown(context.Background()))
+
+	rec := httptest.NewRecorder()
+	NewStuckSpansHandler(zsp).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stuckspans", nil))
+	assert.Contains(t, rec.Body.String(), "The detection of stuck spans is not enabled.")
+}
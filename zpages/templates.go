@@ -33,6 +33,9 @@ var (
 	headerTemplate       = parseTemplate("header")
 	summaryTableTemplate = parseTemplate("summary")
 	tracesTableTemplate  = parseTemplate("traces")
+	traceTemplate        = parseTemplate("trace")
+	metriczTemplate      = parseTemplate("metricz")
+	stuckSpansTemplate   = parseTemplate("stuckspans")
 	footerTemplate       = parseTemplate("footer")
 )
 
@@ -67,10 +70,11 @@ func spanRowFormatter(r spanRow) template.HTML {
 	if r.SpanContext.IsSampled() {
 		col = "blue"
 	}
+	traceID := fmt.Sprintf(`<a href="?%s=%s"><b style="color:%s">%s</b></a>`, traceIDQueryField, r.SpanContext.TraceID(), col, r.SpanContext.TraceID())
 	if r.ParentSpanContext.IsValid() {
-		return template.HTML(fmt.Sprintf(`trace_id: <b style="color:%s">%s</b> span_id: %s parent_span_id: %s`, col, r.SpanContext.TraceID(), r.SpanContext.SpanID(), r.ParentSpanContext.SpanID()))
+		return template.HTML(fmt.Sprintf(`trace_id: %s span_id: %s parent_span_id: %s`, traceID, r.SpanContext.SpanID(), r.ParentSpanContext.SpanID()))
 	}
-	return template.HTML(fmt.Sprintf(`trace_id: <b style="color:%s">%s</b> span_id: %s`, col, r.SpanContext.TraceID(), r.SpanContext.SpanID()))
+	return template.HTML(fmt.Sprintf(`trace_id: %s span_id: %s`, traceID, r.SpanContext.SpanID()))
 }
 
 func even(x int) bool {
@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zpages // import "go.opentelemetry.io/contrib/zpages"
+
+import (
+	"sync"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traceIndex indexes the active and sampled spans by trace ID.
+//
+// The zero value is ready to use.
+type traceIndex struct {
+	sync.Mutex // protects everything below.
+	spans      map[trace.TraceID]map[trace.SpanID]sdktrace.ReadOnlySpan
+}
+
+// add adds span to the index, replacing any span with the same span ID.
+func (ti *traceIndex) add(span sdktrace.ReadOnlySpan) {
+	sc := span.SpanContext()
+	if !sc.IsValid() {
+		return
+	}
+	ti.Lock()
+	defer ti.Unlock()
+	if ti.spans == nil {
+		ti.spans = make(map[trace.TraceID]map[trace.SpanID]sdktrace.ReadOnlySpan)
+	}
+	spans, ok := ti.spans[sc.TraceID()]
+	if !ok {
+		spans = make(map[trace.SpanID]sdktrace.ReadOnlySpan)
+		ti.spans[sc.TraceID()] = spans
+	}
+	spans[sc.SpanID()] = span
+}
+
+// remove removes the span with the span ID of span from the index. The
+// removed span is not required to be span: the span passed to OnEnd can be a
+// snapshot of the one passed to OnStart.
+func (ti *traceIndex) remove(span sdktrace.ReadOnlySpan) {
+	sc := span.SpanContext()
+	ti.Lock()
+	defer ti.Unlock()
+	spans, ok := ti.spans[sc.TraceID()]
+	if !ok {
+		return
+	}
+	delete(spans, sc.SpanID())
+	if len(spans) == 0 {
+		delete(ti.spans, sc.TraceID())
+	}
+}
+
+// trace returns the indexed spans of the trace id.
+func (ti *traceIndex) trace(id trace.TraceID) []sdktrace.ReadOnlySpan {
+	ti.Lock()
+	defer ti.Unlock()
+	spans := ti.spans[id]
+	out := make([]sdktrace.ReadOnlySpan, 0, len(spans))
+	for _, s := range spans {
+		out = append(out, s)
+	}
+	return out
+}
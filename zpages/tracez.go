@@ -16,8 +16,10 @@
 package zpages // import "go.opentelemetry.io/contrib/zpages"
 
 import (
+	"bytes"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
 	"sort"
 	"strconv"
@@ -25,6 +27,7 @@ import (
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -37,6 +40,15 @@ const (
 	// spanLatencyBucketQueryField is the header for latency based samples.
 	// Default is [0, 8] representing the latency buckets, where 0 is the first one.
 	spanLatencyBucketQueryField = "zlatencybucket"
+	// spanLimitQueryField is the header for the maximum number of spans to return in JSON output.
+	spanLimitQueryField = "zlimit"
+	// formatQueryField is the header for the output format, "json" for JSON output.
+	// Default is HTML.
+	formatQueryField = "zformat"
+	// traceIDQueryField is the header for the trace ID of the trace to display.
+	traceIDQueryField = "trace_id"
+	// traceIndentWidth is the indentation in pixels of a child span in the trace view.
+	traceIndentWidth = 16
 	// maxTraceMessageLength is the maximum length of a message in tracez output.
 	maxTraceMessageLength = 1024
 )
@@ -47,6 +59,20 @@ type summaryTableData struct {
 	Links              bool
 	TracesEndpoint     string
 	Rows               []summaryTableRowData
+	Limits             limitsData
+}
+
+// limitsData contains the limits of the SpanProcessor and how often they
+// were reached.
+type limitsData struct {
+	SpanNames             int    `json:"spanNames"`
+	MaxSpanNames          int    `json:"maxSpanNames,omitempty"`
+	EvictedSpanNames      uint64 `json:"evictedSpanNames"`
+	ActiveSpans           int64  `json:"activeSpans"`
+	MaxActiveSpans        int    `json:"maxActiveSpans,omitempty"`
+	UntrackedActiveSpans  uint64 `json:"untrackedActiveSpans"`
+	LatencySampleCapacity uint   `json:"latencySampleCapacity"`
+	ErrorSampleCapacity   uint   `json:"errorSampleCapacity"`
 }
 
 type summaryTableRowData struct {
@@ -56,6 +82,39 @@ type summaryTableRowData struct {
 	Errors  int
 }
 
+// traceData contains data for the trace template, the spans of a trace
+// ordered as a waterfall.
+type traceData struct {
+	TraceID  string
+	Duration time.Duration
+	Rows     []traceRow
+}
+
+type traceRow struct {
+	Name   string
+	SpanID string
+	// MissingParentSpanID is the ID of the parent span if it is not retained.
+	MissingParentSpanID string
+	Kind                string
+	Status              string
+	Error               bool
+	Running             bool
+	Indent              int
+	Offset              time.Duration
+	Duration            time.Duration
+	// Left and Width are the position of the span in the waterfall, in
+	// percents of the trace duration.
+	Left   float64
+	Width  float64
+	Events []traceEvent
+}
+
+type traceEvent struct {
+	Name   string
+	Offset time.Duration
+	Left   float64
+}
+
 // traceTableData contains data for the trace data template.
 type traceTableData struct {
 	Name string
@@ -75,12 +134,31 @@ func NewTracezHandler(sp *SpanProcessor) http.Handler {
 }
 
 // ServeHTTP implements the http.Handler and is capable of serving "tracez" HTTP requests.
+//
+// The data is returned as JSON instead of HTML when the zformat query
+// parameter is "json", see serveJSON.
 func (th *tracezHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := r.ParseForm(); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	var traceID trace.TraceID
+	if v := r.Form.Get(traceIDQueryField); v != "" {
+		var err error
+		if traceID, err = trace.TraceIDFromHex(v); err != nil {
+			http.Error(w, fmt.Sprintf("invalid %s value %q", traceIDQueryField, v), http.StatusBadRequest)
+			return
+		}
+	}
+	if r.Form.Get(formatQueryField) == "json" {
+		th.serveJSON(w, r, traceID)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if traceID.IsValid() {
+		th.serveTrace(w, traceID)
+		return
+	}
 	spanName := r.Form.Get(spanNameQueryField)
 	spanType, _ := strconv.Atoi(r.Form.Get(spanTypeQueryField))
 	spanSubtype, _ := strconv.Atoi(r.Form.Get(spanLatencyBucketQueryField))
@@ -101,17 +179,137 @@ func (th *tracezHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (th *tracezHandler) getTraceTableData(spanName string, spanType, latencyBucket int) traceTableData {
-	var spans []sdktrace.ReadOnlySpan
+// spans returns the spans of the given name and type (running = 0, latency = 1, error = 2).
+func (th *tracezHandler) spans(spanName string, spanType, latencyBucket int) []sdktrace.ReadOnlySpan {
 	switch spanType {
 	case 0: // active
-		spans = th.sp.activeSpans(spanName)
+		return th.sp.activeSpans(spanName)
 	case 1: // latency
-		spans = th.sp.spansByLatency(spanName, latencyBucket)
+		return th.sp.spansByLatency(spanName, latencyBucket)
 	case 2: // error
-		spans = th.sp.errorSpans(spanName)
+		return th.sp.errorSpans(spanName)
+	}
+	return nil
+}
+
+// serveTrace renders the spans of the trace id as a waterfall.
+func (th *tracezHandler) serveTrace(w http.ResponseWriter, id trace.TraceID) {
+	if err := headerTemplate.Execute(w, headerData{Title: "Trace"}); err != nil {
+		log.Printf("zpages: executing template: %v", err)
+	}
+	if err := traceTemplate.Execute(w, th.getTraceData(id, time.Now())); err != nil {
+		log.Printf("zpages: executing template: %v", err)
+	}
+	if err := footerTemplate.Execute(w, nil); err != nil {
+		log.Printf("zpages: executing template: %v", err)
+	}
+}
+
+// getTraceData returns the spans of the trace id ordered as a waterfall:
+// every span is followed by its children, ordered by start time. Spans whose
+// parent is not retained are shown as roots. Active spans are shown as ending
+// at now.
+func (th *tracezHandler) getTraceData(id trace.TraceID, now time.Time) traceData {
+	data := traceData{TraceID: id.String()}
+	spans := sortedTraceSpans(th.sp.traceSpans(id))
+	if len(spans) == 0 {
+		return data
+	}
+
+	end := func(s sdktrace.ReadOnlySpan) time.Time {
+		if s.EndTime().IsZero() {
+			return now
+		}
+		return s.EndTime()
+	}
+	start, last := spans[0].StartTime(), end(spans[0])
+	retained := make(map[trace.SpanID]bool, len(spans))
+	children := make(map[trace.SpanID][]sdktrace.ReadOnlySpan)
+	for _, s := range spans {
+		if e := end(s); e.After(last) {
+			last = e
+		}
+		retained[s.SpanContext().SpanID()] = true
+		if p := s.Parent(); p.IsValid() {
+			children[p.SpanID()] = append(children[p.SpanID()], s)
+		}
+	}
+	data.Duration = last.Sub(start)
+	total := float64(data.Duration)
+	if total <= 0 {
+		total = 1
+	}
+	percent := func(d time.Duration) float64 {
+		return math.Round(float64(d)/total*10000) / 100
 	}
 
+	visited := make(map[trace.SpanID]bool, len(spans))
+	var add func(s sdktrace.ReadOnlySpan, depth int)
+	add = func(s sdktrace.ReadOnlySpan, depth int) {
+		id := s.SpanContext().SpanID()
+		if visited[id] {
+			return
+		}
+		visited[id] = true
+
+		row := traceRow{
+			Name:     s.Name(),
+			SpanID:   id.String(),
+			Kind:     s.SpanKind().String(),
+			Status:   s.Status().Code.String(),
+			Error:    s.Status().Code == codes.Error,
+			Running:  s.EndTime().IsZero(),
+			Indent:   depth * traceIndentWidth,
+			Offset:   s.StartTime().Sub(start),
+			Duration: end(s).Sub(s.StartTime()),
+		}
+		if s.Status().Description != "" {
+			row.Status += ": " + s.Status().Description
+		}
+		if p := s.Parent(); p.IsValid() && !retained[p.SpanID()] {
+			row.MissingParentSpanID = p.SpanID().String()
+		}
+		row.Left = percent(row.Offset)
+		row.Width = percent(row.Duration)
+		es := events(s.Events())
+		sort.Sort(es)
+		for _, e := range es {
+			offset := e.Time.Sub(start)
+			row.Events = append(row.Events, traceEvent{Name: e.Name, Offset: e.Time.Sub(s.StartTime()), Left: percent(offset)})
+		}
+		data.Rows = append(data.Rows, row)
+
+		for _, c := range children[id] {
+			add(c, depth+1)
+		}
+	}
+	for _, s := range spans {
+		if p := s.Parent(); !p.IsValid() || !retained[p.SpanID()] {
+			add(s, 0)
+		}
+	}
+	// Spans of a parent cycle have no root.
+	for _, s := range spans {
+		add(s, 0)
+	}
+	return data
+}
+
+// sortedTraceSpans sorts spans by start time, and then by span ID.
+func sortedTraceSpans(spans []sdktrace.ReadOnlySpan) []sdktrace.ReadOnlySpan {
+	sort.Slice(spans, func(i, j int) bool {
+		si, sj := spans[i].StartTime(), spans[j].StartTime()
+		if !si.Equal(sj) {
+			return si.Before(sj)
+		}
+		a, b := spans[i].SpanContext().SpanID(), spans[j].SpanContext().SpanID()
+		return bytes.Compare(a[:], b[:]) < 0
+	})
+	return spans
+}
+
+func (th *tracezHandler) getTraceTableData(spanName string, spanType, latencyBucket int) traceTableData {
+	spans := th.spans(spanName, spanType, latencyBucket)
 	data := traceTableData{
 		Name: spanName,
 		Num:  len(spans),
@@ -126,10 +324,11 @@ func (th *tracezHandler) getSummaryTableData() summaryTableData {
 	data := summaryTableData{
 		Links:          true,
 		TracesEndpoint: "tracez",
+		Limits:         th.sp.limits(),
 	}
 	data.Header = []string{"Name", "active"}
 	// An implicit 0 lower bound latency bucket is always present.
-	latencyBuckets := append([]time.Duration{0}, defaultBoundaries.durations...)
+	latencyBuckets := append([]time.Duration{0}, th.sp.cfg.boundaries.durations...)
 	for _, l := range latencyBuckets {
 		s := fmt.Sprintf(">%v", l)
 		data.Header = append(data.Header, s)
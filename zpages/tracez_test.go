@@ -0,0 +1,135 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zpages
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracezHTML(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewTracezHandler(NewSpanProcessor()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tracez", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+}
+
+func TestTracezTrace(t *testing.T) {
+	zsp := NewSpanProcessor()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(zsp)).Tracer("test")
+
+	start := time.Unix(1000, 0)
+	ctx, root := tracer.Start(context.Background(), "root", trace.WithTimestamp(start), trace.WithSpanKind(trace.SpanKindServer))
+	_, second := tracer.Start(ctx, "second", trace.WithTimestamp(start.Add(6*time.Second)))
+	second.End(trace.WithTimestamp(start.Add(8 * time.Second)))
+	childCtx, first := tracer.Start(ctx, "first", trace.WithTimestamp(start.Add(2*time.Second)))
+	_, grandchild := tracer.Start(childCtx, "grandchild", trace.WithTimestamp(start.Add(3*time.Second)))
+	grandchild.AddEvent("event", trace.WithTimestamp(start.Add(4*time.Second)))
+	grandchild.SetStatus(codes.Error, "failed")
+	grandchild.End(trace.WithTimestamp(start.Add(5 * time.Second)))
+	first.End(trace.WithTimestamp(start.Add(5 * time.Second)))
+	_, other := tracer.Start(context.Background(), "other")
+	defer other.End()
+
+	th := &tracezHandler{sp: zsp}
+	data := th.getTraceData(root.SpanContext().TraceID(), start.Add(10*time.Second))
+	assert.Equal(t, root.SpanContext().TraceID().String(), data.TraceID)
+	assert.Equal(t, 10*time.Second, data.Duration)
+	require.Len(t, data.Rows, 4)
+
+	assert.Equal(t, traceRow{
+		Name:     "root",
+		SpanID:   root.SpanContext().SpanID().String(),
+		Kind:     "server",
+		Status:   "Unset",
+		Running:  true,
+		Duration: 10 * time.Second,
+		Width:    100,
+	}, data.Rows[0])
+	assert.Equal(t, "first", data.Rows[1].Name)
+	assert.Equal(t, traceIndentWidth, data.Rows[1].Indent)
+	assert.Equal(t, traceRow{
+		Name:     "grandchild",
+		SpanID:   grandchild.SpanContext().SpanID().String(),
+		Kind:     "internal",
+		Status:   "Error: failed",
+		Error:    true,
+		Indent:   2 * traceIndentWidth,
+		Offset:   3 * time.Second,
+		Duration: 2 * time.Second,
+		Left:     30,
+		Width:    20,
+		Events:   []traceEvent{{Name: "event", Offset: time.Second, Left: 40}},
+	}, data.Rows[2])
+	assert.Equal(t, "second", data.Rows[3].Name)
+	assert.Equal(t, traceIndentWidth, data.Rows[3].Indent)
+	assert.Equal(t, 60.0, data.Rows[3].Left)
+
+	root.End(trace.WithTimestamp(start.Add(10 * time.Second)))
+	rec := httptest.NewRecorder()
+	th.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tracez?trace_id="+data.TraceID, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, "4 Spans")
+	assert.Contains(t, body, "grandchild")
+	assert.Contains(t, body, "left: 30%; width: 20%")
+	assert.NotContains(t, body, "other")
+
+	_, got := serveTracez(t, th, "zformat=json&trace_id="+data.TraceID)
+	assert.Equal(t, data.TraceID, got["traceId"])
+	spans := got["spans"].([]interface{})
+	require.Len(t, spans, 4)
+	assert.Equal(t, "first", spans[1].(map[string]interface{})["name"])
+	assert.Equal(t, "grandchild", spans[2].(map[string]interface{})["name"])
+}
+
+func TestTracezTraceMissingParent(t *testing.T) {
+	zsp := NewSpanProcessor()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(zsp)).Tracer("test")
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	_, span := tracer.Start(trace.ContextWithRemoteSpanContext(context.Background(), parent), "span")
+	span.End()
+
+	data := (&tracezHandler{sp: zsp}).getTraceData(parent.TraceID(), time.Now())
+	require.Len(t, data.Rows, 1)
+	assert.Equal(t, 0, data.Rows[0].Indent)
+	assert.Equal(t, parent.SpanID().String(), data.Rows[0].MissingParentSpanID)
+}
+
+func TestTracezTraceUnknown(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewTracezHandler(NewSpanProcessor()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tracez?trace_id=0102030405060708090a0b0c0d0e0f10", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "No span of this trace")
+
+	rec = httptest.NewRecorder()
+	NewTracezHandler(NewSpanProcessor()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tracez?trace_id=invalid", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
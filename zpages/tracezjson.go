@@ -0,0 +1,307 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zpages // import "go.opentelemetry.io/contrib/zpages"
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanTypeNames are the names of the span types in JSON output, indexed by
+// the ztype query parameter value.
+var spanTypeNames = []string{"active", "latency", "error"}
+
+// summaryJSON is the JSON output of the tracez summary table.
+type summaryJSON struct {
+	LatencyBuckets []latencyBucketJSON `json:"latencyBuckets"`
+	SpanNames      []spanNameJSON      `json:"spanNames"`
+	Limits         limitsData          `json:"limits"`
+}
+
+type latencyBucketJSON struct {
+	Name            string `json:"name"`
+	LowerBoundNanos int64  `json:"lowerBoundNanos"`
+	// UpperBoundNanos is not set for the last bucket, which is unbounded.
+	UpperBoundNanos *int64 `json:"upperBoundNanos,omitempty"`
+}
+
+type spanNameJSON struct {
+	Name    string `json:"name"`
+	Active  int    `json:"active"`
+	Latency []int  `json:"latency"`
+	Errors  int    `json:"errors"`
+}
+
+// spansJSON is the JSON output of the spans of a span name.
+type spansJSON struct {
+	Name          string     `json:"name"`
+	Type          string     `json:"type"`
+	LatencyBucket *int       `json:"latencyBucket,omitempty"`
+	Total         int        `json:"total"`
+	Spans         []spanJSON `json:"spans"`
+}
+
+// traceJSON is the JSON output of the spans of a trace.
+type traceJSON struct {
+	TraceID string     `json:"traceId"`
+	Spans   []spanJSON `json:"spans"`
+}
+
+type spanJSON struct {
+	TraceID                string                 `json:"traceId"`
+	SpanID                 string                 `json:"spanId"`
+	TraceState             string                 `json:"traceState,omitempty"`
+	ParentSpanID           string                 `json:"parentSpanId,omitempty"`
+	Sampled                bool                   `json:"sampled"`
+	Name                   string                 `json:"name"`
+	Kind                   string                 `json:"kind"`
+	StartTime              time.Time              `json:"startTime"`
+	EndTime                *time.Time             `json:"endTime,omitempty"`
+	Attributes             map[string]interface{} `json:"attributes,omitempty"`
+	DroppedAttributesCount int                    `json:"droppedAttributesCount,omitempty"`
+	Events                 []eventJSON            `json:"events,omitempty"`
+	DroppedEventsCount     int                    `json:"droppedEventsCount,omitempty"`
+	Links                  []linkJSON             `json:"links,omitempty"`
+	DroppedLinksCount      int                    `json:"droppedLinksCount,omitempty"`
+	Status                 statusJSON             `json:"status"`
+	Resource               resourceJSON           `json:"resource"`
+	InstrumentationScope   scopeJSON              `json:"instrumentationScope"`
+}
+
+type eventJSON struct {
+	Name                   string                 `json:"name"`
+	Time                   time.Time              `json:"time"`
+	Attributes             map[string]interface{} `json:"attributes,omitempty"`
+	DroppedAttributesCount int                    `json:"droppedAttributesCount,omitempty"`
+}
+
+type linkJSON struct {
+	TraceID                string                 `json:"traceId"`
+	SpanID                 string                 `json:"spanId"`
+	TraceState             string                 `json:"traceState,omitempty"`
+	Attributes             map[string]interface{} `json:"attributes,omitempty"`
+	DroppedAttributesCount int                    `json:"droppedAttributesCount,omitempty"`
+}
+
+type statusJSON struct {
+	Code        string `json:"code"`
+	Description string `json:"description,omitempty"`
+}
+
+type resourceJSON struct {
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	SchemaURL  string                 `json:"schemaUrl,omitempty"`
+}
+
+type scopeJSON struct {
+	Name      string `json:"name"`
+	Version   string `json:"version,omitempty"`
+	SchemaURL string `json:"schemaUrl,omitempty"`
+}
+
+// serveJSON serves the tracez data as JSON.
+//
+// If traceID is valid, the active and sampled spans of that trace are
+// returned, ordered by start time. Without a zspanname query parameter the
+// summary of every span name is returned: the number of active spans, of
+// sampled spans per latency bucket and of sampled error spans. Otherwise the
+// spans of that name are returned, selected with the ztype and zlatencybucket
+// query parameters as for HTML output, most recently started first. The
+// zlimit query parameter caps the number of returned spans.
+func (th *tracezHandler) serveJSON(w http.ResponseWriter, r *http.Request, traceID trace.TraceID) {
+	if traceID.IsValid() {
+		data := traceJSON{TraceID: traceID.String(), Spans: []spanJSON{}}
+		for _, s := range sortedTraceSpans(th.sp.traceSpans(traceID)) {
+			data.Spans = append(data.Spans, newSpanJSON(s))
+		}
+		writeJSON(w, data)
+		return
+	}
+
+	spanName := r.Form.Get(spanNameQueryField)
+	if spanName == "" {
+		writeJSON(w, th.getSummaryJSON())
+		return
+	}
+
+	spanType, err := intQueryField(r, spanTypeQueryField, 0)
+	if err != nil || spanType < 0 || spanType >= len(spanTypeNames) {
+		http.Error(w, fmt.Sprintf("invalid %s value %q", spanTypeQueryField, r.Form.Get(spanTypeQueryField)), http.StatusBadRequest)
+		return
+	}
+	latencyBucket, err := intQueryField(r, spanLatencyBucketQueryField, 0)
+	if err != nil || latencyBucket < 0 || latencyBucket >= th.sp.cfg.boundaries.numBuckets() {
+		http.Error(w, fmt.Sprintf("invalid %s value %q", spanLatencyBucketQueryField, r.Form.Get(spanLatencyBucketQueryField)), http.StatusBadRequest)
+		return
+	}
+	limit, err := intQueryField(r, spanLimitQueryField, 0)
+	if err != nil || limit < 0 {
+		http.Error(w, fmt.Sprintf("invalid %s value %q", spanLimitQueryField, r.Form.Get(spanLimitQueryField)), http.StatusBadRequest)
+		return
+	}
+
+	spans := th.spans(spanName, spanType, latencyBucket)
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].StartTime().After(spans[j].StartTime())
+	})
+	data := spansJSON{
+		Name:  spanName,
+		Type:  spanTypeNames[spanType],
+		Total: len(spans),
+		Spans: []spanJSON{},
+	}
+	if spanType == 1 {
+		data.LatencyBucket = &latencyBucket
+	}
+	if limit > 0 && len(spans) > limit {
+		spans = spans[:limit]
+	}
+	for _, s := range spans {
+		data.Spans = append(data.Spans, newSpanJSON(s))
+	}
+	writeJSON(w, data)
+}
+
+// intQueryField returns the integer value of the query parameter name, or
+// defaultValue if it is not set.
+func intQueryField(r *http.Request, name string, defaultValue int) (int, error) {
+	v := r.Form.Get(name)
+	if v == "" {
+		return defaultValue, nil
+	}
+	return strconv.Atoi(v)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("zpages: encoding JSON: %v", err)
+	}
+}
+
+func (th *tracezHandler) getSummaryJSON() summaryJSON {
+	table := th.getSummaryTableData()
+	data := summaryJSON{SpanNames: []spanNameJSON{}, Limits: table.Limits}
+	// An implicit 0 lower bound latency bucket is always present.
+	lower := time.Duration(0)
+	for i, name := range table.LatencyBucketNames {
+		b := latencyBucketJSON{Name: name, LowerBoundNanos: int64(lower)}
+		if i < len(th.sp.cfg.boundaries.durations) {
+			upper := int64(th.sp.cfg.boundaries.durations[i])
+			b.UpperBoundNanos = &upper
+			lower = th.sp.cfg.boundaries.durations[i]
+		}
+		data.LatencyBuckets = append(data.LatencyBuckets, b)
+	}
+	for _, row := range table.Rows {
+		latency := row.Latency
+		if latency == nil {
+			// Only active spans have been seen for this name.
+			latency = make([]int, th.sp.cfg.boundaries.numBuckets())
+		}
+		data.SpanNames = append(data.SpanNames, spanNameJSON{
+			Name:    row.Name,
+			Active:  row.Active,
+			Latency: latency,
+			Errors:  row.Errors,
+		})
+	}
+	return data
+}
+
+func newSpanJSON(s sdktrace.ReadOnlySpan) spanJSON {
+	sc := s.SpanContext()
+	out := spanJSON{
+		TraceID:                sc.TraceID().String(),
+		SpanID:                 sc.SpanID().String(),
+		TraceState:             sc.TraceState().String(),
+		Sampled:                sc.IsSampled(),
+		Name:                   s.Name(),
+		Kind:                   s.SpanKind().String(),
+		StartTime:              s.StartTime(),
+		Attributes:             attributesJSON(s.Attributes()),
+		DroppedAttributesCount: s.DroppedAttributes(),
+		DroppedEventsCount:     s.DroppedEvents(),
+		DroppedLinksCount:      s.DroppedLinks(),
+		Status: statusJSON{
+			Code:        s.Status().Code.String(),
+			Description: s.Status().Description,
+		},
+		InstrumentationScope: scopeJSON{
+			Name:      s.InstrumentationScope().Name,
+			Version:   s.InstrumentationScope().Version,
+			SchemaURL: s.InstrumentationScope().SchemaURL,
+		},
+	}
+	if psc := s.Parent(); psc.IsValid() {
+		out.ParentSpanID = psc.SpanID().String()
+	}
+	if end := s.EndTime(); !end.IsZero() {
+		out.EndTime = &end
+	}
+	if res := s.Resource(); res != nil {
+		out.Resource = resourceJSON{
+			Attributes: attributesJSON(res.Attributes()),
+			SchemaURL:  res.SchemaURL(),
+		}
+	}
+
+	es := events(s.Events())
+	sort.Sort(es)
+	for _, e := range es {
+		out.Events = append(out.Events, eventJSON{
+			Name:                   e.Name,
+			Time:                   e.Time,
+			Attributes:             attributesJSON(e.Attributes),
+			DroppedAttributesCount: e.DroppedAttributeCount,
+		})
+	}
+	for _, l := range s.Links() {
+		out.Links = append(out.Links, newLinkJSON(l))
+	}
+	return out
+}
+
+func newLinkJSON(l sdktrace.Link) linkJSON {
+	return linkJSON{
+		TraceID:                l.SpanContext.TraceID().String(),
+		SpanID:                 l.SpanContext.SpanID().String(),
+		TraceState:             l.SpanContext.TraceState().String(),
+		Attributes:             attributesJSON(l.Attributes),
+		DroppedAttributesCount: l.DroppedAttributeCount,
+	}
+}
+
+// attributesJSON returns the attributes as a map of their keys to their
+// values, or nil if there are none.
+func attributesJSON(attrs []attribute.KeyValue) map[string]interface{} {
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := make(map[string]interface{}, len(attrs))
+	for _, a := range attrs {
+		out[string(a.Key)] = a.Value.AsInterface()
+	}
+	return out
+}
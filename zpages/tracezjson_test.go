@@ -0,0 +1,161 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zpages
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func serveTracez(t *testing.T, h http.Handler, query string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tracez?"+query, nil))
+	if rec.Code != http.StatusOK {
+		return rec, nil
+	}
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	return rec, got
+}
+
+func TestTracezJSON(t *testing.T) {
+	zsp := NewSpanProcessor()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(zsp),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "test"))),
+	)
+	tracer := tp.Tracer("scope", trace.WithInstrumentationVersion("v1"))
+
+	ctx, parent := tracer.Start(context.Background(), "parent", trace.WithSpanKind(trace.SpanKindServer))
+	defer parent.End()
+	link := trace.Link{SpanContext: parent.SpanContext(), Attributes: []attribute.KeyValue{attribute.Bool("link", true)}}
+	_, span := tracer.Start(ctx, "child",
+		trace.WithAttributes(attribute.String("key", "value"), attribute.Int64Slice("ints", []int64{1, 2})),
+		trace.WithLinks(link),
+	)
+	span.AddEvent("event", trace.WithAttributes(attribute.Int("n", 1)))
+	span.SetStatus(codes.Error, "failed")
+	span.End()
+
+	h := NewTracezHandler(zsp)
+
+	_, summary := serveTracez(t, h, "zformat=json")
+	buckets := summary["latencyBuckets"].([]interface{})
+	require.Len(t, buckets, defaultBoundaries.numBuckets())
+	assert.Equal(t, map[string]interface{}{"name": ">0s", "lowerBoundNanos": 0.0, "upperBoundNanos": 10e3}, buckets[0])
+	assert.Equal(t, map[string]interface{}{"name": ">1m40s", "lowerBoundNanos": 100e9}, buckets[len(buckets)-1])
+	names := summary["spanNames"].([]interface{})
+	require.Len(t, names, 2)
+	assert.Equal(t, "child", names[0].(map[string]interface{})["name"])
+	assert.Equal(t, 1.0, names[0].(map[string]interface{})["errors"])
+	assert.Equal(t, "parent", names[1].(map[string]interface{})["name"])
+	assert.Equal(t, 1.0, names[1].(map[string]interface{})["active"])
+	assert.Len(t, names[1].(map[string]interface{})["latency"], defaultBoundaries.numBuckets())
+
+	_, got := serveTracez(t, h, "zformat=json&zspanname=child&ztype=2")
+	assert.Equal(t, "child", got["name"])
+	assert.Equal(t, "error", got["type"])
+	assert.NotContains(t, got, "latencyBucket")
+	assert.Equal(t, 1.0, got["total"])
+	spans := got["spans"].([]interface{})
+	require.Len(t, spans, 1)
+	s := spans[0].(map[string]interface{})
+	assert.Equal(t, span.SpanContext().TraceID().String(), s["traceId"])
+	assert.Equal(t, span.SpanContext().SpanID().String(), s["spanId"])
+	assert.Equal(t, parent.SpanContext().SpanID().String(), s["parentSpanId"])
+	assert.Equal(t, "internal", s["kind"])
+	assert.Contains(t, s, "endTime")
+	assert.Equal(t, map[string]interface{}{"key": "value", "ints": []interface{}{1.0, 2.0}}, s["attributes"])
+	assert.Equal(t, map[string]interface{}{"code": "Error", "description": "failed"}, s["status"])
+	assert.Equal(t, map[string]interface{}{"attributes": map[string]interface{}{"service.name": "test"}}, s["resource"])
+	assert.Equal(t, map[string]interface{}{"name": "scope", "version": "v1"}, s["instrumentationScope"])
+	events := s["events"].([]interface{})
+	require.Len(t, events, 1)
+	assert.Equal(t, "event", events[0].(map[string]interface{})["name"])
+	assert.Equal(t, map[string]interface{}{"n": 1.0}, events[0].(map[string]interface{})["attributes"])
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"traceId":    parent.SpanContext().TraceID().String(),
+		"spanId":     parent.SpanContext().SpanID().String(),
+		"attributes": map[string]interface{}{"link": true},
+	}}, s["links"])
+
+	_, got = serveTracez(t, h, "zformat=json&zspanname=parent")
+	assert.Equal(t, "active", got["type"])
+	spans = got["spans"].([]interface{})
+	require.Len(t, spans, 1)
+	assert.Equal(t, "server", spans[0].(map[string]interface{})["kind"])
+	assert.NotContains(t, spans[0], "endTime")
+
+	_, got = serveTracez(t, h, "zformat=json&zspanname=child&ztype=1&zlatencybucket=3")
+	assert.Equal(t, "latency", got["type"])
+	assert.Equal(t, 3.0, got["latencyBucket"])
+	assert.Equal(t, []interface{}{}, got["spans"])
+}
+
+func TestTracezJSONLimit(t *testing.T) {
+	zsp := NewSpanProcessor()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(zsp))
+	tracer := tp.Tracer("test")
+	start := time.Unix(100, 0)
+	var spans []trace.Span
+	for i := 0; i < 3; i++ {
+		_, s := tracer.Start(context.Background(), "span", trace.WithTimestamp(start.Add(time.Duration(i)*time.Second)))
+		spans = append(spans, s)
+	}
+	defer func() {
+		for _, s := range spans {
+			s.End()
+		}
+	}()
+
+	_, got := serveTracez(t, NewTracezHandler(zsp), "zformat=json&zspanname=span&zlimit=2")
+	assert.Equal(t, 3.0, got["total"])
+	returned := got["spans"].([]interface{})
+	require.Len(t, returned, 2)
+	// Most recently started first.
+	assert.Equal(t, spans[2].SpanContext().SpanID().String(), returned[0].(map[string]interface{})["spanId"])
+	assert.Equal(t, spans[1].SpanContext().SpanID().String(), returned[1].(map[string]interface{})["spanId"])
+}
+
+func TestTracezJSONInvalidQuery(t *testing.T) {
+	h := NewTracezHandler(NewSpanProcessor())
+	for _, query := range []string{
+		"ztype=3",
+		"ztype=latency",
+		"ztype=1&zlatencybucket=-1",
+		"ztype=1&zlatencybucket=9",
+		"zlimit=-1",
+		"zlimit=ten",
+	} {
+		t.Run(query, func(t *testing.T) {
+			rec, _ := serveTracez(t, h, "zformat=json&zspanname=span&"+query)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+	}
+}